@@ -2,7 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/di"
@@ -22,15 +28,25 @@ func main() {
 	config.LoadEnv()
 	config.ConfigureLogger()
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	e := echo.New()
 	i := di.New()
 
+	ipExtractor, err := middlewares.IPExtractor(config.Env.API.TrustedProxies)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	e.IPExtractor = ipExtractor
+
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
 	middlewares.Cors(e)
+	middlewares.ClientInfo(e)
+	middlewares.CSRF(e)
 
 	DB, err := storage.NewPostgresStorage(ctx)
 	if err != nil {
@@ -41,29 +57,110 @@ func main() {
 		return DB, nil
 	})
 
+	redisClient, err := storage.NewRedisStorage(ctx)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	di.Provide(i, func(d *di.Injector) (storage.Cache, error) {
+		return storage.NewRedisCache(redisClient), nil
+	})
+
+	di.Provide(i, func(d *di.Injector) (storage.AttemptCounter, error) {
+		return storage.NewFallbackAttemptCounter(storage.NewRedisAttemptCounter(redisClient), storage.NewMemoryAttemptCounter()), nil
+	})
+
+	keySet, err := services.LoadKeySet(config.Env.JWT.KeysDir, config.Env.JWT.SigningKeyID)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	di.Provide(i, func(d *di.Injector) (*services.KeySet, error) {
+		return keySet, nil
+	})
+
+	objectStorage, err := storage.NewObjectStorage()
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	di.Provide(i, func(d *di.Injector) (storage.ObjectStorage, error) {
+		return objectStorage, nil
+	})
+
 	di.Provide(i, templates.NewTemplate)
 	di.Provide(i, email.NewEmailService)
+	di.Provide(i, email.NewEmailTransport)
+	di.Provide(i, email.NewOutboxWorker)
 
+	di.Provide(i, middlewares.NewAuthMiddleware)
+
+	di.Provide(i, handlers.NewAPIKeyHandler)
 	di.Provide(i, handlers.NewAuthHandler)
+	di.Provide(i, handlers.NewEmailHandler)
+	di.Provide(i, handlers.NewJWKSHandler)
 	di.Provide(i, handlers.NewOrderHandler)
 	di.Provide(i, handlers.NewRecipientHandler)
+	di.Provide(i, handlers.NewSessionHandler)
+	di.Provide(i, handlers.NewTwoFactorHandler)
 	di.Provide(i, handlers.NewUserHandler)
 
+	di.Provide(i, services.NewAPIKeyService)
 	di.Provide(i, services.NewAuthService)
+	di.Provide(i, services.NewEmailOutboxService)
+	di.Provide(i, services.NewEmailTemplateService)
 	di.Provide(i, services.NewFileService)
+	di.Provide(i, services.NewLoginGuard)
 	di.Provide(i, services.NewOrderService)
 	di.Provide(i, services.NewRecipientService)
 	di.Provide(i, services.NewSecureService)
+	di.Provide(i, services.NewSessionService)
 	di.Provide(i, services.NewTokenService)
+	di.Provide(i, services.NewTwoFactorService)
 	di.Provide(i, services.NewUserService)
 
+	di.Provide(i, repositories.NewActivationTokenRepository)
+	di.Provide(i, repositories.NewAPIKeyRepository)
+	di.Provide(i, repositories.NewAuditLogRepository)
+	di.Provide(i, repositories.NewDeliveryAttemptRepository)
+	di.Provide(i, repositories.NewEmailOutboxRepository)
+	di.Provide(i, repositories.NewOrderEventRepository)
 	di.Provide(i, repositories.NewOrderRepository)
 	di.Provide(i, repositories.NewRecipientRepository)
+	di.Provide(i, repositories.NewRecoveryCodeRepository)
+	di.Provide(i, repositories.NewTransactionManager)
 	di.Provide(i, repositories.NewUserRepository)
 
 	if err := handlers.SetupRoutes(e, i); err != nil {
 		e.Logger.Fatal(err)
 	}
 
-	e.Logger.Fatal(e.Start(fmt.Sprintf(":%d", config.Env.API.Port)))
+	outboxWorker, err := di.Invoke[email.OutboxWorker](i)
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		outboxWorker.Run(ctx)
+	}()
+
+	go func() {
+		if err := e.Start(fmt.Sprintf(":%d", config.Env.API.Port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		e.Logger.Error(err)
+	}
+
+	// The worker stops after the email it is sending, so its result is saved.
+	<-workerDone
 }
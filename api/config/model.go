@@ -1,11 +1,22 @@
 package config
 
 type Environment struct {
-	Postgres Postgres
-	Redis    Redis
-	API      API
-	Session  Session
-	SMTP     SMTP
+	Postgres      Postgres
+	Redis         Redis
+	API           API
+	Session       Session
+	JWT           JWT
+	Activation    Activation
+	PasswordReset PasswordReset
+	Login         Login
+	TwoFactor     TwoFactor
+	SMTP          SMTP
+	Mail          Mail
+	Order         Order
+	Storage       Storage
+	Image         Image
+	Outbox        Outbox
+	S3            S3
 }
 
 type Postgres struct {
@@ -31,17 +42,110 @@ type Redis struct {
 
 type API struct {
 	Port int `env:"API_PORT,default=8080"`
+	// TrustedProxies lists the IPs or CIDR ranges of the proxies allowed to
+	// set X-Forwarded-For. When empty, the connection IP is the client IP.
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
 }
 
 type Session struct {
-	TokenExp   int    `env:"TOKEN_EXP,default=6"`
-	JWTSecret  string `env:"JWT_SECRET"`
-	CookieName string `env:"COOKIE_NAME"`
+	AccessTokenExp    int    `env:"ACCESS_TOKEN_EXP,default=15"`
+	RefreshTokenExp   int    `env:"REFRESH_TOKEN_EXP,default=720"`
+	CookieName        string `env:"COOKIE_NAME"`
+	RefreshCookieName string `env:"REFRESH_COOKIE_NAME,default=fast-feet.refresh-token"`
+	CSRFCookieName    string `env:"CSRF_COOKIE_NAME,default=fast-feet.csrf-token"`
+	CookieDomain      string `env:"COOKIE_DOMAIN"`
+	CookieSecure      bool   `env:"COOKIE_SECURE,default=true"`
+	CookieSameSite    string `env:"COOKIE_SAME_SITE,default=lax"`
+}
+
+// JWT points at the directory of <kid>.pem key files and at the kid of the
+// key that signs new tokens. The other keys only verify.
+type JWT struct {
+	KeysDir      string `env:"JWT_KEYS_DIR,default=./keys"`
+	SigningKeyID string `env:"JWT_SIGNING_KEY_ID"`
+}
+
+type Activation struct {
+	URL      string `env:"ACTIVATION_URL,default=http://localhost:5173/activate"`
+	TokenExp int    `env:"ACTIVATION_TOKEN_EXP,default=48"`
+}
+
+type PasswordReset struct {
+	URL      string `env:"PASSWORD_RESET_URL,default=http://localhost:5173/reset-password"`
+	TokenExp int    `env:"PASSWORD_RESET_TOKEN_EXP,default=30"`
+}
+
+// Login holds the brute-force protection limits. Windows and lockouts are in
+// minutes, delays in milliseconds.
+type Login struct {
+	MaxAttempts      int `env:"LOGIN_MAX_ATTEMPTS,default=5"`
+	MaxAttemptsPerIP int `env:"LOGIN_MAX_ATTEMPTS_PER_IP,default=20"`
+	AttemptWindow    int `env:"LOGIN_ATTEMPT_WINDOW,default=15"`
+	LockoutDuration  int `env:"LOGIN_LOCKOUT_DURATION,default=15"`
+	BaseDelay        int `env:"LOGIN_BASE_DELAY,default=250"`
+	MaxDelay         int `env:"LOGIN_MAX_DELAY,default=4000"`
+}
+
+// TwoFactor configures TOTP. Users whose role is listed in RequiredRoles
+// (separated by "|") must enroll before they can log in; the other admins and
+// owners may opt in. Expirations are in minutes.
+type TwoFactor struct {
+	Issuer        string   `env:"TWO_FACTOR_ISSUER,default=Fast Feet"`
+	RequiredRoles []string `env:"TWO_FACTOR_REQUIRED_ROLES"`
+	ChallengeExp  int      `env:"TWO_FACTOR_CHALLENGE_EXP,default=5"`
+	EnrollmentExp int      `env:"TWO_FACTOR_ENROLLMENT_EXP,default=10"`
 }
 
 type SMTP struct {
-	Host     string `env:"SMTP_HOST"`
-	Port     int    `env:"SMTP_PORT"`
-	User     string `env:"SMTP_USER"`
-	Password string `env:"SMTP_PASSWORD"`
+	Host        string `env:"SMTP_HOST"`
+	Port        int    `env:"SMTP_PORT"`
+	User        string `env:"SMTP_USER"`
+	Password    string `env:"SMTP_PASSWORD"`
+	PoolSize    int    `env:"SMTP_POOL_SIZE,default=2"`
+	IdleTimeout int    `env:"SMTP_IDLE_TIMEOUT,default=30"`
+}
+
+type Mail struct {
+	Transport string `env:"EMAIL_TRANSPORT,default=smtp"`
+	From      string `env:"EMAIL_FROM"`
+	FilePath  string `env:"EMAIL_FILE_PATH,default=./mails"`
+}
+
+type Outbox struct {
+	Interval    int `env:"EMAIL_OUTBOX_INTERVAL,default=5"`
+	BatchSize   int `env:"EMAIL_OUTBOX_BATCH_SIZE,default=20"`
+	MaxAttempts int `env:"EMAIL_OUTBOX_MAX_ATTEMPTS,default=8"`
+	BackoffBase int `env:"EMAIL_OUTBOX_BACKOFF_BASE,default=30"`
+	BackoffMax  int `env:"EMAIL_OUTBOX_BACKOFF_MAX,default=3600"`
+	// Lease is how long, in seconds, a worker may take to send a claimed
+	// batch before another worker picks it up again.
+	Lease int `env:"EMAIL_OUTBOX_LEASE,default=300"`
+}
+
+type Order struct {
+	MaxDeliveryAttempts int `env:"ORDER_MAX_DELIVERY_ATTEMPTS,default=3"`
+}
+
+type Storage struct {
+	Driver       string `env:"STORAGE_DRIVER,default=local"`
+	LocalPath    string `env:"STORAGE_LOCAL_PATH,default=./uploads"`
+	SignedURLExp int    `env:"STORAGE_SIGNED_URL_EXP,default=300"`
+}
+
+type Image struct {
+	MaxDimension       int `env:"IMAGE_MAX_DIMENSION,default=1920"`
+	ThumbnailDimension int `env:"IMAGE_THUMBNAIL_DIMENSION,default=320"`
+	JPEGQuality        int `env:"IMAGE_JPEG_QUALITY,default=85"`
+	// MaxPixels bounds width × height of an upload, since decoding allocates
+	// memory for every pixel regardless of the file size.
+	MaxPixels int `env:"IMAGE_MAX_PIXELS,default=40000000"`
+}
+
+type S3 struct {
+	Endpoint     string `env:"S3_ENDPOINT"`
+	Region       string `env:"S3_REGION,default=us-east-1"`
+	Bucket       string `env:"S3_BUCKET"`
+	AccessKey    string `env:"S3_ACCESS_KEY"`
+	SecretKey    string `env:"S3_SECRET_KEY"`
+	UsePathStyle bool   `env:"S3_USE_PATH_STYLE,default=true"`
 }
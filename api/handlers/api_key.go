@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/responses"
+	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/G-Villarinho/fast-feet-api/validators"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler interface {
+	CreateAPIKey(ectx echo.Context) error
+	GetAPIKeys(ectx echo.Context) error
+	RevokeAPIKey(ectx echo.Context) error
+}
+
+type apiKeyHandler struct {
+	i   *di.Injector
+	aks services.APIKeyService
+}
+
+func NewAPIKeyHandler(i *di.Injector) (APIKeyHandler, error) {
+	aks, err := di.Invoke[services.APIKeyService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke api key service: %w", err)
+	}
+
+	return &apiKeyHandler{
+		i:   i,
+		aks: aks,
+	}, nil
+}
+
+func (a *apiKeyHandler) CreateAPIKey(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "api-key"),
+		slog.String("func", "CreateAPIKey"),
+	)
+
+	var payload models.CreateAPIKeyPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := a.aks.CreateAPIKey(ectx.Request().Context(), payload)
+	if err != nil {
+		log.Error(err.Error())
+		return apiKeyErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusCreated, response)
+}
+
+func (a *apiKeyHandler) GetAPIKeys(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "api-key"),
+		slog.String("func", "GetAPIKeys"),
+	)
+
+	response, err := a.aks.GetAPIKeys(ectx.Request().Context())
+	if err != nil {
+		log.Error(err.Error())
+		return apiKeyErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (a *apiKeyHandler) RevokeAPIKey(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "api-key"),
+		slog.String("func", "RevokeAPIKey"),
+	)
+
+	apiKeyID, err := uuid.Parse(ectx.Param("apiKeyId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de chave de API inválido.")
+	}
+
+	if err := a.aks.RevokeAPIKey(ectx.Request().Context(), apiKeyID); err != nil {
+		log.Error(err.Error())
+		return apiKeyErrorResponse(ectx, err)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func apiKeyErrorResponse(ectx echo.Context, err error) error {
+	if errors.Is(err, models.ErrUserNotFoundInContext) || errors.Is(err, models.ErrUserNotFound) {
+		return responses.AccessDeniedAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrInsufficientPermission) {
+		return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrAPIKeyNotFound) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Chave de API não encontrada.")
+	}
+
+	if errors.Is(err, models.ErrAPIKeyScopeUnsupported) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Um dos escopos informados não é aceito por chaves de API.")
+	}
+
+	if errors.Is(err, models.ErrAPIKeyExpirationInvalid) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A data de expiração da chave de API deve estar no futuro.")
+	}
+
+	return responses.InternalServerAPIErrorResponse(ectx)
+}
@@ -3,15 +3,19 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/middlewares"
 	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
 	"github.com/G-Villarinho/fast-feet-api/responses"
 	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/G-Villarinho/fast-feet-api/utils"
 	"github.com/G-Villarinho/fast-feet-api/validators"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/labstack/echo/v4"
@@ -19,7 +23,14 @@ import (
 
 type AuthHandler interface {
 	Login(ectx echo.Context) error
+	StartTwoFactorEnrollment(ectx echo.Context) error
+	VerifyTwoFactor(ectx echo.Context) error
 	Logout(ectx echo.Context) error
+	Refresh(ectx echo.Context) error
+	ActivateAccount(ectx echo.Context) error
+	ChangePassword(ectx echo.Context) error
+	ForgotPassword(ectx echo.Context) error
+	ResetPassword(ectx echo.Context) error
 }
 
 type authHandler struct {
@@ -60,7 +71,7 @@ func (a *authHandler) Login(ectx echo.Context) error {
 		return responses.NewValidationErrorResponse(ectx, validationErrors)
 	}
 
-	response, err := a.as.Login(ectx.Request().Context(), payload)
+	result, err := a.as.Login(ectx.Request().Context(), payload)
 	if err != nil {
 		log.Error(err.Error())
 
@@ -72,28 +83,391 @@ func (a *authHandler) Login(ectx echo.Context) error {
 			return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "Sua conta está temporariamente bloqueada. Entre em contato com o suporte para mais informações.")
 		}
 
+		if errors.Is(err, models.ErrAccountLocked) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusLocked, "Sua conta foi bloqueada temporariamente após várias tentativas de login sem sucesso. Tente novamente mais tarde ou redefina sua senha.")
+		}
+
+		if errors.Is(err, models.ErrTooManyLoginAttempts) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusTooManyRequests, "Muitas tentativas de login. Aguarde alguns minutos e tente novamente.")
+		}
+
 		return responses.InternalServerAPIErrorResponse(ectx)
 	}
 
-	ectx.SetCookie(&http.Cookie{
-		Name:     config.Env.Session.CookieName,
-		Value:    response.Token,
-		HttpOnly: true,
-		Path:     "/",
-	})
+	if result.Challenge != nil {
+		return ectx.JSON(http.StatusAccepted, result.Challenge)
+	}
+
+	return respondWithSession(ectx, result.Session, ectx.QueryParam("mode") == bearerMode, http.StatusOK)
+}
+
+// StartTwoFactorEnrollment returns the secret a user whose role requires
+// two-factor authentication must add to an authenticator before finishing
+// their login.
+func (a *authHandler) StartTwoFactorEnrollment(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "auth"),
+		slog.String("func", "StartTwoFactorEnrollment"),
+	)
+
+	var payload models.TwoFactorChallengePayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := a.as.StartTwoFactorEnrollment(ectx.Request().Context(), payload)
+	if err != nil {
+		log.Error(err.Error())
+		return twoFactorErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+// VerifyTwoFactor finishes a login by answering its challenge. The session is
+// handed over like in Login, including the "mode" query parameter.
+func (a *authHandler) VerifyTwoFactor(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "auth"),
+		slog.String("func", "VerifyTwoFactor"),
+	)
+
+	var payload models.VerifyTwoFactorPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := a.as.VerifyTwoFactor(ectx.Request().Context(), payload)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrAccountLocked) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusLocked, "Sua conta foi bloqueada temporariamente após várias tentativas de login sem sucesso. Tente novamente mais tarde ou redefina sua senha.")
+		}
+
+		if errors.Is(err, models.ErrUserBlocked) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "Sua conta está temporariamente bloqueada. Entre em contato com o suporte para mais informações.")
+		}
+
+		return twoFactorErrorResponse(ectx, err)
+	}
+
+	if ectx.QueryParam("mode") == bearerMode {
+		return ectx.JSON(http.StatusOK, response)
+	}
+
+	if err := setSessionCookies(ectx, &response.LoginResponse); err != nil {
+		log.Error("set session cookies", slog.String("error", err.Error()))
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	// Recovery codes from a finished enrollment are shown only this once.
+	if len(response.RecoveryCodes) > 0 {
+		return ectx.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: response.RecoveryCodes})
+	}
 
 	return ectx.NoContent(http.StatusOK)
 }
 
 func (a *authHandler) Logout(ectx echo.Context) error {
-	ectx.SetCookie(&http.Cookie{
-		Name:     config.Env.Session.CookieName,
-		Value:    "",
-		HttpOnly: true,
-		Path:     "/",
-		Expires:  time.Unix(0, 0),
-		MaxAge:   -1,
-	})
+	log := slog.With(
+		slog.String("handler", "auth"),
+		slog.String("func", "Logout"),
+	)
+
+	// The cookies are cleared whatever happens to the session, so the browser
+	// is always left logged out.
+	clearSessionCookies(ectx)
+
+	accessToken, _, _ := middlewares.ExtractToken(ectx)
+
+	var payload models.RefreshTokenPayload
+	if cookie, err := ectx.Cookie(config.Env.Session.RefreshCookieName); err == nil && cookie.Value != "" {
+		payload.RefreshToken = cookie.Value
+	} else if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+	}
+
+	if err := a.as.Logout(ectx.Request().Context(), accessToken, payload.RefreshToken); err != nil && !errors.Is(err, models.ErrSessionNotFound) {
+		log.Error(err.Error())
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
 
 	return ectx.NoContent(http.StatusOK)
 }
+
+// Refresh rotates the refresh token. Browsers send it in a cookie and get the
+// new pair back in cookies; other clients send it in the body and get the new
+// pair in the response.
+func (a *authHandler) Refresh(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "auth"),
+		slog.String("func", "Refresh"),
+	)
+
+	var payload models.RefreshTokenPayload
+	fromCookie := false
+
+	if cookie, err := ectx.Cookie(config.Env.Session.RefreshCookieName); err == nil && cookie.Value != "" {
+		payload.RefreshToken = cookie.Value
+		fromCookie = true
+	} else if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := a.as.Refresh(ectx.Request().Context(), payload)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrRefreshTokenInvalid) {
+			clearSessionCookies(ectx)
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusUnauthorized, "Sua sessão expirou. Faça login novamente.")
+		}
+
+		if errors.Is(err, models.ErrRefreshTokenReused) {
+			clearSessionCookies(ectx)
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusUnauthorized, "Sua sessão foi encerrada por segurança. Faça login novamente.")
+		}
+
+		if errors.Is(err, models.ErrUserBlocked) {
+			clearSessionCookies(ectx)
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "Sua conta está temporariamente bloqueada. Entre em contato com o suporte para mais informações.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return respondWithSession(ectx, response, !fromCookie, http.StatusNoContent)
+}
+
+func (a *authHandler) ActivateAccount(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "auth"),
+		slog.String("func", "ActivateAccount"),
+	)
+
+	var payload models.ActivateAccountPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	if err := a.as.ActivateAccount(ectx.Request().Context(), payload); err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrActivationTokenInvalid) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "O link de ativação é inválido ou já foi utilizado.")
+		}
+
+		if errors.Is(err, models.ErrActivationTokenExpired) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusGone, "O link de ativação expirou. Solicite um novo link ao administrador.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func (a *authHandler) ChangePassword(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "auth"),
+		slog.String("func", "ChangePassword"),
+	)
+
+	var payload models.ChangePasswordPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := a.as.ChangePassword(ectx.Request().Context(), payload)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrInvalidPassword) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A senha atual está incorreta.")
+		}
+
+		if errors.Is(err, models.ErrSamePassword) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A nova senha deve ser diferente da senha atual.")
+		}
+
+		if errors.Is(err, models.ErrUserNotFound) || errors.Is(err, models.ErrUserNotFoundInContext) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	method, _ := request.AuthenticatedWith(ectx.Request().Context())
+
+	return respondWithSession(ectx, response, method == request.BearerAuth, http.StatusNoContent)
+}
+
+func (a *authHandler) ForgotPassword(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "auth"),
+		slog.String("func", "ForgotPassword"),
+	)
+
+	var payload models.ForgotPasswordPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	if err := a.as.ForgotPassword(ectx.Request().Context(), payload); err != nil {
+		log.Error(err.Error())
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.NoContent(http.StatusAccepted)
+}
+
+func (a *authHandler) ResetPassword(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "auth"),
+		slog.String("func", "ResetPassword"),
+	)
+
+	var payload models.ResetPasswordPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	if err := a.as.ResetPassword(ectx.Request().Context(), payload); err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrResetTokenInvalid) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "O link de redefinição de senha é inválido ou expirou. Solicite um novo link.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+// bearerMode is the value of the login "mode" query parameter that asks for
+// the token pair in the response body instead of cookies.
+const bearerMode = "bearer"
+
+// respondWithSession hands a new token pair to the client: in the body for
+// bearer clients, in cookies for browsers.
+func respondWithSession(ectx echo.Context, response *models.LoginResponse, bearer bool, cookieStatus int) error {
+	if bearer {
+		return ectx.JSON(http.StatusOK, response)
+	}
+
+	if err := setSessionCookies(ectx, response); err != nil {
+		slog.Error("set session cookies", slog.String("error", err.Error()))
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.NoContent(cookieStatus)
+}
+
+// refreshCookiePath scopes the refresh token cookie to the endpoints that read
+// it: refresh and logout.
+const refreshCookiePath = "/v1/auth"
+
+// legacyRefreshCookiePath is where refresh cookies used to be set. Browsers
+// would send such a cookie to the refresh endpoint before the current one, so
+// it is expired whenever the session cookies are written.
+const legacyRefreshCookiePath = "/v1/auth/refresh"
+
+// setSessionCookies stores the token pair in HttpOnly cookies and starts a new
+// CSRF token. The CSRF cookie stays readable by scripts, which send it back
+// in the X-CSRF-Token header; it is also returned in that header for clients
+// served from another domain.
+func setSessionCookies(ectx echo.Context, response *models.LoginResponse) error {
+	csrfToken, err := middlewares.NewCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	maxAge := config.Env.Session.RefreshTokenExp * int(time.Hour/time.Second)
+
+	ectx.SetCookie(utils.NewCookie(config.Env.Session.CookieName, response.Token, "/", 0, true))
+	ectx.SetCookie(utils.ExpiredCookie(config.Env.Session.RefreshCookieName, legacyRefreshCookiePath, true))
+	ectx.SetCookie(utils.NewCookie(config.Env.Session.RefreshCookieName, response.RefreshToken, refreshCookiePath, maxAge, true))
+	ectx.SetCookie(utils.NewCookie(config.Env.Session.CSRFCookieName, csrfToken, "/", maxAge, false))
+	ectx.Response().Header().Set(middlewares.CSRFHeader, csrfToken)
+
+	return nil
+}
+
+func clearSessionCookies(ectx echo.Context) {
+	ectx.SetCookie(utils.ExpiredCookie(config.Env.Session.CookieName, "/", true))
+	ectx.SetCookie(utils.ExpiredCookie(config.Env.Session.RefreshCookieName, legacyRefreshCookiePath, true))
+	ectx.SetCookie(utils.ExpiredCookie(config.Env.Session.RefreshCookieName, refreshCookiePath, true))
+	ectx.SetCookie(utils.ExpiredCookie(config.Env.Session.CSRFCookieName, "/", false))
+}
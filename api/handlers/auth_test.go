@@ -3,11 +3,14 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
 	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/middlewares"
 	"github.com/G-Villarinho/fast-feet-api/mocks"
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/labstack/echo/v4"
@@ -99,9 +102,11 @@ func TestAuthHandler_Login(t *testing.T) {
 		mockAuthService := new(mocks.AuthService)
 		handler := &authHandler{as: mockAuthService}
 
-		mockAuthService.On("Login", mock.Anything, mock.Anything).Return(&models.LoginResponse{Token: "valid-token"}, nil)
+		mockAuthService.On("Login", mock.Anything, mock.Anything).
+			Return(&models.LoginResult{Session: &models.LoginResponse{Token: "valid-token"}}, nil)
 
 		config.Env.Session.CookieName = "fast-feet.token"
+		config.Env.Session.CSRFCookieName = "fast-feet.csrf-token"
 
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(validLoginPayload))
@@ -124,7 +129,62 @@ func TestAuthHandler_Login(t *testing.T) {
 
 		assert.NotNil(t, tokenCookie)
 		assert.Equal(t, "valid-token", tokenCookie.Value)
+		assert.True(t, tokenCookie.HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, tokenCookie.SameSite)
 
+		var csrfCookie *http.Cookie
+		for _, cookie := range cookies {
+			if cookie.Name == config.Env.Session.CSRFCookieName {
+				csrfCookie = cookie
+			}
+		}
+
+		assert.NotNil(t, csrfCookie)
+		assert.False(t, csrfCookie.HttpOnly)
+		assert.Equal(t, csrfCookie.Value, rec.Header().Get(middlewares.CSRFHeader))
+
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("WhenBearerModeIsRequested_ShouldReturnTokenInBody", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("Login", mock.Anything, mock.Anything).
+			Return(&models.LoginResult{Session: &models.LoginResponse{Token: "valid-token", RefreshToken: "refresh-token", ExpiresIn: 900}}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/login?mode=bearer", strings.NewReader(validLoginPayload))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.Login(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"token": "valid-token", "refreshToken": "refresh-token", "expiresIn": 900}`, rec.Body.String())
+		assert.Empty(t, rec.Result().Cookies())
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("WhenSecondFactorIsNeeded_ShouldReturnChallengeWithoutCookies", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("Login", mock.Anything, mock.Anything).
+			Return(&models.LoginResult{Challenge: &models.TwoFactorChallengeResponse{ChallengeToken: "challenge-token", ExpiresIn: 300}}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(validLoginPayload))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.Login(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.JSONEq(t, `{"challengeToken": "challenge-token", "enrollmentRequired": false, "expiresIn": 300}`, rec.Body.String())
+		assert.Empty(t, rec.Result().Cookies())
 		mockAuthService.AssertExpectations(t)
 	})
 
@@ -146,3 +206,238 @@ func TestAuthHandler_Login(t *testing.T) {
 		mockAuthService.AssertExpectations(t)
 	})
 }
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	config.Env.Session.CookieName = "fast-feet.token"
+	config.Env.Session.RefreshCookieName = "fast-feet.refresh-token"
+
+	t.Run("WhenRefreshTokenComesFromCookie_ShouldRotateCookies", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("Refresh", mock.Anything, models.RefreshTokenPayload{RefreshToken: "old-refresh-token"}).
+			Return(&models.LoginResponse{Token: "new-token", RefreshToken: "new-refresh-token"}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/refresh", nil)
+		req.AddCookie(&http.Cookie{Name: "fast-feet.refresh-token", Value: "old-refresh-token"})
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.Refresh(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Body.String())
+
+		values := make(map[string]string)
+		for _, cookie := range rec.Result().Cookies() {
+			values[cookie.Name] = cookie.Value
+		}
+
+		assert.Equal(t, "new-token", values["fast-feet.token"])
+		assert.Equal(t, "new-refresh-token", values["fast-feet.refresh-token"])
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("WhenRefreshTokenComesFromBody_ShouldReturnTokenPair", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("Refresh", mock.Anything, models.RefreshTokenPayload{RefreshToken: "old-refresh-token"}).
+			Return(&models.LoginResponse{Token: "new-token", RefreshToken: "new-refresh-token", ExpiresIn: 900}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/refresh", strings.NewReader(`{"refreshToken": "old-refresh-token"}`))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.Refresh(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"token": "new-token", "refreshToken": "new-refresh-token", "expiresIn": 900}`, rec.Body.String())
+		assert.Empty(t, rec.Result().Cookies())
+	})
+
+	t.Run("WhenRefreshTokenIsReused_ShouldClearCookiesAndReturnUnauthorized", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("Refresh", mock.Anything, mock.Anything).
+			Return(nil, models.ErrRefreshTokenReused)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/refresh", nil)
+		req.AddCookie(&http.Cookie{Name: "fast-feet.refresh-token", Value: "stolen-refresh-token"})
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.Refresh(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		for _, cookie := range rec.Result().Cookies() {
+			assert.Empty(t, cookie.Value)
+		}
+	})
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	config.Env.Session.CookieName = "fast-feet.token"
+	config.Env.Session.RefreshCookieName = "fast-feet.refresh-token"
+	config.Env.Session.CSRFCookieName = "fast-feet.csrf"
+
+	// browserCookies returns the cookies a browser holding a fresh session
+	// would attach to a request for target, honoring their paths.
+	browserCookies := func(t *testing.T, target string) []*http.Cookie {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(httptest.NewRequest(http.MethodPost, "/v1/login", nil), rec)
+
+		err := setSessionCookies(ectx, &models.LoginResponse{Token: "expired-token", RefreshToken: "refresh-token"})
+		assert.NoError(t, err)
+
+		jar, err := cookiejar.New(nil)
+		assert.NoError(t, err)
+
+		loginURL, _ := url.Parse("https://api.example.com/v1/login")
+		jar.SetCookies(loginURL, rec.Result().Cookies())
+
+		targetURL, _ := url.Parse("https://api.example.com" + target)
+		return jar.Cookies(targetURL)
+	}
+
+	t.Run("WhenAccessTokenExpired_ShouldRevokeByRefreshCookieAndClearCookies", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("Logout", mock.Anything, "expired-token", "refresh-token").
+			Return(nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/logout", nil)
+		for _, cookie := range browserCookies(t, "/v1/auth/logout") {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.Logout(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		cleared := make(map[string]bool)
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Path != legacyRefreshCookiePath {
+				cleared[cookie.Name] = cookie.Value == "" && cookie.MaxAge < 0
+			}
+		}
+
+		assert.True(t, cleared["fast-feet.token"])
+		assert.True(t, cleared["fast-feet.refresh-token"])
+		assert.True(t, cleared["fast-feet.csrf"])
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("WhenRefreshTokenComesFromBody_ShouldRevokeItsSession", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("Logout", mock.Anything, "", "refresh-token").
+			Return(nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/logout", strings.NewReader(`{"refreshToken": "refresh-token"}`))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.Logout(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("WhenRevokingFails_ShouldStillClearCookies", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("Logout", mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.New("redis unavailable"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/logout", nil)
+		for _, cookie := range browserCookies(t, "/v1/auth/logout") {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.Logout(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		for _, cookie := range rec.Result().Cookies() {
+			assert.Empty(t, cookie.Value)
+		}
+	})
+}
+
+func TestAuthHandler_VerifyTwoFactor(t *testing.T) {
+	config.Env.Session.CookieName = "fast-feet.token"
+	config.Env.Session.RefreshCookieName = "fast-feet.refresh-token"
+
+	const payload = `{"challengeToken": "challenge-token", "code": "123456"}`
+
+	t.Run("WhenEnrollmentCompletes_ShouldSetCookiesAndReturnRecoveryCodes", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("VerifyTwoFactor", mock.Anything, models.VerifyTwoFactorPayload{ChallengeToken: "challenge-token", Code: "123456"}).
+			Return(&models.TwoFactorLoginResponse{
+				LoginResponse: models.LoginResponse{Token: "valid-token", RefreshToken: "refresh-token"},
+				RecoveryCodes: []string{"abcde-fghjk"},
+			}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/login/2fa", strings.NewReader(payload))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.VerifyTwoFactor(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"recoveryCodes": ["abcde-fghjk"]}`, rec.Body.String())
+
+		values := make(map[string]string)
+		for _, cookie := range rec.Result().Cookies() {
+			values[cookie.Name] = cookie.Value
+		}
+
+		assert.Equal(t, "valid-token", values["fast-feet.token"])
+		mockAuthService.AssertExpectations(t)
+	})
+
+	t.Run("WhenCodeIsInvalid_ShouldReturnBadRequest", func(t *testing.T) {
+		mockAuthService := new(mocks.AuthService)
+		handler := &authHandler{as: mockAuthService}
+
+		mockAuthService.On("VerifyTwoFactor", mock.Anything, mock.Anything).
+			Return(nil, models.ErrInvalidTwoFactorCode)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/login/2fa", strings.NewReader(payload))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.VerifyTwoFactor(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Código de verificação inválido.")
+		assert.Empty(t, rec.Result().Cookies())
+	})
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/responses"
+	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+)
+
+type EmailHandler interface {
+	GetEmails(ectx echo.Context) error
+	RetryEmail(ectx echo.Context) error
+	PreviewTemplate(ectx echo.Context) error
+	SendTestEmail(ectx echo.Context) error
+}
+
+type emailHandler struct {
+	i   *di.Injector
+	eos services.EmailOutboxService
+	ets services.EmailTemplateService
+}
+
+func NewEmailHandler(i *di.Injector) (EmailHandler, error) {
+	eos, err := di.Invoke[services.EmailOutboxService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email outbox service: %w", err)
+	}
+
+	ets, err := di.Invoke[services.EmailTemplateService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email template service: %w", err)
+	}
+
+	return &emailHandler{
+		i:   i,
+		eos: eos,
+		ets: ets,
+	}, nil
+}
+
+func (e *emailHandler) GetEmails(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "email"),
+		slog.String("func", "GetEmails"),
+	)
+
+	pagination := &models.EmailOutboxPagination{
+		Pagination: *models.NewPagination(ectx.QueryParam("page"), ectx.QueryParam("limit")),
+	}
+
+	if status := ectx.QueryParam("status"); status != "" {
+		emailStatus := models.EmailOutboxStatus(strings.ToUpper(status))
+		pagination.Status = &emailStatus
+	}
+
+	response, err := e.eos.GetEmails(ectx.Request().Context(), pagination)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) || errors.Is(err, models.ErrUserNotFound) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (e *emailHandler) RetryEmail(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "email"),
+		slog.String("func", "RetryEmail"),
+	)
+
+	emailID, err := uuid.Parse(ectx.Param("emailId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de e-mail inválido.")
+	}
+
+	if err := e.eos.RetryEmail(ectx.Request().Context(), emailID); err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) || errors.Is(err, models.ErrUserNotFound) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrEmailNotFound) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado nenhum e-mail.")
+		}
+
+		if errors.Is(err, models.ErrEmailAlreadySent) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Este e-mail já foi enviado e não pode ser reenviado.")
+		}
+
+		if errors.Is(err, models.ErrEmailSending) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Este e-mail está sendo enviado neste momento.")
+		}
+
+		if errors.Is(err, models.ErrEmailRedacted) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Este e-mail continha um link de uso único e não pode ser reenviado. Solicite um novo envio.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.NoContent(http.StatusAccepted)
+}
+
+func (e *emailHandler) PreviewTemplate(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "email"),
+		slog.String("func", "PreviewTemplate"),
+	)
+
+	params := make(map[string]string)
+	for key, values := range ectx.QueryParams() {
+		if key != "format" && len(values) > 0 {
+			params[key] = values[0]
+		}
+	}
+
+	content, err := e.ets.PreviewTemplate(ectx.Request().Context(), templates.TemplateName(ectx.Param("name")), params)
+	if err != nil {
+		log.Error(err.Error())
+		return templateErrorResponse(ectx, err)
+	}
+
+	if ectx.QueryParam("format") == "text" {
+		return ectx.String(http.StatusOK, content.Text)
+	}
+
+	return ectx.HTML(http.StatusOK, content.HTML)
+}
+
+func (e *emailHandler) SendTestEmail(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "email"),
+		slog.String("func", "SendTestEmail"),
+	)
+
+	// The body is optional: without it the sample params are used.
+	params := make(map[string]string)
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if err := e.ets.SendTestEmail(ectx.Request().Context(), templates.TemplateName(ectx.Param("name")), params); err != nil {
+		log.Error(err.Error())
+		return templateErrorResponse(ectx, err)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func templateErrorResponse(ectx echo.Context, err error) error {
+	if errors.Is(err, models.ErrUserNotFoundInContext) || errors.Is(err, models.ErrUserNotFound) {
+		return responses.AccessDeniedAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrInsufficientPermission) {
+		return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, templates.ErrTemplateNotFound) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado nenhum template de e-mail com esse nome.")
+	}
+
+	if errors.Is(err, templates.ErrInvalidParam) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro inválido para este template de e-mail.")
+	}
+
+	return responses.InternalServerAPIErrorResponse(ectx)
+}
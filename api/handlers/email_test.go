@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEmailHandler_PreviewTemplate(t *testing.T) {
+	t.Run("ShouldRenderHTMLWithSuppliedParams", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/v1/admin/email-templates/pick-up-template/preview?recipientName=Ana", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("name")
+		ectx.SetParamValues("pick-up-template")
+
+		mockEmailTemplateService := new(mocks.EmailTemplateService)
+		handler := &emailHandler{ets: mockEmailTemplateService}
+
+		mockEmailTemplateService.On("PreviewTemplate", mock.Anything, templates.PickUpTemplate, map[string]string{"recipientName": "Ana"}).
+			Return(&templates.RenderedTemplate{HTML: "<h2>Olá Ana,</h2>", Text: "Olá Ana,"}, nil)
+
+		err := handler.PreviewTemplate(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMETextHTML)
+		assert.Equal(t, "<h2>Olá Ana,</h2>", rec.Body.String())
+	})
+
+	t.Run("WhenFormatIsText_ShouldRenderPlaintext", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/v1/admin/email-templates/pick-up-template/preview?format=text", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("name")
+		ectx.SetParamValues("pick-up-template")
+
+		mockEmailTemplateService := new(mocks.EmailTemplateService)
+		handler := &emailHandler{ets: mockEmailTemplateService}
+
+		mockEmailTemplateService.On("PreviewTemplate", mock.Anything, templates.PickUpTemplate, map[string]string{}).
+			Return(&templates.RenderedTemplate{HTML: "<h2>Olá Maria,</h2>", Text: "Olá Maria,"}, nil)
+
+		err := handler.PreviewTemplate(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Olá Maria,", rec.Body.String())
+	})
+
+	t.Run("WhenTemplateNotFound_ShouldReturnNotFound", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/v1/admin/email-templates/unknown/preview", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("name")
+		ectx.SetParamValues("unknown")
+
+		mockEmailTemplateService := new(mocks.EmailTemplateService)
+		handler := &emailHandler{ets: mockEmailTemplateService}
+
+		mockEmailTemplateService.On("PreviewTemplate", mock.Anything, templates.TemplateName("unknown"), map[string]string{}).
+			Return(nil, templates.ErrTemplateNotFound)
+
+		err := handler.PreviewTemplate(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestEmailHandler_SendTestEmail(t *testing.T) {
+	t.Run("WhenBodyIsEmpty_ShouldSendWithSampleParams", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/email-templates/cancel-template/test-send", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("name")
+		ectx.SetParamValues("cancel-template")
+
+		mockEmailTemplateService := new(mocks.EmailTemplateService)
+		handler := &emailHandler{ets: mockEmailTemplateService}
+
+		mockEmailTemplateService.On("SendTestEmail", mock.Anything, templates.CancelTemplate, map[string]string{}).
+			Return(nil)
+
+		err := handler.SendTestEmail(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("WhenParamIsInvalid_ShouldReturnBadRequest", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/email-templates/cancel-template/test-send", strings.NewReader(`{"unknown": "x"}`))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("name")
+		ectx.SetParamValues("cancel-template")
+
+		mockEmailTemplateService := new(mocks.EmailTemplateService)
+		handler := &emailHandler{ets: mockEmailTemplateService}
+
+		mockEmailTemplateService.On("SendTestEmail", mock.Anything, templates.CancelTemplate, map[string]string{"unknown": "x"}).
+			Return(templates.ErrInvalidParam)
+
+		err := handler.SendTestEmail(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
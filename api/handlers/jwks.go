@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/labstack/echo/v4"
+)
+
+type JWKSHandler interface {
+	GetJWKS(ectx echo.Context) error
+}
+
+type jwksHandler struct {
+	i  *di.Injector
+	ts services.TokenService
+}
+
+func NewJWKSHandler(i *di.Injector) (JWKSHandler, error) {
+	ts, err := di.Invoke[services.TokenService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke token service: %w", err)
+	}
+
+	return &jwksHandler{
+		i:  i,
+		ts: ts,
+	}, nil
+}
+
+// GetJWKS publishes the keys that verify our tokens. Clients may cache them
+// for a few minutes, which is why a new key is published before it signs.
+func (j *jwksHandler) GetJWKS(ectx echo.Context) error {
+	ectx.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+
+	return ectx.JSON(http.StatusOK, j.ts.GetJWKS(ectx.Request().Context()))
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
@@ -20,8 +22,14 @@ type OrderHandler interface {
 	CreateOrder(ectx echo.Context) error
 	PickUpOrder(ectx echo.Context) error
 	DeliverOrder(ectx echo.Context) error
+	FailDelivery(ectx echo.Context) error
+	CancelOrder(ectx echo.Context) error
+	ReturnOrder(ectx echo.Context) error
 	GetOrders(ectx echo.Context) error
 	GetOrder(ectx echo.Context) error
+	GetOrderEvents(ectx echo.Context) error
+	GetOrderProof(ectx echo.Context) error
+	TrackOrder(ectx echo.Context) error
 }
 
 type orderHandler struct {
@@ -96,11 +104,26 @@ func (o *orderHandler) PickUpOrder(ectx echo.Context) error {
 		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de encomenda inválido.")
 	}
 
-	response, err := o.os.PickUpOrder(ectx.Request().Context(), orderID)
+	var payload models.PickUpOrderPayload
+	if err := ectx.Bind(&payload); err != nil {
+		log.Warn("Error to bind payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := o.os.PickUpOrder(ectx.Request().Context(), orderID, payload)
 	if err != nil {
 		log.Error(err.Error())
 
-		if errors.Is(err, models.ErrUserNotFoundInContext) {
+		if errors.Is(err, models.ErrUserNotFoundInContext) || errors.Is(err, models.ErrUserNotFound) {
 			responses.AccessDeniedAPIErrorResponse(ectx)
 		}
 
@@ -113,7 +136,7 @@ func (o *orderHandler) PickUpOrder(ectx echo.Context) error {
 		}
 
 		if errors.Is(err, models.ErrCannotTransitionToPicknUp) {
-			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Não é possível entregar a encomenda que já foi entregue ou que foi retirada por outro entregador.")
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Não é possível retirar a encomenda que já foi entregue ou que foi retirada por outro entregador.")
 		}
 
 		return responses.InternalServerAPIErrorResponse(ectx)
@@ -140,10 +163,23 @@ func (o *orderHandler) DeliverOrder(ectx echo.Context) error {
 		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "É necessário enviar uma imagem para terminar a entrega.")
 	}
 
-	payload := models.DeliverOrderPayload{
-		OrderImage: image,
+	var payload models.DeliverOrderPayload
+	if err := ectx.Bind(&payload.OrderEventMetadata); err != nil {
+		log.Warn("Error to bind payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload.OrderEventMetadata); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
 	}
 
+	payload.OrderImage = image
+
 	if err := o.os.DeliverOrder(ectx.Request().Context(), orderID, payload); err != nil {
 		log.Error(err.Error())
 
@@ -163,10 +199,18 @@ func (o *orderHandler) DeliverOrder(ectx echo.Context) error {
 			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Não é possível entregar a encomenda sem antes retira-la.")
 		}
 
+		if errors.Is(err, models.ErrNotAssignedToOrder) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Você não está atribuído a esta entrega.")
+		}
+
 		if errors.Is(err, models.ErrImageTooLarge) {
 			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A imagem é muito grande. O tamanho máximo permitido é 5MB.")
 		}
 
+		if errors.Is(err, models.ErrImageTooManyPixels) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A resolução da imagem é muito grande. Envie uma foto com resolução menor.")
+		}
+
 		if errors.Is(err, models.ErrInvalidImageFormat) {
 			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Formato de imagem inválido. Por favor, envie uma imagem com formato válido (JPG, JPEG, PNG).")
 		}
@@ -175,6 +219,191 @@ func (o *orderHandler) DeliverOrder(ectx echo.Context) error {
 			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A imagem está corrompida ou tem um formato inválido.")
 		}
 
+		if errors.Is(err, models.ErrImageAlreadyUsed) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Esta imagem já foi utilizada como comprovante de outra entrega.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.NoContent(http.StatusOK)
+}
+
+func (o *orderHandler) FailDelivery(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "order"),
+		slog.String("func", "FailDelivery"),
+	)
+
+	orderID, err := uuid.Parse(ectx.Param("orderId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de encomenda inválido.")
+	}
+
+	var payload models.FailDeliveryPayload
+	if err := ectx.Bind(&payload); err != nil {
+		log.Warn("Error to bind payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := o.os.FailDelivery(ectx.Request().Context(), orderID, payload)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrOrderNotFound) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado um pedido para registrar a tentativa de entrega.")
+		}
+
+		if errors.Is(err, models.ErrNotAssignedToOrder) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Você não está atribuído a esta entrega.")
+		}
+
+		if errors.Is(err, models.ErrCannotTransitionToDeliveryFailed) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Só é possível registrar uma tentativa de entrega para uma encomenda em rota de entrega.")
+		}
+
+		if errors.Is(err, models.ErrImageTooLarge) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A imagem é muito grande. O tamanho máximo permitido é 5MB.")
+		}
+
+		if errors.Is(err, models.ErrImageTooManyPixels) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A resolução da imagem é muito grande. Envie uma foto com resolução menor.")
+		}
+
+		if errors.Is(err, models.ErrInvalidImageFormat) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Formato de imagem inválido. Por favor, envie uma imagem com formato válido (JPG, JPEG, PNG).")
+		}
+
+		if errors.Is(err, models.ErrImageCorrupted) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "A imagem está corrompida ou tem um formato inválido.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (o *orderHandler) CancelOrder(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "order"),
+		slog.String("func", "CancelOrder"),
+	)
+
+	orderID, err := uuid.Parse(ectx.Param("orderId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de encomenda inválido.")
+	}
+
+	var payload models.CancelOrderPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	if err := o.os.CancelOrder(ectx.Request().Context(), orderID, payload); err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrOrderNotFound) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado um pedido para cancelar.")
+		}
+
+		if errors.Is(err, models.ErrCannotTransitionToCanceled) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Não é possível cancelar uma encomenda que já está em devolução, foi entregue, devolvida ou cancelada.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.NoContent(http.StatusOK)
+}
+
+func (o *orderHandler) ReturnOrder(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "order"),
+		slog.String("func", "ReturnOrder"),
+	)
+
+	orderID, err := uuid.Parse(ectx.Param("orderId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de encomenda inválido.")
+	}
+
+	var payload models.ReturnOrderPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	if err := o.os.ReturnOrder(ectx.Request().Context(), orderID, payload); err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrOrderNotFound) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado um pedido para devolver.")
+		}
+
+		if errors.Is(err, models.ErrNotAssignedToOrder) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Você não está atribuído a esta entrega.")
+		}
+
+		if errors.Is(err, models.ErrCannotTransitionToReturned) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Só é possível devolver uma encomenda que foi retirada e não foi entregue.")
+		}
+
 		return responses.InternalServerAPIErrorResponse(ectx)
 	}
 
@@ -187,7 +416,24 @@ func (o *orderHandler) GetOrders(ectx echo.Context) error {
 		slog.String("func", "GetOrders"),
 	)
 
-	pagination := models.NewPagination(ectx.QueryParam("page"), ectx.QueryParam("limit"))
+	pagination := &models.OrderPagination{
+		Pagination: *models.NewPagination(ectx.QueryParam("page"), ectx.QueryParam("limit")),
+	}
+
+	if status := ectx.QueryParam("status"); status != "" {
+		orderStatus := models.OrderStatus(strings.ToUpper(status))
+		pagination.Status = &orderStatus
+	}
+
+	if returned := ectx.QueryParam("returned"); returned != "" {
+		isReturned, err := strconv.ParseBool(returned)
+		if err != nil {
+			log.Warn(err.Error())
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de filtro de devolução inválido.")
+		}
+
+		pagination.IsReturned = &isReturned
+	}
 
 	response, err := o.os.GetOrders(ectx.Request().Context(), pagination)
 	if err != nil {
@@ -201,7 +447,7 @@ func (o *orderHandler) GetOrders(ectx echo.Context) error {
 			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
 		}
 
-		responses.InternalServerAPIErrorResponse(ectx)
+		return responses.InternalServerAPIErrorResponse(ectx)
 	}
 
 	return ectx.JSON(http.StatusOK, response)
@@ -244,3 +490,118 @@ func (o *orderHandler) GetOrder(ectx echo.Context) error {
 
 	return ectx.JSON(http.StatusOK, response)
 }
+
+func (o *orderHandler) GetOrderEvents(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "order"),
+		slog.String("func", "GetOrderEvents"),
+	)
+
+	orderID, err := uuid.Parse(ectx.Param("orderId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de encomenda inválido.")
+	}
+
+	response, err := o.os.GetOrderEvents(ectx.Request().Context(), orderID)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrOrderNotFound) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado nenhuma entrega.")
+		}
+
+		if errors.Is(err, models.ErrNotAssignedToOrder) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Você não está atribuído a esta entrega.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (o *orderHandler) GetOrderProof(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "order"),
+		slog.String("func", "GetOrderProof"),
+	)
+
+	orderID, err := uuid.Parse(ectx.Param("orderId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de encomenda inválido.")
+	}
+
+	proof, err := o.os.GetOrderProof(ectx.Request().Context(), orderID)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrOrderNotFound) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado nenhuma entrega.")
+		}
+
+		if errors.Is(err, models.ErrNotAssignedToOrder) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Você não está atribuído a esta entrega.")
+		}
+
+		if errors.Is(err, models.ErrProofNotFound) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Esta entrega não possui comprovante de entrega.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	if proof.URL != "" {
+		return ectx.Redirect(http.StatusFound, proof.URL)
+	}
+	defer proof.Content.Close()
+
+	if proof.Size > 0 {
+		ectx.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(proof.Size, 10))
+	}
+
+	return ectx.Stream(http.StatusOK, proof.ContentType, proof.Content)
+}
+
+func (o *orderHandler) TrackOrder(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "order"),
+		slog.String("func", "TrackOrder"),
+	)
+
+	trackingCode, err := uuid.Parse(ectx.Param("trackingCode"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Código de rastreamento inválido.")
+	}
+
+	response, err := o.os.TrackOrder(ectx.Request().Context(), trackingCode)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrOrderNotFound) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrada nenhuma encomenda com esse código de rastreamento.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
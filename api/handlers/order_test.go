@@ -1 +1,352 @@
 package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOrderHandler_TrackOrder(t *testing.T) {
+	t.Run("WhenTrackingCodeIsInvalid_ShouldReturnBadRequest", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/tracking/invalid", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("trackingCode")
+		ectx.SetParamValues("invalid")
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		err := handler.TrackOrder(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Código de rastreamento inválido.")
+	})
+
+	t.Run("WhenOrderNotFound_ShouldReturnNotFound", func(t *testing.T) {
+		trackingCode := uuid.New()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/tracking/"+trackingCode.String(), nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("trackingCode")
+		ectx.SetParamValues(trackingCode.String())
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("TrackOrder", mock.Anything, trackingCode).Return(nil, models.ErrOrderNotFound)
+
+		err := handler.TrackOrder(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockOrderService.AssertExpectations(t)
+	})
+
+	t.Run("WhenServiceFails_ShouldReturnInternalServerError", func(t *testing.T) {
+		trackingCode := uuid.New()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/tracking/"+trackingCode.String(), nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("trackingCode")
+		ectx.SetParamValues(trackingCode.String())
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("TrackOrder", mock.Anything, trackingCode).Return(nil, errors.New("unexpected error"))
+
+		err := handler.TrackOrder(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		mockOrderService.AssertExpectations(t)
+	})
+
+	t.Run("WhenOrderFound_ShouldReturnTracking", func(t *testing.T) {
+		trackingCode := uuid.New()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/tracking/"+trackingCode.String(), nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("trackingCode")
+		ectx.SetParamValues(trackingCode.String())
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("TrackOrder", mock.Anything, trackingCode).Return(&models.TrackingResponse{
+			TrackingCode: trackingCode,
+			Status:       models.Waiting,
+			City:         "São Paulo",
+		}, nil)
+
+		err := handler.TrackOrder(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"city":"São Paulo"`)
+		mockOrderService.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_CancelOrder(t *testing.T) {
+	newContext := func(orderID, body string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/orders/"+orderID+"/status/cancel", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("orderId")
+		ectx.SetParamValues(orderID)
+		return ectx, rec
+	}
+
+	t.Run("WhenReasonIsMissing_ShouldReturnValidationError", func(t *testing.T) {
+		ectx, rec := newContext(uuid.NewString(), `{"reason": ""}`)
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		err := handler.CancelOrder(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Este campo é obrigatório. Por favor, preencha corretamente.")
+		mockOrderService.AssertNotCalled(t, "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenOrderCannotBeCanceled_ShouldReturnBadRequest", func(t *testing.T) {
+		orderID := uuid.New()
+		ectx, rec := newContext(orderID.String(), `{"reason": "Pedido duplicado"}`)
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("CancelOrder", mock.Anything, orderID, models.CancelOrderPayload{Reason: "Pedido duplicado"}).
+			Return(models.ErrCannotTransitionToCanceled)
+
+		err := handler.CancelOrder(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockOrderService.AssertExpectations(t)
+	})
+
+	t.Run("WhenUserIsNotAllowed_ShouldReturnForbidden", func(t *testing.T) {
+		orderID := uuid.New()
+		ectx, rec := newContext(orderID.String(), `{"reason": "Pedido duplicado"}`)
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("CancelOrder", mock.Anything, orderID, mock.Anything).
+			Return(models.ErrInsufficientPermission)
+
+		err := handler.CancelOrder(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		mockOrderService.AssertExpectations(t)
+	})
+
+	t.Run("WhenOrderIsCanceled_ShouldReturnOK", func(t *testing.T) {
+		orderID := uuid.New()
+		ectx, rec := newContext(orderID.String(), `{"reason": "Pedido duplicado"}`)
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("CancelOrder", mock.Anything, orderID, mock.Anything).
+			Return(nil)
+
+		err := handler.CancelOrder(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockOrderService.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_GetOrders(t *testing.T) {
+	t.Run("WhenReturnedFilterIsInvalid_ShouldReturnBadRequest", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/orders?returned=maybe", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		err := handler.GetOrders(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockOrderService.AssertNotCalled(t, "GetOrders", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenFilteringReturnedOrders_ShouldPassFilterToService", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/orders?returned=true&status=returned", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("GetOrders", mock.Anything, mock.MatchedBy(func(pagination *models.OrderPagination) bool {
+			return pagination.IsReturned != nil && *pagination.IsReturned &&
+				pagination.Status != nil && *pagination.Status == models.Returned
+		})).Return(&models.PaginatedResponse[*models.OrderResponse]{}, nil)
+
+		err := handler.GetOrders(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockOrderService.AssertExpectations(t)
+	})
+
+	t.Run("WhenServiceFails_ShouldReturnInternalServerError", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("GetOrders", mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+
+		err := handler.GetOrders(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		mockOrderService.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_FailDelivery(t *testing.T) {
+	newContext := func(orderID string, form url.Values) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/orders/"+orderID+"/status/fail", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("orderId")
+		ectx.SetParamValues(orderID)
+		return ectx, rec
+	}
+
+	t.Run("WhenReasonIsInvalid_ShouldReturnValidationError", func(t *testing.T) {
+		ectx, rec := newContext(uuid.NewString(), url.Values{"reason": {"SLEEPING"}})
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		err := handler.FailDelivery(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "O valor informado não é uma das opções permitidas")
+		mockOrderService.AssertNotCalled(t, "FailDelivery", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenAttemptIsRecorded_ShouldReturnAttemptCount", func(t *testing.T) {
+		orderID := uuid.New()
+		ectx, rec := newContext(orderID.String(), url.Values{"reason": {"RECIPIENT_ABSENT"}, "note": {"Portão fechado"}})
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("FailDelivery", mock.Anything, orderID, mock.MatchedBy(func(payload models.FailDeliveryPayload) bool {
+			return payload.Reason == models.RecipientAbsent && payload.Note != nil && *payload.Note == "Portão fechado"
+		})).Return(&models.FailDeliveryResponse{Status: models.DeliveryFailed, DeliveryAttempts: 1}, nil)
+
+		err := handler.FailDelivery(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"deliveryAttempts":1`)
+		mockOrderService.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_GetOrderProof(t *testing.T) {
+	newContext := func(orderID string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/orders/"+orderID+"/proof", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("orderId")
+		ectx.SetParamValues(orderID)
+		return ectx, rec
+	}
+
+	t.Run("WhenProofHasSignedURL_ShouldRedirect", func(t *testing.T) {
+		orderID := uuid.New()
+		ectx, rec := newContext(orderID.String())
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("GetOrderProof", mock.Anything, orderID).
+			Return(&models.OrderProof{URL: "https://storage.local/proof.png?X-Amz-Signature=abc"}, nil)
+
+		err := handler.GetOrderProof(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, "https://storage.local/proof.png?X-Amz-Signature=abc", rec.Header().Get(echo.HeaderLocation))
+	})
+
+	t.Run("WhenProofIsStreamed_ShouldWriteImage", func(t *testing.T) {
+		orderID := uuid.New()
+		ectx, rec := newContext(orderID.String())
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("GetOrderProof", mock.Anything, orderID).
+			Return(&models.OrderProof{Content: io.NopCloser(strings.NewReader("png")), ContentType: "image/png", Size: 3}, nil)
+
+		err := handler.GetOrderProof(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "png", rec.Body.String())
+	})
+
+	t.Run("WhenOrderHasNoProof_ShouldReturnNotFound", func(t *testing.T) {
+		orderID := uuid.New()
+		ectx, rec := newContext(orderID.String())
+
+		mockOrderService := new(mocks.OrderService)
+		handler := &orderHandler{os: mockOrderService}
+
+		mockOrderService.On("GetOrderProof", mock.Anything, orderID).Return(nil, models.ErrProofNotFound)
+
+		err := handler.GetOrderProof(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Esta entrega não possui comprovante de entrega.")
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/middlewares"
+	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/labstack/echo/v4"
 )
 
@@ -17,6 +18,14 @@ func SetupRoutes(e *echo.Echo, i *di.Injector) error {
 		return fmt.Errorf("setup auth routes: %w", err)
 	}
 
+	if err := SetupSessionRoutes(e, i); err != nil {
+		return fmt.Errorf("setup session routes: %w", err)
+	}
+
+	if err := SetupTwoFactorRoutes(e, i); err != nil {
+		return fmt.Errorf("setup two-factor routes: %w", err)
+	}
+
 	if err := SetupRecipientRoutes(e, i); err != nil {
 		return fmt.Errorf("setup recipient routes: %w", err)
 	}
@@ -25,6 +34,22 @@ func SetupRoutes(e *echo.Echo, i *di.Injector) error {
 		return fmt.Errorf("setup order routes: %w", err)
 	}
 
+	if err := SetupTrackingRoutes(e, i); err != nil {
+		return fmt.Errorf("setup tracking routes: %w", err)
+	}
+
+	if err := SetupEmailRoutes(e, i); err != nil {
+		return fmt.Errorf("setup email routes: %w", err)
+	}
+
+	if err := SetupJWKSRoutes(e, i); err != nil {
+		return fmt.Errorf("setup JWKS routes: %w", err)
+	}
+
+	if err := SetupAPIKeyRoutes(e, i); err != nil {
+		return fmt.Errorf("setup api key routes: %w", err)
+	}
+
 	return nil
 }
 
@@ -34,11 +59,26 @@ func SetupUserRoutes(e *echo.Echo, i *di.Injector) error {
 		return fmt.Errorf("invoke user handler: %w", err)
 	}
 
-	v1Group := e.Group("/v1/users", middlewares.Authenticate)
+	am, err := di.Invoke[middlewares.AuthMiddleware](i)
+	if err != nil {
+		return fmt.Errorf("invoke auth middleware: %w", err)
+	}
+
+	v1Group := e.Group("/v1/users", am.Authenticate)
 
 	v1Group.POST("/admin", h.CreateAdmin)
 	v1Group.GET("/me", h.GetUser)
 
+	v1Group.POST("/delivery-men", h.CreateDeliveryMan)
+	v1Group.GET("/delivery-men", h.GetDeliveryMen)
+	v1Group.GET("/delivery-men/:deliveryManId", h.GetDeliveryMan)
+	v1Group.PUT("/delivery-men/:deliveryManId", h.UpdateDeliveryMan)
+	v1Group.DELETE("/delivery-men/:deliveryManId", h.DeactivateDeliveryMan)
+	v1Group.GET("/delivery-men/:deliveryManId/orders", h.GetDeliveryManOrders)
+
+	v1Group.POST("/:userId/block", h.BlockUser)
+	v1Group.POST("/:userId/unblock", h.UnblockUser)
+
 	return nil
 }
 
@@ -48,10 +88,63 @@ func SetupAuthRoutes(e *echo.Echo, i *di.Injector) error {
 		return fmt.Errorf("invoke auth handler: %w", err)
 	}
 
+	am, err := di.Invoke[middlewares.AuthMiddleware](i)
+	if err != nil {
+		return fmt.Errorf("invoke auth middleware: %w", err)
+	}
+
 	v1Group := e.Group("/v1")
 
 	v1Group.POST("/login", h.Login)
-	v1Group.POST("/logout", h.Logout)
+	v1Group.POST("/login/2fa", h.VerifyTwoFactor)
+	v1Group.POST("/login/2fa/enrollment", h.StartTwoFactorEnrollment)
+	v1Group.POST("/auth/refresh", h.Refresh)
+	v1Group.POST("/auth/logout", h.Logout)
+	v1Group.POST("/activate", h.ActivateAccount)
+	v1Group.POST("/password/forgot", h.ForgotPassword)
+	v1Group.POST("/password/reset", h.ResetPassword)
+	v1Group.PATCH("/users/me/password", h.ChangePassword, am.Authenticate)
+
+	return nil
+}
+
+func SetupSessionRoutes(e *echo.Echo, i *di.Injector) error {
+	h, err := di.Invoke[SessionHandler](i)
+	if err != nil {
+		return fmt.Errorf("invoke session handler: %w", err)
+	}
+
+	am, err := di.Invoke[middlewares.AuthMiddleware](i)
+	if err != nil {
+		return fmt.Errorf("invoke auth middleware: %w", err)
+	}
+
+	v1Group := e.Group("/v1/sessions", am.Authenticate)
+
+	v1Group.GET("", h.GetSessions)
+	v1Group.DELETE("", h.RevokeOtherSessions)
+	v1Group.DELETE("/:sessionId", h.RevokeSession)
+
+	return nil
+}
+
+func SetupTwoFactorRoutes(e *echo.Echo, i *di.Injector) error {
+	h, err := di.Invoke[TwoFactorHandler](i)
+	if err != nil {
+		return fmt.Errorf("invoke two-factor handler: %w", err)
+	}
+
+	am, err := di.Invoke[middlewares.AuthMiddleware](i)
+	if err != nil {
+		return fmt.Errorf("invoke auth middleware: %w", err)
+	}
+
+	v1Group := e.Group("/v1/users/me/2fa", am.Authenticate)
+
+	v1Group.POST("", h.StartEnrollment)
+	v1Group.POST("/confirm", h.ConfirmEnrollment)
+	v1Group.DELETE("", h.DisableTwoFactor)
+	v1Group.POST("/recovery-codes", h.RegenerateRecoveryCodes)
 
 	return nil
 }
@@ -62,13 +155,18 @@ func SetupRecipientRoutes(e *echo.Echo, i *di.Injector) error {
 		return fmt.Errorf("invoke recipient handler: %w", err)
 	}
 
-	v1Group := e.Group("/v1/recipients", middlewares.Authenticate)
+	am, err := di.Invoke[middlewares.AuthMiddleware](i)
+	if err != nil {
+		return fmt.Errorf("invoke auth middleware: %w", err)
+	}
+
+	v1Group := e.Group("/v1/recipients")
 
-	v1Group.POST("", h.CreateRecipient)
-	v1Group.GET("/:recipientId", h.GetRecipient)
-	v1Group.GET("/lite", h.GetRecipientsBasicInfo)
-	v1Group.DELETE("/:recipientId", h.DeleteRecipient)
-	v1Group.PUT("/:recipientId", h.UpdateRecipient)
+	v1Group.POST("", h.CreateRecipient, am.AuthenticateWithScope(models.Create, models.Recipients))
+	v1Group.GET("/:recipientId", h.GetRecipient, am.AuthenticateWithScope(models.Read, models.Recipients))
+	v1Group.GET("/lite", h.GetRecipientsBasicInfo, am.AuthenticateWithScope(models.Read, models.Recipients))
+	v1Group.DELETE("/:recipientId", h.DeleteRecipient, am.AuthenticateWithScope(models.Delete, models.Recipients))
+	v1Group.PUT("/:recipientId", h.UpdateRecipient, am.AuthenticateWithScope(models.Update, models.Recipients))
 
 	return nil
 }
@@ -79,13 +177,92 @@ func SetupOrdersRoutes(e *echo.Echo, i *di.Injector) error {
 		return fmt.Errorf("invoke order handler: %w", err)
 	}
 
-	v1Group := e.Group("/v1/orders", middlewares.Authenticate)
+	am, err := di.Invoke[middlewares.AuthMiddleware](i)
+	if err != nil {
+		return fmt.Errorf("invoke auth middleware: %w", err)
+	}
+
+	v1Group := e.Group("/v1/orders")
+
+	// Status updates stay with the delivery man, so they take no API keys.
+	v1Group.POST("", h.CreateOrder, am.AuthenticateWithScope(models.Create, models.Orders))
+	v1Group.PATCH("/:orderId/status/pick-up", h.PickUpOrder, am.Authenticate)
+	v1Group.PATCH("/:orderId/status/deliver", h.DeliverOrder, am.Authenticate)
+	v1Group.PATCH("/:orderId/status/fail", h.FailDelivery, am.Authenticate)
+	v1Group.PATCH("/:orderId/status/cancel", h.CancelOrder, am.AuthenticateWithScope(models.Cancel, models.Orders))
+	v1Group.PATCH("/:orderId/status/return", h.ReturnOrder, am.Authenticate)
+	v1Group.GET("", h.GetOrders, am.AuthenticateWithScope(models.Read, models.Orders))
+	v1Group.GET("/:orderId", h.GetOrder, am.AuthenticateWithScope(models.Read, models.Orders))
+	v1Group.GET("/:orderId/events", h.GetOrderEvents, am.AuthenticateWithScope(models.Read, models.Orders))
+	v1Group.GET("/:orderId/proof", h.GetOrderProof, am.AuthenticateWithScope(models.Read, models.Orders))
+
+	return nil
+}
+
+func SetupTrackingRoutes(e *echo.Echo, i *di.Injector) error {
+	h, err := di.Invoke[OrderHandler](i)
+	if err != nil {
+		return fmt.Errorf("invoke order handler: %w", err)
+	}
+
+	v1Group := e.Group("/v1/tracking")
+
+	v1Group.GET("/:trackingCode", h.TrackOrder)
+
+	return nil
+}
+
+func SetupEmailRoutes(e *echo.Echo, i *di.Injector) error {
+	h, err := di.Invoke[EmailHandler](i)
+	if err != nil {
+		return fmt.Errorf("invoke email handler: %w", err)
+	}
+
+	am, err := di.Invoke[middlewares.AuthMiddleware](i)
+	if err != nil {
+		return fmt.Errorf("invoke auth middleware: %w", err)
+	}
+
+	v1Group := e.Group("/v1/admin/emails", am.Authenticate)
+
+	v1Group.GET("", h.GetEmails)
+	v1Group.POST("/:emailId/retry", h.RetryEmail)
+
+	templatesGroup := e.Group("/v1/admin/email-templates", am.Authenticate)
+
+	templatesGroup.GET("/:name/preview", h.PreviewTemplate)
+	templatesGroup.POST("/:name/test-send", h.SendTestEmail)
+
+	return nil
+}
+
+func SetupJWKSRoutes(e *echo.Echo, i *di.Injector) error {
+	h, err := di.Invoke[JWKSHandler](i)
+	if err != nil {
+		return fmt.Errorf("invoke JWKS handler: %w", err)
+	}
+
+	e.GET("/.well-known/jwks.json", h.GetJWKS)
+
+	return nil
+}
+
+func SetupAPIKeyRoutes(e *echo.Echo, i *di.Injector) error {
+	h, err := di.Invoke[APIKeyHandler](i)
+	if err != nil {
+		return fmt.Errorf("invoke api key handler: %w", err)
+	}
+
+	am, err := di.Invoke[middlewares.AuthMiddleware](i)
+	if err != nil {
+		return fmt.Errorf("invoke auth middleware: %w", err)
+	}
+
+	v1Group := e.Group("/v1/api-keys", am.Authenticate)
 
-	v1Group.POST("", h.CreateOrder)
-	v1Group.PATCH("/:orderId/status/pick-up", h.PickUpOrder)
-	v1Group.PATCH("/:orderId/status/deliver", h.DeliverOrder)
-	v1Group.GET("", h.GetOrders)
-	v1Group.GET("/:orderId", h.GetOrder)
+	v1Group.POST("", h.CreateAPIKey)
+	v1Group.GET("", h.GetAPIKeys)
+	v1Group.DELETE("/:apiKeyId", h.RevokeAPIKey)
 
 	return nil
 }
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/responses"
+	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type SessionHandler interface {
+	GetSessions(ectx echo.Context) error
+	RevokeSession(ectx echo.Context) error
+	RevokeOtherSessions(ectx echo.Context) error
+}
+
+type sessionHandler struct {
+	i   *di.Injector
+	ses services.SessionService
+}
+
+func NewSessionHandler(i *di.Injector) (SessionHandler, error) {
+	ses, err := di.Invoke[services.SessionService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke session service: %w", err)
+	}
+
+	return &sessionHandler{
+		i:   i,
+		ses: ses,
+	}, nil
+}
+
+func (s *sessionHandler) GetSessions(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "session"),
+		slog.String("func", "GetSessions"),
+	)
+
+	response, err := s.ses.GetSessions(ectx.Request().Context())
+	if err != nil {
+		log.Error(err.Error())
+		return sessionErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (s *sessionHandler) RevokeSession(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "session"),
+		slog.String("func", "RevokeSession"),
+	)
+
+	sessionID, err := uuid.Parse(ectx.Param("sessionId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de sessão inválido.")
+	}
+
+	if err := s.ses.RevokeSession(ectx.Request().Context(), sessionID); err != nil {
+		log.Error(err.Error())
+		return sessionErrorResponse(ectx, err)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func (s *sessionHandler) RevokeOtherSessions(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "session"),
+		slog.String("func", "RevokeOtherSessions"),
+	)
+
+	if err := s.ses.RevokeOtherSessions(ectx.Request().Context()); err != nil {
+		log.Error(err.Error())
+		return sessionErrorResponse(ectx, err)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func sessionErrorResponse(ectx echo.Context, err error) error {
+	if errors.Is(err, models.ErrUserNotFoundInContext) {
+		return responses.AccessDeniedAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrSessionNotFound) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Sessão não encontrada.")
+	}
+
+	return responses.InternalServerAPIErrorResponse(ectx)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/responses"
+	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/G-Villarinho/fast-feet-api/validators"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+)
+
+type TwoFactorHandler interface {
+	StartEnrollment(ectx echo.Context) error
+	ConfirmEnrollment(ectx echo.Context) error
+	DisableTwoFactor(ectx echo.Context) error
+	RegenerateRecoveryCodes(ectx echo.Context) error
+}
+
+type twoFactorHandler struct {
+	i   *di.Injector
+	tfs services.TwoFactorService
+}
+
+func NewTwoFactorHandler(i *di.Injector) (TwoFactorHandler, error) {
+	tfs, err := di.Invoke[services.TwoFactorService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke two-factor service: %w", err)
+	}
+
+	return &twoFactorHandler{
+		i:   i,
+		tfs: tfs,
+	}, nil
+}
+
+func (t *twoFactorHandler) StartEnrollment(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "two-factor"),
+		slog.String("func", "StartEnrollment"),
+	)
+
+	response, err := t.tfs.StartEnrollment(ectx.Request().Context())
+	if err != nil {
+		log.Error(err.Error())
+		return twoFactorErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (t *twoFactorHandler) ConfirmEnrollment(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "two-factor"),
+		slog.String("func", "ConfirmEnrollment"),
+	)
+
+	var payload models.TwoFactorCodePayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := t.tfs.ConfirmEnrollment(ectx.Request().Context(), payload)
+	if err != nil {
+		log.Error(err.Error())
+		return twoFactorErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (t *twoFactorHandler) DisableTwoFactor(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "two-factor"),
+		slog.String("func", "DisableTwoFactor"),
+	)
+
+	var payload models.TwoFactorCodePayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	if err := t.tfs.DisableTwoFactor(ectx.Request().Context(), payload); err != nil {
+		log.Error(err.Error())
+		return twoFactorErrorResponse(ectx, err)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func (t *twoFactorHandler) RegenerateRecoveryCodes(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "two-factor"),
+		slog.String("func", "RegenerateRecoveryCodes"),
+	)
+
+	var payload models.TwoFactorCodePayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := t.tfs.RegenerateRecoveryCodes(ectx.Request().Context(), payload)
+	if err != nil {
+		log.Error(err.Error())
+		return twoFactorErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func twoFactorErrorResponse(ectx echo.Context, err error) error {
+	if errors.Is(err, models.ErrUserNotFoundInContext) || errors.Is(err, models.ErrUserNotFound) {
+		return responses.AccessDeniedAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrTwoFactorNotAvailable) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "A autenticação em dois fatores não está disponível para o seu perfil.")
+	}
+
+	if errors.Is(err, models.ErrTwoFactorAlreadyEnabled) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "A autenticação em dois fatores já está ativada.")
+	}
+
+	if errors.Is(err, models.ErrTwoFactorNotEnabled) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "A autenticação em dois fatores não está ativada.")
+	}
+
+	if errors.Is(err, models.ErrTwoFactorRequired) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "A autenticação em dois fatores é obrigatória para o seu perfil e não pode ser desativada.")
+	}
+
+	if errors.Is(err, models.ErrTwoFactorEnrollmentNotFound) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusGone, "A configuração da autenticação em dois fatores expirou. Comece novamente.")
+	}
+
+	if errors.Is(err, models.ErrTwoFactorChallengeInvalid) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusUnauthorized, "Sua tentativa de login expirou. Faça login novamente.")
+	}
+
+	if errors.Is(err, models.ErrInvalidTwoFactorCode) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Código de verificação inválido.")
+	}
+
+	if errors.Is(err, models.ErrTooManyCodeAttempts) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusTooManyRequests, "Muitas tentativas com código inválido. Aguarde alguns minutos e tente novamente.")
+	}
+
+	return responses.InternalServerAPIErrorResponse(ectx)
+}
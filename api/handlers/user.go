@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/G-Villarinho/fast-feet-api/responses"
 	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/G-Villarinho/fast-feet-api/utils"
 	"github.com/G-Villarinho/fast-feet-api/validators"
+	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/labstack/echo/v4"
 )
@@ -18,6 +21,14 @@ import (
 type UserHandler interface {
 	CreateAdmin(ectx echo.Context) error
 	GetUser(ectx echo.Context) error
+	CreateDeliveryMan(ectx echo.Context) error
+	GetDeliveryMen(ectx echo.Context) error
+	GetDeliveryMan(ectx echo.Context) error
+	UpdateDeliveryMan(ectx echo.Context) error
+	DeactivateDeliveryMan(ectx echo.Context) error
+	GetDeliveryManOrders(ectx echo.Context) error
+	BlockUser(ectx echo.Context) error
+	UnblockUser(ectx echo.Context) error
 }
 
 type userHandler struct {
@@ -106,3 +117,294 @@ func (u *userHandler) GetUser(ectx echo.Context) error {
 
 	return ectx.JSON(http.StatusOK, response)
 }
+
+func (u *userHandler) CreateDeliveryMan(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "CreateDeliveryMan"),
+	)
+
+	var payload models.CreateUserPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	if err := u.us.CreateDeliveryMan(ectx.Request().Context(), payload); err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrEmailAlreadyExists) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Um usuário com o mesmo e-mail já está cadastrado.")
+		}
+
+		if errors.Is(err, models.ErrCPFAlreadyExists) {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Um usuário com o mesmo CPF já está cadastrado.")
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.NoContent(http.StatusCreated)
+}
+
+func (u *userHandler) GetDeliveryMen(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "GetDeliveryMen"),
+	)
+
+	pagination := &models.DeliveryManPagination{
+		Pagination: *models.NewPagination(ectx.QueryParam("page"), ectx.QueryParam("limit")),
+		Q:          utils.GetQueryStringPointer(ectx.QueryParam("q")),
+	}
+
+	if status := ectx.QueryParam("status"); status != "" {
+		userStatus := models.Status(strings.ToUpper(status))
+		pagination.Status = &userStatus
+	}
+
+	response, err := u.us.GetDeliveryMen(ectx.Request().Context(), pagination)
+	if err != nil {
+		log.Error(err.Error())
+
+		if errors.Is(err, models.ErrUserNotFoundInContext) || errors.Is(err, models.ErrUserNotFound) {
+			return responses.AccessDeniedAPIErrorResponse(ectx)
+		}
+
+		if errors.Is(err, models.ErrInsufficientPermission) {
+			return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+		}
+
+		return responses.InternalServerAPIErrorResponse(ectx)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (u *userHandler) GetDeliveryMan(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "GetDeliveryMan"),
+	)
+
+	deliveryManID, err := uuid.Parse(ectx.Param("deliveryManId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de entregador inválido.")
+	}
+
+	response, err := u.us.GetDeliveryMan(ectx.Request().Context(), deliveryManID)
+	if err != nil {
+		log.Error(err.Error())
+		return deliveryManErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (u *userHandler) UpdateDeliveryMan(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "UpdateDeliveryMan"),
+	)
+
+	deliveryManID, err := uuid.Parse(ectx.Param("deliveryManId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de entregador inválido.")
+	}
+
+	var payload models.UpdateUserPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	response, err := u.us.UpdateDeliveryMan(ectx.Request().Context(), deliveryManID, payload)
+	if err != nil {
+		log.Error(err.Error())
+		return deliveryManErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (u *userHandler) DeactivateDeliveryMan(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "DeactivateDeliveryMan"),
+	)
+
+	deliveryManID, err := uuid.Parse(ectx.Param("deliveryManId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de entregador inválido.")
+	}
+
+	if err := u.us.DeactivateDeliveryMan(ectx.Request().Context(), deliveryManID); err != nil {
+		log.Error(err.Error())
+		return deliveryManErrorResponse(ectx, err)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func (u *userHandler) GetDeliveryManOrders(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "GetDeliveryManOrders"),
+	)
+
+	deliveryManID, err := uuid.Parse(ectx.Param("deliveryManId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de entregador inválido.")
+	}
+
+	pagination := models.NewPagination(ectx.QueryParam("page"), ectx.QueryParam("limit"))
+
+	response, err := u.us.GetDeliveryManOrders(ectx.Request().Context(), deliveryManID, pagination)
+	if err != nil {
+		log.Error(err.Error())
+		return deliveryManErrorResponse(ectx, err)
+	}
+
+	return ectx.JSON(http.StatusOK, response)
+}
+
+func (u *userHandler) BlockUser(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "BlockUser"),
+	)
+
+	userID, err := uuid.Parse(ectx.Param("userId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de usuário inválido.")
+	}
+
+	var payload models.BlockUserPayload
+	if err := jsoniter.NewDecoder(ectx.Request().Body).Decode(&payload); err != nil {
+		log.Warn("Error to decode JSON payload", slog.String("error", err.Error()))
+		return responses.CannotBindPayloadAPIErrorResponse(ectx)
+	}
+
+	if validationErrors := validators.ValidateStruct(&payload); validationErrors != nil {
+		if msg, exists := validationErrors["validation_setup"]; exists {
+			log.Warn("Error in validation setup", slog.String("message", msg))
+			return responses.CannotBindPayloadAPIErrorResponse(ectx)
+		}
+
+		return responses.NewValidationErrorResponse(ectx, validationErrors)
+	}
+
+	if err := u.us.BlockUser(ectx.Request().Context(), userID, payload); err != nil {
+		log.Error(err.Error())
+		return blockErrorResponse(ectx, err)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func (u *userHandler) UnblockUser(ectx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "UnblockUser"),
+	)
+
+	userID, err := uuid.Parse(ectx.Param("userId"))
+	if err != nil {
+		log.Warn(err.Error())
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusBadRequest, "Parâmetro de busca de usuário inválido.")
+	}
+
+	if err := u.us.UnblockUser(ectx.Request().Context(), userID); err != nil {
+		log.Error(err.Error())
+		return blockErrorResponse(ectx, err)
+	}
+
+	return ectx.NoContent(http.StatusNoContent)
+}
+
+func blockErrorResponse(ectx echo.Context, err error) error {
+	if errors.Is(err, models.ErrUserNotFoundInContext) {
+		return responses.AccessDeniedAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrInsufficientPermission) {
+		return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrUserNotFound) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado um usuário com esse parâmetro de busca.")
+	}
+
+	if errors.Is(err, models.ErrUserAlreadyBlocked) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Este usuário já está bloqueado.")
+	}
+
+	if errors.Is(err, models.ErrUserNotBlocked) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Este usuário não está bloqueado.")
+	}
+
+	if errors.Is(err, models.ErrCannotBlockSelf) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Você não pode bloquear a sua própria conta.")
+	}
+
+	return responses.InternalServerAPIErrorResponse(ectx)
+}
+
+func deliveryManErrorResponse(ectx echo.Context, err error) error {
+	if errors.Is(err, models.ErrUserNotFoundInContext) || errors.Is(err, models.ErrUserNotFound) {
+		return responses.AccessDeniedAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrInsufficientPermission) {
+		return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+	}
+
+	if errors.Is(err, models.ErrDeliveryManNotFound) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusNotFound, "Não foi encontrado um entregador com esse parâmetro de busca.")
+	}
+
+	if errors.Is(err, models.ErrEmailAlreadyExists) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Um usuário com o mesmo e-mail já está cadastrado.")
+	}
+
+	if errors.Is(err, models.ErrCPFAlreadyExists) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "Um usuário com o mesmo CPF já está cadastrado.")
+	}
+
+	if errors.Is(err, models.ErrDeliveryManHasActiveOrders) {
+		return responses.NewCustomAPIErrorResponse(ectx, http.StatusConflict, "O entregador possui encomendas em andamento e não pode ser desativado.")
+	}
+
+	return responses.InternalServerAPIErrorResponse(ectx)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/G-Villarinho/fast-feet-api/mocks"
 	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -152,3 +153,85 @@ func TestUserHandler_CreateAdmin(t *testing.T) {
 		mockUserService.AssertExpectations(t)
 	})
 }
+
+func TestUserHandler_CreateDeliveryMan(t *testing.T) {
+	t.Run("WhenPayloadIsValid_ShouldReturnCreated", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		handler := &userHandler{us: mockUserService}
+
+		mockUserService.On("CreateDeliveryMan", mock.Anything, mock.Anything).Return(nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/delivery-men", strings.NewReader(validCreateUserPayload))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := handler.CreateDeliveryMan(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		mockUserService.AssertExpectations(t)
+	})
+}
+
+func TestUserHandler_DeactivateDeliveryMan(t *testing.T) {
+	t.Run("WhenIDIsInvalid_ShouldReturnBadRequest", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		handler := &userHandler{us: mockUserService}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/delivery-men/invalid", nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("deliveryManId")
+		ectx.SetParamValues("invalid")
+
+		err := handler.DeactivateDeliveryMan(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("WhenDeliveryManHasActiveOrders_ShouldReturnConflict", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		handler := &userHandler{us: mockUserService}
+
+		deliveryManID := uuid.New()
+		mockUserService.On("DeactivateDeliveryMan", mock.Anything, deliveryManID).
+			Return(models.ErrDeliveryManHasActiveOrders)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/delivery-men/"+deliveryManID.String(), nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("deliveryManId")
+		ectx.SetParamValues(deliveryManID.String())
+
+		err := handler.DeactivateDeliveryMan(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "encomendas em andamento")
+	})
+
+	t.Run("WhenDeliveryManNotFound_ShouldReturnNotFound", func(t *testing.T) {
+		mockUserService := new(mocks.UserService)
+		handler := &userHandler{us: mockUserService}
+
+		deliveryManID := uuid.New()
+		mockUserService.On("DeactivateDeliveryMan", mock.Anything, deliveryManID).
+			Return(models.ErrDeliveryManNotFound)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/delivery-men/"+deliveryManID.String(), nil)
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+		ectx.SetParamNames("deliveryManId")
+		ectx.SetParamValues(deliveryManID.String())
+
+		err := handler.DeactivateDeliveryMan(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
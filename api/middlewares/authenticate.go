@@ -2,33 +2,156 @@ package middlewares
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"time"
+	"strings"
 
 	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/repositories"
 	"github.com/G-Villarinho/fast-feet-api/request"
 	"github.com/G-Villarinho/fast-feet-api/responses"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/G-Villarinho/fast-feet-api/utils"
 	"github.com/labstack/echo/v4"
 )
 
-func Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+// passwordChangeRoutes stay reachable while the user must change the password
+// set when their account was created; every other route is refused.
+var passwordChangeRoutes = map[string]bool{
+	"/v1/users/me":          true,
+	"/v1/users/me/password": true,
+}
+
+// APIKeyHeader carries the API key of machine-to-machine clients.
+const APIKeyHeader = "X-API-Key"
+
+type AuthMiddleware interface {
+	Authenticate(next echo.HandlerFunc) echo.HandlerFunc
+	AuthenticateWithScope(action models.Action, resource models.Resource) echo.MiddlewareFunc
+}
+
+type authMiddleware struct {
+	i   *di.Injector
+	ur  repositories.UserRepository
+	ses services.SessionService
+	ts  services.TokenService
+	aks services.APIKeyService
+}
+
+func NewAuthMiddleware(i *di.Injector) (AuthMiddleware, error) {
+	ur, err := di.Invoke[repositories.UserRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke user repository: %w", err)
+	}
+
+	ses, err := di.Invoke[services.SessionService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke session service: %w", err)
+	}
+
+	ts, err := di.Invoke[services.TokenService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke token service: %w", err)
+	}
+
+	aks, err := di.Invoke[services.APIKeyService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke api key service: %w", err)
+	}
+
+	return &authMiddleware{
+		i:   i,
+		ur:  ur,
+		ses: ses,
+		ts:  ts,
+		aks: aks,
+	}, nil
+}
+
+// Authenticate validates the session token, checks that its session was not
+// revoked and loads its user on every request, so logging out, blocking or
+// deactivating a user takes effect immediately instead of when the token
+// expires. The token is read from an "Authorization: Bearer" header or, when
+// the header is absent, from the session cookie.
+func (a *authMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ectx echo.Context) error {
-		cookie, err := ectx.Cookie(config.Env.Session.CookieName)
-		if err != nil {
+		token, method, found := ExtractToken(ectx)
+
+		// Only cookies are ours to clear; bearer clients drop their own token.
+		reject := func() error {
+			if method == request.CookieAuth {
+				removeCookie(ectx)
+			} else {
+				ectx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+			}
+
 			return responses.AccessDeniedAPIErrorResponse(ectx)
 		}
 
-		claims, err := validateToken(cookie.Value)
-		if err != nil {
-			removeCookie(ectx)
-			return responses.AccessDeniedAPIErrorResponse(ectx)
+		if !found {
+			return reject()
+		}
+
+		// The route does not accept API keys; authenticating the request with
+		// the session instead would hide that the key was ignored.
+		if ectx.Request().Header.Get(APIKeyHeader) != "" {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusUnauthorized, "Esta rota não aceita chaves de API.")
 		}
 
 		ctx := ectx.Request().Context()
+
+		claims, err := a.ts.ParseToken(ctx, token)
+		if err != nil {
+			return reject()
+		}
+
+		sessionID, err := claims.SessionID()
+		if err != nil {
+			return reject()
+		}
+
+		if err := a.ses.ValidateSession(ctx, claims.UserID, sessionID); err != nil {
+			if errors.Is(err, models.ErrSessionRevoked) {
+				return reject()
+			}
+
+			slog.Error("validate session", slog.String("sessionId", sessionID.String()), slog.String("error", err.Error()))
+			return responses.InternalServerAPIErrorResponse(ectx)
+		}
+
+		user, err := a.ur.GetUserByID(ctx, claims.UserID)
+		if err != nil {
+			slog.Error("get authenticated user", slog.String("userId", claims.UserID.String()), slog.String("error", err.Error()))
+			return responses.InternalServerAPIErrorResponse(ectx)
+		}
+
+		if user == nil {
+			return reject()
+		}
+
+		if claims.IssuedAt == nil || user.SessionRevoked(claims.IssuedAt.Time) {
+			return reject()
+		}
+
+		if user.Status == models.BlockedStatus {
+			if method == request.CookieAuth {
+				removeCookie(ectx)
+			}
+
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "Sua conta está temporariamente bloqueada. Entre em contato com o suporte para mais informações.")
+		}
+
+		if user.MustChangePassword && !passwordChangeRoutes[ectx.Path()] {
+			return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "Você precisa alterar sua senha antes de continuar.")
+		}
+
 		ctx = request.WithUserID(ctx, claims.UserID)
-		ctx = request.WithToken(ctx, cookie.Value)
+		ctx = request.WithToken(ctx, token)
+		ctx = request.WithSessionID(ctx, sessionID)
+		ctx = request.WithAuthMethod(ctx, method)
 
 		ectx.SetRequest(ectx.Request().WithContext(ctx))
 
@@ -36,29 +159,81 @@ func Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
-func validateToken(tokenString string) (*models.TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &models.TokenClaims{}, func(token *jwt.Token) (any, error) {
-		return []byte(config.Env.Session.JWTSecret), nil
-	})
+// AuthenticateWithScope works like Authenticate but also accepts an API key,
+// sent in the X-API-Key header, when one of its scopes covers the action on
+// the resource. The request then acts as the user that owns the key, so the
+// role of that user still limits what the key can do. Authenticate alone never
+// accepts API keys, which keeps account management out of their reach.
+func (a *authMiddleware) AuthenticateWithScope(action models.Action, resource models.Resource) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		authenticate := a.Authenticate(next)
 
-	if err != nil {
-		return nil, err
+		return func(ectx echo.Context) error {
+			key := ectx.Request().Header.Get(APIKeyHeader)
+			if key == "" {
+				return authenticate(ectx)
+			}
+
+			ctx := ectx.Request().Context()
+
+			apiKey, err := a.aks.AuthenticateAPIKey(ctx, key)
+			if err != nil {
+				if errors.Is(err, models.ErrAPIKeyInvalid) {
+					return responses.AccessDeniedAPIErrorResponse(ectx)
+				}
+
+				slog.Error("authenticate api key", slog.String("error", err.Error()))
+				return responses.InternalServerAPIErrorResponse(ectx)
+			}
+
+			if !apiKey.Allows(action, resource) {
+				return responses.ForbiddenPermissionAPIErrorResponse(ectx)
+			}
+
+			user, err := a.ur.GetUserByID(ctx, apiKey.UserID)
+			if err != nil {
+				slog.Error("get api key owner", slog.String("userId", apiKey.UserID.String()), slog.String("error", err.Error()))
+				return responses.InternalServerAPIErrorResponse(ectx)
+			}
+
+			if user == nil {
+				return responses.AccessDeniedAPIErrorResponse(ectx)
+			}
+
+			if user.Status == models.BlockedStatus {
+				return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "A conta dona desta chave de API está bloqueada. Entre em contato com o suporte para mais informações.")
+			}
+
+			ctx = request.WithUserID(ctx, user.ID)
+			ctx = request.WithAuthMethod(ctx, request.APIKeyAuth)
+
+			ectx.SetRequest(ectx.Request().WithContext(ctx))
+
+			return next(ectx)
+		}
+	}
+}
+
+// ExtractToken prefers the Authorization header, so API clients that also
+// carry a stale cookie are authenticated by the token they chose to send.
+func ExtractToken(ectx echo.Context) (string, request.AuthMethod, bool) {
+	if header := ectx.Request().Header.Get(echo.HeaderAuthorization); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			return "", request.BearerAuth, false
+		}
+
+		return strings.TrimSpace(token), request.BearerAuth, true
 	}
 
-	if claims, ok := token.Claims.(*models.TokenClaims); ok && token.Valid {
-		return claims, nil
+	cookie, err := ectx.Cookie(config.Env.Session.CookieName)
+	if err != nil || cookie.Value == "" {
+		return "", request.CookieAuth, false
 	}
 
-	return nil, errors.New("invalid token")
+	return cookie.Value, request.CookieAuth, true
 }
 
 func removeCookie(ectx echo.Context) {
-	ectx.SetCookie(&http.Cookie{
-		Name:     config.Env.Session.CookieName,
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Expires:  time.Unix(0, 0),
-		MaxAge:   -1,
-	})
+	ectx.SetCookie(utils.ExpiredCookie(config.Env.Session.CookieName, "/", true))
 }
@@ -0,0 +1,440 @@
+package middlewares
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthMiddleware_Authenticate(t *testing.T) {
+	config.Env.Session = config.Session{CookieName: "fast-feet.token"}
+
+	sessionID := uuid.New()
+	keySet, tokenService := newTokenService(t)
+
+	newTokenIssuedAt := func(userID uuid.UUID, issuedAt time.Time) string {
+		token, err := keySet.Sign(models.TokenClaims{
+			UserID: userID,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        sessionID.String(),
+				ExpiresAt: jwt.NewNumericDate(issuedAt.Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(issuedAt),
+			},
+		})
+		assert.NoError(t, err)
+
+		return token
+	}
+
+	newToken := func(userID uuid.UUID) string {
+		return newTokenIssuedAt(userID, time.Now())
+	}
+
+	serve := func(middleware AuthMiddleware, token string) (*httptest.ResponseRecorder, bool) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/v1/users/me", nil)
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: token})
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		called := false
+		err := middleware.Authenticate(func(ectx echo.Context) error {
+			_, called = request.UserID(ectx.Request().Context())
+			return ectx.NoContent(http.StatusOK)
+		})(ectx)
+		assert.NoError(t, err)
+
+		return rec, called
+	}
+
+	t.Run("WhenUserIsActive_ShouldCallNextWithUserID", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		sessionServiceMock.On("ValidateSession", mock.Anything, mock.Anything, sessionID).
+			Return(nil)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Status: models.ActiveStatus}, nil)
+
+		rec, called := serve(middleware, newToken(userID))
+
+		assert.True(t, called)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("WhenUserIsBlocked_ShouldRejectValidToken", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		sessionServiceMock.On("ValidateSession", mock.Anything, mock.Anything, sessionID).
+			Return(nil)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Status: models.BlockedStatus}, nil)
+
+		rec, called := serve(middleware, newToken(userID))
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Header().Get("Set-Cookie"), "Max-Age=0")
+	})
+
+	t.Run("WhenUserNoLongerExists_ShouldReturnUnauthorized", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		sessionServiceMock.On("ValidateSession", mock.Anything, mock.Anything, sessionID).
+			Return(nil)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(nil, nil)
+
+		rec, called := serve(middleware, newToken(userID))
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("WhenTokenIssuedBeforeSessionsRevoked_ShouldReturnUnauthorized", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		sessionServiceMock.On("ValidateSession", mock.Anything, mock.Anything, sessionID).
+			Return(nil)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		userID := uuid.New()
+		user := &models.User{Status: models.ActiveStatus}
+		user.RevokeSessions(time.Now())
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(user, nil)
+
+		rec, called := serve(middleware, newTokenIssuedAt(userID, time.Now().Add(-time.Minute)))
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Header().Get("Set-Cookie"), "Max-Age=0")
+	})
+
+	t.Run("WhenTokenIssuedAfterSessionsRevoked_ShouldCallNext", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		sessionServiceMock.On("ValidateSession", mock.Anything, mock.Anything, sessionID).
+			Return(nil)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		userID := uuid.New()
+		user := &models.User{Status: models.ActiveStatus}
+		user.RevokeSessions(time.Now().Add(-time.Minute))
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(user, nil)
+
+		rec, called := serve(middleware, newToken(userID))
+
+		assert.True(t, called)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("WhenUserMustChangePassword_ShouldOnlyAllowPasswordChangeRoutes", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		sessionServiceMock.On("ValidateSession", mock.Anything, mock.Anything, sessionID).
+			Return(nil)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Status: models.ActiveStatus, MustChangePassword: true}, nil)
+
+		for path, want := range map[string]int{
+			"/v1/orders":            http.StatusForbidden,
+			"/v1/users/me":          http.StatusOK,
+			"/v1/users/me/password": http.StatusOK,
+		} {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+newToken(userID))
+			rec := httptest.NewRecorder()
+			ectx := e.NewContext(req, rec)
+			ectx.SetPath(path)
+
+			err := middleware.Authenticate(func(ectx echo.Context) error {
+				return ectx.NoContent(http.StatusOK)
+			})(ectx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, rec.Code, path)
+		}
+	})
+
+	t.Run("WhenSessionWasRevoked_ShouldReturnUnauthorized", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		userID := uuid.New()
+		sessionServiceMock.On("ValidateSession", mock.Anything, userID, sessionID).
+			Return(models.ErrSessionRevoked)
+
+		rec, called := serve(middleware, newToken(userID))
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Header().Get("Set-Cookie"), "Max-Age=0")
+		userRepoMock.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenBearerTokenIsValid_ShouldAuthenticateWithoutCookie", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		userID := uuid.New()
+		sessionServiceMock.On("ValidateSession", mock.Anything, userID, sessionID).
+			Return(nil)
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Status: models.ActiveStatus}, nil)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/v1/users/me", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+newToken(userID))
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		var method request.AuthMethod
+		err := middleware.Authenticate(func(ectx echo.Context) error {
+			method, _ = request.AuthenticatedWith(ectx.Request().Context())
+			return ectx.NoContent(http.StatusOK)
+		})(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, request.BearerAuth, method)
+	})
+
+	t.Run("WhenBearerTokenIsInvalid_ShouldNotTouchCookies", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		middleware := &authMiddleware{ur: userRepoMock, ts: tokenService}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/v1/users/me", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer invalid-token")
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: newToken(uuid.New())})
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := middleware.Authenticate(func(ectx echo.Context) error {
+			return ectx.NoContent(http.StatusOK)
+		})(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+		assert.Empty(t, rec.Header().Get("Set-Cookie"))
+	})
+
+	t.Run("WhenAuthorizationSchemeIsNotBearer_ShouldReturnUnauthorized", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		middleware := &authMiddleware{ur: userRepoMock, ts: tokenService}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/v1/users/me", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Basic dXNlcjpwYXNz")
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		err := middleware.Authenticate(func(ectx echo.Context) error {
+			return ectx.NoContent(http.StatusOK)
+		})(ectx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		userRepoMock.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenTokenIsSignedWithSharedSecret_ShouldReturnUnauthorized", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		middleware := &authMiddleware{ur: userRepoMock, ts: tokenService}
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, models.TokenClaims{
+			UserID: uuid.New(),
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        sessionID.String(),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+			},
+		}).SignedString([]byte("secret"))
+		assert.NoError(t, err)
+
+		rec, called := serve(middleware, token)
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		userRepoMock.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenTokenIsInvalid_ShouldNotLoadUser", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		sessionServiceMock.On("ValidateSession", mock.Anything, mock.Anything, sessionID).
+			Return(nil)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		rec, called := serve(middleware, "invalid-token")
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		userRepoMock.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenRequestCarriesAPIKey_ShouldRejectInsteadOfUsingTheCookie", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		sessionServiceMock := new(mocks.SessionService)
+		middleware := &authMiddleware{ur: userRepoMock, ses: sessionServiceMock, ts: tokenService}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/v1/orders/123/status/delivered", nil)
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: newToken(uuid.New())})
+		req.Header.Set(APIKeyHeader, "ffk_dummy")
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		called := false
+		err := middleware.Authenticate(func(ectx echo.Context) error {
+			called = true
+			return ectx.NoContent(http.StatusOK)
+		})(ectx)
+
+		assert.NoError(t, err)
+		assert.False(t, called)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		sessionServiceMock.AssertNotCalled(t, "ValidateSession", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthMiddleware_AuthenticateWithScope(t *testing.T) {
+	config.Env.Session = config.Session{CookieName: "fast-feet.token"}
+
+	const key = "ffk_random-secret"
+
+	serve := func(middleware AuthMiddleware, key string) (*httptest.ResponseRecorder, request.AuthMethod) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		ectx := e.NewContext(req, rec)
+
+		var method request.AuthMethod
+		err := middleware.AuthenticateWithScope(models.Create, models.Orders)(func(ectx echo.Context) error {
+			method, _ = request.AuthenticatedWith(ectx.Request().Context())
+			return ectx.NoContent(http.StatusOK)
+		})(ectx)
+		assert.NoError(t, err)
+
+		return rec, method
+	}
+
+	t.Run("WhenKeyHasScope_ShouldActAsItsOwner", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		apiKeyServiceMock := new(mocks.APIKeyService)
+		middleware := &authMiddleware{ur: userRepoMock, aks: apiKeyServiceMock}
+
+		userID := uuid.New()
+		apiKeyServiceMock.On("AuthenticateAPIKey", mock.Anything, key).
+			Return(&models.APIKey{UserID: userID, Scopes: []models.APIKeyScope{{Action: models.Create, Resource: models.Orders}}}, nil)
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Status: models.ActiveStatus}, nil)
+
+		rec, method := serve(middleware, key)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, request.APIKeyAuth, method)
+	})
+
+	t.Run("WhenKeyLacksScope_ShouldReturnForbidden", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		apiKeyServiceMock := new(mocks.APIKeyService)
+		middleware := &authMiddleware{ur: userRepoMock, aks: apiKeyServiceMock}
+
+		apiKeyServiceMock.On("AuthenticateAPIKey", mock.Anything, key).
+			Return(&models.APIKey{UserID: uuid.New(), Scopes: []models.APIKeyScope{{Action: models.Read, Resource: models.Orders}}}, nil)
+
+		rec, _ := serve(middleware, key)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		userRepoMock.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenKeyIsInvalid_ShouldReturnUnauthorized", func(t *testing.T) {
+		apiKeyServiceMock := new(mocks.APIKeyService)
+		middleware := &authMiddleware{aks: apiKeyServiceMock}
+
+		apiKeyServiceMock.On("AuthenticateAPIKey", mock.Anything, key).
+			Return(nil, models.ErrAPIKeyInvalid)
+
+		rec, _ := serve(middleware, key)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("WhenNoKeyIsSent_ShouldFallBackToSessionToken", func(t *testing.T) {
+		apiKeyServiceMock := new(mocks.APIKeyService)
+		_, tokenService := newTokenService(t)
+		middleware := &authMiddleware{aks: apiKeyServiceMock, ts: tokenService}
+
+		rec, _ := serve(middleware, "")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		apiKeyServiceMock.AssertNotCalled(t, "AuthenticateAPIKey", mock.Anything, mock.Anything)
+	})
+}
+
+// newTokenService signs and verifies tokens with a throwaway Ed25519 key.
+func newTokenService(t *testing.T) (*services.KeySet, services.TokenService) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	err = os.WriteFile(filepath.Join(dir, "test-key.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+	assert.NoError(t, err)
+
+	keySet, err := services.LoadKeySet(dir, "test-key")
+	assert.NoError(t, err)
+
+	i := di.New()
+	di.Provide(i, func(d *di.Injector) (*services.KeySet, error) {
+		return keySet, nil
+	})
+
+	tokenService, err := services.NewTokenService(i)
+	assert.NoError(t, err)
+
+	return keySet, tokenService
+}
@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractor decides where the client IP comes from. Without trusted
+// proxies the IP of the connection is used and forwarding headers are
+// ignored, since any client can set them. With trusted proxies, given as IPs
+// or CIDR ranges, X-Forwarded-For is read up to the first address that is not
+// one of them.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted proxy %q: %w", proxy, err)
+		}
+
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// ClientInfo stores the caller IP and user agent in the request context, so
+// services can record where a session was started. The IP is taken from
+// RealIP, so the echo instance must have an IPExtractor set.
+func ClientInfo(e *echo.Echo) {
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ectx echo.Context) error {
+			req := ectx.Request()
+			ctx := request.WithClientInfo(req.Context(), ectx.RealIP(), req.UserAgent())
+			ectx.SetRequest(req.WithContext(ctx))
+
+			return next(ectx)
+		}
+	})
+}
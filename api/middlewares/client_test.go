@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/services"
+	"github.com/G-Villarinho/fast-feet-api/storage"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClientInfo(t *testing.T) {
+	config.Env.Login = config.Login{MaxAttemptsPerIP: 20}
+
+	// checkIP runs the login guard behind ClientInfo, so the test sees the
+	// counter key an attacker would be able to influence.
+	checkIP := func(t *testing.T, trustedProxies []string, header string) *mocks.AttemptCounter {
+		attemptCounterMock := new(mocks.AttemptCounter)
+
+		i := di.New()
+		di.Provide(i, func(d *di.Injector) (storage.AttemptCounter, error) {
+			return attemptCounterMock, nil
+		})
+
+		guard, err := services.NewLoginGuard(i)
+		assert.NoError(t, err)
+
+		e := echo.New()
+		e.IPExtractor, err = IPExtractor(trustedProxies)
+		assert.NoError(t, err)
+
+		ClientInfo(e)
+		e.POST("/v1/login", func(ectx echo.Context) error {
+			ip, _ := request.ClientInfo(ectx.Request().Context())
+			return guard.CheckIP(ectx.Request().Context(), ip)
+		})
+
+		attemptCounterMock.On("Count", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/login", nil)
+		req.RemoteAddr = "192.0.2.10:51234"
+		if header != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, header)
+			req.Header.Set(echo.HeaderXRealIP, header)
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		return attemptCounterMock
+	}
+
+	t.Run("WhenNoProxyIsTrusted_ShouldIgnoreSpoofedForwardedFor", func(t *testing.T) {
+		attemptCounterMock := checkIP(t, nil, "203.0.113.99")
+
+		attemptCounterMock.AssertCalled(t, "Count", mock.Anything, "login-attempts:ip:192.0.2.10")
+		attemptCounterMock.AssertNotCalled(t, "Count", mock.Anything, "login-attempts:ip:203.0.113.99")
+	})
+
+	t.Run("WhenRequestComesFromTrustedProxy_ShouldUseForwardedClient", func(t *testing.T) {
+		attemptCounterMock := checkIP(t, []string{"192.0.2.10"}, "198.51.100.7")
+
+		attemptCounterMock.AssertCalled(t, "Count", mock.Anything, "login-attempts:ip:198.51.100.7")
+	})
+
+	t.Run("WhenClientPrependsSpoofedAddress_ShouldUseTheAddressSeenByTheProxy", func(t *testing.T) {
+		attemptCounterMock := checkIP(t, []string{"192.0.2.0/24"}, "203.0.113.99, 198.51.100.7")
+
+		attemptCounterMock.AssertCalled(t, "Count", mock.Anything, "login-attempts:ip:198.51.100.7")
+		attemptCounterMock.AssertNotCalled(t, "Count", mock.Anything, "login-attempts:ip:203.0.113.99")
+	})
+}
+
+func TestIPExtractor(t *testing.T) {
+	t.Run("WhenTrustedProxyIsInvalid_ShouldReturnError", func(t *testing.T) {
+		extractor, err := IPExtractor([]string{"not-an-ip"})
+
+		assert.Nil(t, extractor)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/responses"
+	"github.com/labstack/echo/v4"
+)
+
+// CSRFHeader carries the double-submitted CSRF token.
+const CSRFHeader = "X-CSRF-Token"
+
+// CSRF rejects state-changing requests that carry a session cookie unless the
+// X-CSRF-Token header repeats the value of the CSRF cookie. Another origin
+// cannot read that cookie, so it cannot forge the header. Requests with an
+// Authorization header are left alone: browsers never attach it on their own,
+// and when it is present the cookie is not used to authenticate. An X-API-Key
+// header does not exempt a request that also carries a session cookie, since
+// this middleware runs before the route decides whether to accept the key.
+func CSRF(e *echo.Echo) {
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ectx echo.Context) error {
+			req := ectx.Request()
+
+			if isSafeMethod(req.Method) || req.Header.Get(echo.HeaderAuthorization) != "" || !hasSessionCookie(req) {
+				return next(ectx)
+			}
+
+			cookie, err := req.Cookie(config.Env.Session.CSRFCookieName)
+			if err != nil || cookie.Value == "" {
+				return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "Token CSRF ausente. Recarregue a página e tente novamente.")
+			}
+
+			header := req.Header.Get(CSRFHeader)
+			if subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+				return responses.NewCustomAPIErrorResponse(ectx, http.StatusForbidden, "Token CSRF inválido. Recarregue a página e tente novamente.")
+			}
+
+			return next(ectx)
+		}
+	})
+}
+
+// NewCSRFToken returns a random token for the CSRF cookie.
+func NewCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read random bytes: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func hasSessionCookie(req *http.Request) bool {
+	for _, name := range []string{config.Env.Session.CookieName, config.Env.Session.RefreshCookieName} {
+		if cookie, err := req.Cookie(name); err == nil && cookie.Value != "" {
+			return true
+		}
+	}
+
+	return false
+}
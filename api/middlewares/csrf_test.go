@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRF(t *testing.T) {
+	config.Env.Session.CookieName = "fast-feet.token"
+	config.Env.Session.RefreshCookieName = "fast-feet.refresh-token"
+	config.Env.Session.CSRFCookieName = "fast-feet.csrf-token"
+
+	e := echo.New()
+	CSRF(e)
+
+	handler := func(ectx echo.Context) error {
+		return ectx.NoContent(http.StatusOK)
+	}
+	e.GET("/v1/orders", handler)
+	e.POST("/v1/orders", handler)
+	e.DELETE("/v1/recipients/:id", handler)
+	e.POST("/v1/auth/refresh", handler)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("WhenForgedRequestCarriesOnlyCookies_ShouldReject", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://evil.example.com")
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: "session-token"})
+		req.AddCookie(&http.Cookie{Name: "fast-feet.csrf-token", Value: "csrf-token"})
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("WhenHeaderDoesNotMatchCookie_ShouldReject", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/v1/recipients/123", nil)
+		req.Header.Set(CSRFHeader, "guessed-token")
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: "session-token"})
+		req.AddCookie(&http.Cookie{Name: "fast-feet.csrf-token", Value: "csrf-token"})
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("WhenCSRFCookieIsMissing_ShouldReject", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+		req.Header.Set(CSRFHeader, "csrf-token")
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: "session-token"})
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("WhenRefreshCookieIsSentWithoutHeader_ShouldReject", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/refresh", nil)
+		req.AddCookie(&http.Cookie{Name: "fast-feet.refresh-token", Value: "refresh-token"})
+		req.AddCookie(&http.Cookie{Name: "fast-feet.csrf-token", Value: "csrf-token"})
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("WhenCookieRequestAddsAPIKeyHeader_ShouldStillReject", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+		req.Header.Set(APIKeyHeader, "ffk_dummy")
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: "session-token"})
+		req.AddCookie(&http.Cookie{Name: "fast-feet.csrf-token", Value: "csrf-token"})
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("WhenRequestUsesOnlyAnAPIKey_ShouldCallNext", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+		req.Header.Set(APIKeyHeader, "ffk_random-secret")
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("WhenHeaderMatchesCookie_ShouldCallNext", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+		req.Header.Set(CSRFHeader, "csrf-token")
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: "session-token"})
+		req.AddCookie(&http.Cookie{Name: "fast-feet.csrf-token", Value: "csrf-token"})
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("WhenMethodIsSafe_ShouldCallNext", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+		req.AddCookie(&http.Cookie{Name: "fast-feet.token", Value: "session-token"})
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("WhenRequestUsesBearerToken_ShouldCallNext", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer access-token")
+
+		rec := serve(req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -25,8 +25,15 @@ func main() {
 
 	if err := db.AutoMigrate(
 		&models.User{},
+		&models.ActivationToken{},
+		&models.APIKey{},
+		&models.AuditLog{},
 		&models.Order{},
+		&models.OrderEvent{},
+		&models.DeliveryAttempt{},
+		&models.EmailOutbox{},
 		&models.Recipient{},
+		&models.RecoveryCode{},
 	); err != nil {
 		log.Fatal("error to migrate: ", err)
 	}
@@ -14,20 +14,134 @@ type AuthService struct {
 	mock.Mock
 }
 
+// ActivateAccount provides a mock function with given fields: ctx, payload
+func (_m *AuthService) ActivateAccount(ctx context.Context, payload models.ActivateAccountPayload) error {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ActivateAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ActivateAccountPayload) error); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangePassword provides a mock function with given fields: ctx, payload
+func (_m *AuthService) ChangePassword(ctx context.Context, payload models.ChangePasswordPayload) (*models.LoginResponse, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 *models.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ChangePasswordPayload) (*models.LoginResponse, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ChangePasswordPayload) *models.LoginResponse); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ChangePasswordPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgotPassword provides a mock function with given fields: ctx, payload
+func (_m *AuthService) ForgotPassword(ctx context.Context, payload models.ForgotPasswordPayload) error {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForgotPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ForgotPasswordPayload) error); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Login provides a mock function with given fields: ctx, payload
-func (_m *AuthService) Login(ctx context.Context, payload models.LoginPayload) (*models.LoginResponse, error) {
+func (_m *AuthService) Login(ctx context.Context, payload models.LoginPayload) (*models.LoginResult, error) {
 	ret := _m.Called(ctx, payload)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
+	var r0 *models.LoginResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.LoginPayload) (*models.LoginResult, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.LoginPayload) *models.LoginResult); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.LoginResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.LoginPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, accessToken, refreshToken
+func (_m *AuthService) Logout(ctx context.Context, accessToken string, refreshToken string) error {
+	ret := _m.Called(ctx, accessToken, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, accessToken, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Refresh provides a mock function with given fields: ctx, payload
+func (_m *AuthService) Refresh(ctx context.Context, payload models.RefreshTokenPayload) (*models.LoginResponse, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
 	var r0 *models.LoginResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.LoginPayload) (*models.LoginResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, models.RefreshTokenPayload) (*models.LoginResponse, error)); ok {
 		return rf(ctx, payload)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, models.LoginPayload) *models.LoginResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, models.RefreshTokenPayload) *models.LoginResponse); ok {
 		r0 = rf(ctx, payload)
 	} else {
 		if ret.Get(0) != nil {
@@ -35,7 +149,85 @@ func (_m *AuthService) Login(ctx context.Context, payload models.LoginPayload) (
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, models.LoginPayload) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, models.RefreshTokenPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: ctx, payload
+func (_m *AuthService) ResetPassword(ctx context.Context, payload models.ResetPasswordPayload) error {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ResetPasswordPayload) error); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartTwoFactorEnrollment provides a mock function with given fields: ctx, payload
+func (_m *AuthService) StartTwoFactorEnrollment(ctx context.Context, payload models.TwoFactorChallengePayload) (*models.TwoFactorEnrollmentResponse, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartTwoFactorEnrollment")
+	}
+
+	var r0 *models.TwoFactorEnrollmentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.TwoFactorChallengePayload) (*models.TwoFactorEnrollmentResponse, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.TwoFactorChallengePayload) *models.TwoFactorEnrollmentResponse); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TwoFactorEnrollmentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.TwoFactorChallengePayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyTwoFactor provides a mock function with given fields: ctx, payload
+func (_m *AuthService) VerifyTwoFactor(ctx context.Context, payload models.VerifyTwoFactorPayload) (*models.TwoFactorLoginResponse, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyTwoFactor")
+	}
+
+	var r0 *models.TwoFactorLoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.VerifyTwoFactorPayload) (*models.TwoFactorLoginResponse, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.VerifyTwoFactorPayload) *models.TwoFactorLoginResponse); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TwoFactorLoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.VerifyTwoFactorPayload) error); ok {
 		r1 = rf(ctx, payload)
 	} else {
 		r1 = ret.Error(1)
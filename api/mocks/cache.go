@@ -14,6 +14,24 @@ type Cache struct {
 	mock.Mock
 }
 
+// AddToSet provides a mock function with given fields: ctx, key, member, ttl
+func (_m *Cache) AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, member, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = rf(ctx, key, member, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, key
 func (_m *Cache) Delete(ctx context.Context, key string) error {
 	ret := _m.Called(ctx, key)
@@ -50,6 +68,49 @@ func (_m *Cache) Get(ctx context.Context, key string, target interface{}) error
 	return r0
 }
 
+// GetDel provides a mock function with given fields: ctx, key, target
+func (_m *Cache) GetDel(ctx context.Context, key string, target interface{}) error {
+	ret := _m.Called(ctx, key, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, key, target)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveFromSet provides a mock function with given fields: ctx, key, members
+func (_m *Cache) RemoveFromSet(ctx context.Context, key string, members ...string) error {
+	_va := make([]interface{}, len(members))
+	for _i := range members {
+		_va[_i] = members[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, key)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFromSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) error); ok {
+		r0 = rf(ctx, key, members...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Set provides a mock function with given fields: ctx, key, value, ttl
 func (_m *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	ret := _m.Called(ctx, key, value, ttl)
@@ -68,6 +129,36 @@ func (_m *Cache) Set(ctx context.Context, key string, value interface{}, ttl tim
 	return r0
 }
 
+// SetMembers provides a mock function with given fields: ctx, key
+func (_m *Cache) SetMembers(ctx context.Context, key string) ([]string, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMembers")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewCache creates a new instance of Cache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCache(t interface {
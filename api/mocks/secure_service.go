@@ -59,6 +59,34 @@ func (_m *SecureService) CreatePassword(ctx context.Context) (string, error) {
 	return r0, r1
 }
 
+// CreateToken provides a mock function with given fields: ctx
+func (_m *SecureService) CreateToken(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HashPassword provides a mock function with given fields: ctx, password
 func (_m *SecureService) HashPassword(ctx context.Context, password string) (string, error) {
 	ret := _m.Called(ctx, password)
@@ -42,6 +42,56 @@ func (_m *TokenService) CreateToken(ctx context.Context, payload models.TokenPay
 	return r0, r1
 }
 
+// GetJWKS provides a mock function with given fields: ctx
+func (_m *TokenService) GetJWKS(ctx context.Context) *models.JWKS {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJWKS")
+	}
+
+	var r0 *models.JWKS
+	if rf, ok := ret.Get(0).(func(context.Context) *models.JWKS); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.JWKS)
+		}
+	}
+
+	return r0
+}
+
+// ParseToken provides a mock function with given fields: ctx, token
+func (_m *TokenService) ParseToken(ctx context.Context, token string) (*models.TokenClaims, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseToken")
+	}
+
+	var r0 *models.TokenClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.TokenClaims, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.TokenClaims); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TokenClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTokenService creates a new instance of TokenService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTokenService(t interface {
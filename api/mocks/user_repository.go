@@ -6,9 +6,8 @@ import (
 	context "context"
 
 	models "github.com/G-Villarinho/fast-feet-api/models"
-	mock "github.com/stretchr/testify/mock"
-
 	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -52,6 +51,36 @@ func (_m *UserRepository) DeleteUser(ctx context.Context, ID uuid.UUID) error {
 	return r0
 }
 
+// GetDeliveryMenPagedList provides a mock function with given fields: ctx, pagination
+func (_m *UserRepository) GetDeliveryMenPagedList(ctx context.Context, pagination *models.DeliveryManPagination) (*models.PaginatedResponse[models.User], error) {
+	ret := _m.Called(ctx, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveryMenPagedList")
+	}
+
+	var r0 *models.PaginatedResponse[models.User]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DeliveryManPagination) (*models.PaginatedResponse[models.User], error)); ok {
+		return rf(ctx, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DeliveryManPagination) *models.PaginatedResponse[models.User]); ok {
+		r0 = rf(ctx, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PaginatedResponse[models.User])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.DeliveryManPagination) error); ok {
+		r1 = rf(ctx, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByCPF provides a mock function with given fields: ctx, CPF
 func (_m *UserRepository) GetUserByCPF(ctx context.Context, CPF string) (*models.User, error) {
 	ret := _m.Called(ctx, CPF)
@@ -142,6 +171,82 @@ func (_m *UserRepository) GetUserByID(ctx context.Context, ID uuid.UUID) (*model
 	return r0, r1
 }
 
+// GetUserByIDForUpdate provides a mock function with given fields: ctx, ID
+func (_m *UserRepository) GetUserByIDForUpdate(ctx context.Context, ID uuid.UUID) (*models.User, error) {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByIDForUpdate")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.User, error)); ok {
+		return rf(ctx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.User); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, user
+func (_m *UserRepository) UpdateUser(ctx context.Context, user models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseTwoFactorStep provides a mock function with given fields: ctx, ID, step
+func (_m *UserRepository) UseTwoFactorStep(ctx context.Context, ID uuid.UUID, step int64) (bool, error) {
+	ret := _m.Called(ctx, ID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTwoFactorStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) (bool, error)); ok {
+		return rf(ctx, ID, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) bool); ok {
+		r0 = rf(ctx, ID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int64) error); ok {
+		r1 = rf(ctx, ID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
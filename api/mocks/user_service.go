@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	models "github.com/G-Villarinho/fast-feet-api/models"
+	uuid "github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +15,24 @@ type UserService struct {
 	mock.Mock
 }
 
+// BlockUser provides a mock function with given fields: ctx, userID, payload
+func (_m *UserService) BlockUser(ctx context.Context, userID uuid.UUID, payload models.BlockUserPayload) error {
+	ret := _m.Called(ctx, userID, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.BlockUserPayload) error); ok {
+		r0 = rf(ctx, userID, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateAdmin provides a mock function with given fields: ctx, payload
 func (_m *UserService) CreateAdmin(ctx context.Context, payload models.CreateUserPayload) error {
 	ret := _m.Called(ctx, payload)
@@ -50,6 +69,114 @@ func (_m *UserService) CreateDeliveryMan(ctx context.Context, payload models.Cre
 	return r0
 }
 
+// DeactivateDeliveryMan provides a mock function with given fields: ctx, deliveryManID
+func (_m *UserService) DeactivateDeliveryMan(ctx context.Context, deliveryManID uuid.UUID) error {
+	ret := _m.Called(ctx, deliveryManID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateDeliveryMan")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, deliveryManID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeliveryMan provides a mock function with given fields: ctx, deliveryManID
+func (_m *UserService) GetDeliveryMan(ctx context.Context, deliveryManID uuid.UUID) (*models.DeliveryManResponse, error) {
+	ret := _m.Called(ctx, deliveryManID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveryMan")
+	}
+
+	var r0 *models.DeliveryManResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*models.DeliveryManResponse, error)); ok {
+		return rf(ctx, deliveryManID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *models.DeliveryManResponse); ok {
+		r0 = rf(ctx, deliveryManID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeliveryManResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, deliveryManID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeliveryManOrders provides a mock function with given fields: ctx, deliveryManID, pagination
+func (_m *UserService) GetDeliveryManOrders(ctx context.Context, deliveryManID uuid.UUID, pagination *models.Pagination) (*models.PaginatedResponse[*models.OrderResponse], error) {
+	ret := _m.Called(ctx, deliveryManID, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveryManOrders")
+	}
+
+	var r0 *models.PaginatedResponse[*models.OrderResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *models.Pagination) (*models.PaginatedResponse[*models.OrderResponse], error)); ok {
+		return rf(ctx, deliveryManID, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *models.Pagination) *models.PaginatedResponse[*models.OrderResponse]); ok {
+		r0 = rf(ctx, deliveryManID, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PaginatedResponse[*models.OrderResponse])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *models.Pagination) error); ok {
+		r1 = rf(ctx, deliveryManID, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeliveryMen provides a mock function with given fields: ctx, pagination
+func (_m *UserService) GetDeliveryMen(ctx context.Context, pagination *models.DeliveryManPagination) (*models.PaginatedResponse[*models.DeliveryManResponse], error) {
+	ret := _m.Called(ctx, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveryMen")
+	}
+
+	var r0 *models.PaginatedResponse[*models.DeliveryManResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DeliveryManPagination) (*models.PaginatedResponse[*models.DeliveryManResponse], error)); ok {
+		return rf(ctx, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.DeliveryManPagination) *models.PaginatedResponse[*models.DeliveryManResponse]); ok {
+		r0 = rf(ctx, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PaginatedResponse[*models.DeliveryManResponse])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.DeliveryManPagination) error); ok {
+		r1 = rf(ctx, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUser provides a mock function with given fields: ctx
 func (_m *UserService) GetUser(ctx context.Context) (*models.UserResponse, error) {
 	ret := _m.Called(ctx)
@@ -80,6 +207,54 @@ func (_m *UserService) GetUser(ctx context.Context) (*models.UserResponse, error
 	return r0, r1
 }
 
+// UnblockUser provides a mock function with given fields: ctx, userID
+func (_m *UserService) UnblockUser(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnblockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDeliveryMan provides a mock function with given fields: ctx, deliveryManID, payload
+func (_m *UserService) UpdateDeliveryMan(ctx context.Context, deliveryManID uuid.UUID, payload models.UpdateUserPayload) (*models.DeliveryManResponse, error) {
+	ret := _m.Called(ctx, deliveryManID, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeliveryMan")
+	}
+
+	var r0 *models.DeliveryManResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.UpdateUserPayload) (*models.DeliveryManResponse, error)); ok {
+		return rf(ctx, deliveryManID, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, models.UpdateUserPayload) *models.DeliveryManResponse); ok {
+		r0 = rf(ctx, deliveryManID, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeliveryManResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, models.UpdateUserPayload) error); ok {
+		r1 = rf(ctx, deliveryManID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserService(t interface {
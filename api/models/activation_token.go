@@ -0,0 +1,65 @@
+package models
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrActivationTokenInvalid = errors.New("activation token is invalid or was already used")
+	ErrActivationTokenExpired = errors.New("activation token has expired")
+)
+
+// ActivationToken is the one-time token sent in the welcome email. Only its
+// SHA-256 hash is stored, so a database leak does not expose usable links.
+type ActivationToken struct {
+	BaseModel
+	UserID    uuid.UUID    `gorm:"type:uuid;not null;index"`
+	TokenHash string       `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time    `gorm:"not null"`
+	UsedAt    sql.NullTime `gorm:"default:null"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+type ActivateAccountPayload struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,password"`
+}
+
+func NewActivationToken(userID uuid.UUID, token string, now time.Time, ttl time.Duration) *ActivationToken {
+	return &ActivationToken{
+		BaseModel: BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: now,
+		},
+		UserID:    userID,
+		TokenHash: HashToken(token),
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// HashToken returns the hex encoded SHA-256 of a token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Use consumes the token. A token can only be used once and before it expires.
+func (a *ActivationToken) Use(now time.Time) error {
+	if a.UsedAt.Valid {
+		return ErrActivationTokenInvalid
+	}
+
+	if !now.Before(a.ExpiresAt) {
+		return ErrActivationTokenExpired
+	}
+
+	a.UsedAt = sql.NullTime{Time: now, Valid: true}
+	return nil
+}
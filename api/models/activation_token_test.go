@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivationToken_Use(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("ShouldStoreOnlyTheTokenHash", func(t *testing.T) {
+		token := NewActivationToken(uuid.New(), "secret-token", now, time.Hour)
+
+		assert.NotContains(t, token.TokenHash, "secret-token")
+		assert.Equal(t, HashToken("secret-token"), token.TokenHash)
+		assert.Equal(t, now.Add(time.Hour), token.ExpiresAt)
+	})
+
+	t.Run("WhenTokenIsValid_ShouldMarkItAsUsed", func(t *testing.T) {
+		token := NewActivationToken(uuid.New(), "secret-token", now, time.Hour)
+
+		err := token.Use(now.Add(time.Minute))
+
+		assert.NoError(t, err)
+		assert.True(t, token.UsedAt.Valid)
+	})
+
+	t.Run("WhenTokenWasAlreadyUsed_ShouldReturnErrActivationTokenInvalid", func(t *testing.T) {
+		token := NewActivationToken(uuid.New(), "secret-token", now, time.Hour)
+		assert.NoError(t, token.Use(now))
+
+		err := token.Use(now.Add(time.Minute))
+
+		assert.ErrorIs(t, err, ErrActivationTokenInvalid)
+	})
+
+	t.Run("WhenTokenIsExpired_ShouldReturnErrActivationTokenExpired", func(t *testing.T) {
+		token := NewActivationToken(uuid.New(), "secret-token", now, time.Hour)
+
+		err := token.Use(now.Add(time.Hour))
+
+		assert.ErrorIs(t, err, ErrActivationTokenExpired)
+		assert.False(t, token.UsedAt.Valid)
+	})
+}
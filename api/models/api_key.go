@@ -0,0 +1,161 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAPIKeyNotFound          = errors.New("api key not found")
+	ErrAPIKeyInvalid           = errors.New("api key is invalid, expired or revoked")
+	ErrAPIKeyExpirationInvalid = errors.New("api key expiration must be in the future")
+	ErrAPIKeyScopeUnsupported  = errors.New("api key scope is not accepted by any route")
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to spot.
+const APIKeyPrefix = "ffk_"
+
+// apiKeyDisplayLength is how much of the key is kept in clear to tell keys
+// apart in listings.
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// apiKeyScopes lists, per resource, the actions that routes accept from an
+// API key. A scope outside of it would authenticate nowhere.
+var apiKeyScopes = map[Resource][]Action{
+	Recipients: {Create, Read, Update, Delete, Manage},
+	Orders:     {Create, Read, Cancel, Manage},
+}
+
+// APIKeyScope allows one action on one resource. Manage allows every action
+// on the resource.
+type APIKeyScope struct {
+	Action   Action   `json:"action" validate:"required,oneof=create read update delete manage cancel"`
+	Resource Resource `json:"resource" validate:"required,oneof=Recipients Orders"`
+}
+
+// Supported reports whether some route accepts an API key with the scope.
+func (s APIKeyScope) Supported() bool {
+	return slices.Contains(apiKeyScopes[s.Resource], s.Action)
+}
+
+// APIKey authenticates a machine-to-machine client as UserID, limited to its
+// scopes. A key with an Integration belongs to that integration rather than
+// to the user who registered it, so every user allowed to manage API keys can
+// see and revoke it. Only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	BaseModel
+	Name        string        `gorm:"type:varchar(100);not null"`
+	Integration *string       `gorm:"type:varchar(100);default:null;index"`
+	Prefix      string        `gorm:"type:varchar(16);not null"`
+	KeyHash     string        `gorm:"type:char(64);not null;uniqueIndex"`
+	Scopes      []APIKeyScope `gorm:"type:jsonb;serializer:json;not null"`
+	ExpiresAt   sql.NullTime  `gorm:"default:null"`
+	RevokedAt   sql.NullTime  `gorm:"default:null"`
+	LastUsedAt  sql.NullTime  `gorm:"default:null"`
+
+	UserID uuid.UUID `gorm:"type:uuid;not null;index"`
+}
+
+type CreateAPIKeyPayload struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Integration names the system that owns the key. Leave it empty for a
+	// personal key.
+	Integration *string       `json:"integration" validate:"omitempty,max=100"`
+	Scopes      []APIKeyScope `json:"scopes" validate:"required,min=1,max=20,dive"`
+	ExpiresAt   *time.Time    `json:"expiresAt"`
+}
+
+type APIKeyResponse struct {
+	ID          uuid.UUID     `json:"id"`
+	Name        string        `json:"name"`
+	Integration *string       `json:"integration,omitempty"`
+	Prefix      string        `json:"prefix"`
+	Scopes      []APIKeyScope `json:"scopes"`
+	UserID      uuid.UUID     `json:"userId"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	ExpiresAt   *time.Time    `json:"expiresAt,omitempty"`
+	RevokedAt   *time.Time    `json:"revokedAt,omitempty"`
+	LastUsedAt  *time.Time    `json:"lastUsedAt,omitempty"`
+}
+
+// CreateAPIKeyResponse is the only response that carries the key itself.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func (p *CreateAPIKeyPayload) ToAPIKey(userID uuid.UUID, key string, now time.Time) *APIKey {
+	apiKey := &APIKey{
+		BaseModel: BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: now,
+		},
+		Name:        p.Name,
+		Integration: p.Integration,
+		Prefix:      key[:apiKeyDisplayLength],
+		KeyHash:     HashToken(key),
+		Scopes:      p.Scopes,
+		UserID:      userID,
+	}
+
+	if p.ExpiresAt != nil {
+		apiKey.ExpiresAt = sql.NullTime{Time: p.ExpiresAt.UTC(), Valid: true}
+	}
+
+	return apiKey
+}
+
+// Active reports whether the key can still authenticate requests.
+func (a *APIKey) Active(now time.Time) bool {
+	if a.RevokedAt.Valid {
+		return false
+	}
+
+	return !a.ExpiresAt.Valid || now.Before(a.ExpiresAt.Time)
+}
+
+// Allows reports whether a scope of the key covers the action on the
+// resource.
+func (a *APIKey) Allows(action Action, resource Resource) bool {
+	for _, scope := range a.Scopes {
+		if scope.Resource == resource && (scope.Action == action || scope.Action == Manage) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *APIKey) Revoke(now time.Time) {
+	a.RevokedAt = sql.NullTime{Time: now, Valid: true}
+}
+
+func (a *APIKey) ToAPIKeyResponse() *APIKeyResponse {
+	response := &APIKeyResponse{
+		ID:          a.ID,
+		Name:        a.Name,
+		Integration: a.Integration,
+		Prefix:      a.Prefix,
+		Scopes:      a.Scopes,
+		UserID:      a.UserID,
+		CreatedAt:   a.CreatedAt,
+	}
+
+	if a.ExpiresAt.Valid {
+		response.ExpiresAt = &a.ExpiresAt.Time
+	}
+
+	if a.RevokedAt.Valid {
+		response.RevokedAt = &a.RevokedAt.Time
+	}
+
+	if a.LastUsedAt.Valid {
+		response.LastUsedAt = &a.LastUsedAt.Time
+	}
+
+	return response
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_Allows(t *testing.T) {
+	apiKey := &APIKey{
+		Scopes: []APIKeyScope{
+			{Action: Create, Resource: Orders},
+			{Action: Manage, Resource: Recipients},
+		},
+	}
+
+	t.Run("WhenScopeMatches_ShouldAllow", func(t *testing.T) {
+		assert.True(t, apiKey.Allows(Create, Orders))
+	})
+
+	t.Run("WhenScopeIsManage_ShouldAllowEveryActionOnItsResource", func(t *testing.T) {
+		assert.True(t, apiKey.Allows(Delete, Recipients))
+		assert.True(t, apiKey.Allows(Read, Recipients))
+	})
+
+	t.Run("WhenNoScopeMatches_ShouldDeny", func(t *testing.T) {
+		assert.False(t, apiKey.Allows(Read, Orders))
+		assert.False(t, apiKey.Allows(Create, Users))
+	})
+}
+
+func TestAPIKeyScope_Supported(t *testing.T) {
+	t.Run("WhenARouteChecksTheScope_ShouldBeSupported", func(t *testing.T) {
+		assert.True(t, APIKeyScope{Action: Cancel, Resource: Orders}.Supported())
+		assert.True(t, APIKeyScope{Action: Manage, Resource: Recipients}.Supported())
+	})
+
+	t.Run("WhenNoRouteChecksTheScope_ShouldNotBeSupported", func(t *testing.T) {
+		assert.False(t, APIKeyScope{Action: UpdateStatus, Resource: Orders}.Supported())
+		assert.False(t, APIKeyScope{Action: Cancel, Resource: Recipients}.Supported())
+		assert.False(t, APIKeyScope{Action: Manage, Resource: Users}.Supported())
+		assert.False(t, APIKeyScope{Action: Read, Resource: Emails}.Supported())
+	})
+}
+
+func TestAPIKey_Active(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+
+	newAPIKey := func() *APIKey {
+		payload := CreateAPIKeyPayload{
+			Name:      "erp",
+			Scopes:    []APIKeyScope{{Action: Read, Resource: Orders}},
+			ExpiresAt: &expiresAt,
+		}
+
+		return payload.ToAPIKey(uuid.New(), APIKeyPrefix+"secret-key-value", now)
+	}
+
+	t.Run("ShouldStoreOnlyTheKeyHashAndPrefix", func(t *testing.T) {
+		apiKey := newAPIKey()
+
+		assert.Equal(t, HashToken(APIKeyPrefix+"secret-key-value"), apiKey.KeyHash)
+		assert.Equal(t, APIKeyPrefix+"secret-k", apiKey.Prefix)
+	})
+
+	t.Run("WhenKeyIsNotExpired_ShouldBeActive", func(t *testing.T) {
+		assert.True(t, newAPIKey().Active(now.Add(time.Minute)))
+	})
+
+	t.Run("WhenKeyIsExpired_ShouldNotBeActive", func(t *testing.T) {
+		assert.False(t, newAPIKey().Active(expiresAt))
+	})
+
+	t.Run("WhenKeyWasRevoked_ShouldNotBeActive", func(t *testing.T) {
+		apiKey := newAPIKey()
+		apiKey.Revoke(now)
+
+		assert.False(t, apiKey.Active(now.Add(time.Minute)))
+	})
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AccountLockedAction     AuditAction = "ACCOUNT_LOCKED"
+	TwoFactorEnabledAction  AuditAction = "TWO_FACTOR_ENABLED"
+	TwoFactorDisabledAction AuditAction = "TWO_FACTOR_DISABLED"
+	RecoveryCodeUsedAction  AuditAction = "RECOVERY_CODE_USED"
+	APIKeyCreatedAction     AuditAction = "API_KEY_CREATED"
+	APIKeyRevokedAction     AuditAction = "API_KEY_REVOKED"
+	UserBlockedAction       AuditAction = "USER_BLOCKED"
+	UserUnblockedAction     AuditAction = "USER_UNBLOCKED"
+)
+
+// AuditLog records a security relevant event. UserID is the account the event
+// is about, which is not always the one who caused it.
+type AuditLog struct {
+	BaseModel
+	Action    AuditAction `gorm:"not null;index"`
+	UserID    *uuid.UUID  `gorm:"type:uuid;default:null;index"`
+	IP        string      `gorm:"not null;default:''"`
+	UserAgent string      `gorm:"not null;default:''"`
+	Details   *string     `gorm:"default:null"`
+}
+
+func NewAuditLog(action AuditAction, userID *uuid.UUID, ip, userAgent string, details *string) *AuditLog {
+	return &AuditLog{
+		BaseModel: BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+		},
+		Action:    action,
+		UserID:    userID,
+		IP:        ip,
+		UserAgent: userAgent,
+		Details:   details,
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -10,15 +11,26 @@ import (
 var (
 	ErrInvalidCredentials     = errors.New("invalid credencials: CPF or password wrong")
 	ErrTokenNotFoundInContext = errors.New("token not found in the context")
+	ErrInvalidPassword        = errors.New("current password is wrong")
+	ErrSamePassword           = errors.New("new password must differ from the current one")
+	ErrResetTokenInvalid      = errors.New("password reset token is invalid, expired or was already used")
 )
 
+// TokenClaims carries the session ID in the standard jti claim.
 type TokenClaims struct {
 	UserID uuid.UUID `json:"sub"`
 	jwt.RegisteredClaims
 }
 
+func (c *TokenClaims) SessionID() (uuid.UUID, error) {
+	return uuid.Parse(c.ID)
+}
+
 type TokenPayload struct {
-	UserID uuid.UUID `json:"sub"`
+	UserID    uuid.UUID `json:"sub"`
+	SessionID uuid.UUID `json:"jti"`
+	IssuedAt  time.Time `json:"iat"`
+	ExpiresAt time.Time `json:"exp"`
 }
 
 type LoginPayload struct {
@@ -27,5 +39,34 @@ type LoginPayload struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	// ExpiresIn is the lifetime of Token in seconds.
+	ExpiresIn int64 `json:"expiresIn"`
+}
+
+// LoginResult holds the session started by a login or, for users with
+// two-factor authentication, the challenge to answer before it is started.
+type LoginResult struct {
+	Session   *LoginResponse
+	Challenge *TwoFactorChallengeResponse
+}
+
+type ChangePasswordPayload struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,password"`
+}
+
+type ForgotPasswordPayload struct {
+	Login string `json:"login" validate:"required,max=255"`
+}
+
+type ResetPasswordPayload struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,password"`
+}
+
+// PasswordReset is the value stored in the cache under the hash of a reset token.
+type PasswordReset struct {
+	UserID uuid.UUID `json:"userId"`
 }
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryAttemptReason string
+
+const (
+	RecipientAbsent    DeliveryAttemptReason = "RECIPIENT_ABSENT"
+	WrongAddress       DeliveryAttemptReason = "WRONG_ADDRESS"
+	AddressNotFound    DeliveryAttemptReason = "ADDRESS_NOT_FOUND"
+	RefusedByRecipient DeliveryAttemptReason = "REFUSED_BY_RECIPIENT"
+	OtherReason        DeliveryAttemptReason = "OTHER"
+)
+
+type DeliveryAttempt struct {
+	BaseModel
+	OrderID     uuid.UUID             `gorm:"type:uuid;not null;index"`
+	Reason      DeliveryAttemptReason `gorm:"not null"`
+	Note        *string               `gorm:"default:null"`
+	AttemptedAt time.Time             `gorm:"not null"`
+	PhotoKey    *string               `gorm:"type:varchar(255);default:null"`
+	ThumbKey    *string               `gorm:"type:varchar(255);default:null"`
+
+	DeliverymanID uuid.UUID `gorm:"type:uuid;not null"`
+	Deliveryman   User      `gorm:"foreignKey:DeliverymanID;references:ID"`
+}
+
+type FailDeliveryPayload struct {
+	OrderEventMetadata
+	Reason DeliveryAttemptReason `json:"reason" form:"reason" validate:"required,oneof=RECIPIENT_ABSENT WRONG_ADDRESS ADDRESS_NOT_FOUND REFUSED_BY_RECIPIENT OTHER"`
+	Photo  *multipart.FileHeader `json:"-" form:"image"`
+}
+
+type FailDeliveryResponse struct {
+	Status           OrderStatus `json:"status"`
+	DeliveryAttempts int         `json:"deliveryAttempts"`
+}
+
+func (p *FailDeliveryPayload) ToDeliveryAttempt(orderID, deliverymanID uuid.UUID) *DeliveryAttempt {
+	now := time.Now().UTC()
+
+	return &DeliveryAttempt{
+		BaseModel: BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: now,
+		},
+		OrderID:       orderID,
+		DeliverymanID: deliverymanID,
+		Reason:        p.Reason,
+		Note:          p.Note,
+		AttemptedAt:   now,
+	}
+}
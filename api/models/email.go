@@ -1,10 +1,23 @@
 package models
 
-import "github.com/G-Villarinho/fast-feet-api/templates"
+import (
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/templates"
+)
 
 type SendEmailPayload struct {
-	To           string
-	Subject      string
-	TemplateName templates.TemplateName
-	Params       map[string]string
+	To      string
+	Subject string
+	Params  templates.Params
+}
+
+// EmailMessage is a fully rendered email, ready to be handed to a transport.
+type EmailMessage struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	SentAt  time.Time
 }
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmailNotFound    = errors.New("email not found in outbox")
+	ErrEmailAlreadySent = errors.New("email was already sent")
+	ErrEmailSending     = errors.New("email is being sent")
+	ErrEmailRedacted    = errors.New("email params were redacted")
+)
+
+type EmailOutboxStatus string
+
+const (
+	EmailPending EmailOutboxStatus = "PENDING"
+	// EmailSending marks an email claimed by a worker. NextAttemptAt holds the
+	// end of its lease; once it passes, the email is due again.
+	EmailSending EmailOutboxStatus = "SENDING"
+	EmailSent    EmailOutboxStatus = "SENT"
+	EmailDead    EmailOutboxStatus = "DEAD"
+)
+
+type EmailOutbox struct {
+	BaseModel
+	To            string                 `gorm:"not null"`
+	Subject       string                 `gorm:"not null"`
+	TemplateName  templates.TemplateName `gorm:"not null"`
+	Params        json.RawMessage        `gorm:"type:jsonb"`
+	Status        EmailOutboxStatus      `gorm:"not null;default:'PENDING';index"`
+	Attempts      int                    `gorm:"not null;default:0"`
+	NextAttemptAt time.Time              `gorm:"not null;index"`
+	LastError     *string                `gorm:"default:null"`
+	SentAt        sql.NullTime           `gorm:"default:null"`
+}
+
+type EmailOutboxPagination struct {
+	Pagination
+	Status *EmailOutboxStatus `json:"status"`
+}
+
+type EmailOutboxResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	To            string                 `json:"to"`
+	Subject       string                 `json:"subject"`
+	TemplateName  templates.TemplateName `json:"templateName"`
+	Status        EmailOutboxStatus      `json:"status"`
+	Attempts      int                    `json:"attempts"`
+	NextAttemptAt time.Time              `json:"nextAttemptAt"`
+	LastError     *string                `json:"lastError,omitempty"`
+	SentAt        *time.Time             `json:"sentAt,omitempty"`
+	CreatedAt     time.Time              `json:"createdAt"`
+}
+
+func NewEmailOutbox(payload SendEmailPayload) (*EmailOutbox, error) {
+	params, err := json.Marshal(payload.Params)
+	if err != nil {
+		return nil, fmt.Errorf("encode email params: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	return &EmailOutbox{
+		BaseModel: BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: now,
+		},
+		To:            payload.To,
+		Subject:       payload.Subject,
+		TemplateName:  payload.Params.TemplateName(),
+		Params:        params,
+		Status:        EmailPending,
+		NextAttemptAt: now,
+	}, nil
+}
+
+func (e *EmailOutbox) ToSendEmailPayload() (SendEmailPayload, error) {
+	params, err := templates.DecodeParams(e.TemplateName, e.Params)
+	if err != nil {
+		return SendEmailPayload{}, err
+	}
+
+	return SendEmailPayload{
+		To:      e.To,
+		Subject: e.Subject,
+		Params:  params,
+	}, nil
+}
+
+// Claim leases the email to a worker until leaseUntil, so it is not picked up
+// by another batch while it is being sent.
+func (e *EmailOutbox) Claim(leaseUntil time.Time) {
+	e.Status = EmailSending
+	e.NextAttemptAt = leaseUntil
+}
+
+func (e *EmailOutbox) MarkSent(now time.Time) {
+	e.Status = EmailSent
+	e.Attempts++
+	e.LastError = nil
+	e.SentAt = sql.NullTime{Time: now, Valid: true}
+	e.redactParams()
+}
+
+// MarkFailed records a failed delivery. The next attempt is scheduled with an
+// exponential backoff (base, 2*base, 4*base... capped at maxDelay) and the
+// email is moved to the dead-letter state once maxAttempts is reached.
+func (e *EmailOutbox) MarkFailed(cause error, now time.Time, maxAttempts int, base, maxDelay time.Duration) {
+	e.Attempts++
+
+	message := cause.Error()
+	e.LastError = &message
+
+	if e.Attempts >= maxAttempts {
+		e.Status = EmailDead
+		e.redactParams()
+		return
+	}
+
+	e.Status = EmailPending
+
+	delay := base
+	for i := 1; i < e.Attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	e.NextAttemptAt = now.Add(min(delay, maxDelay))
+}
+
+// MarkDead moves the email straight to the dead-letter state, for failures
+// that retrying cannot fix.
+func (e *EmailOutbox) MarkDead(cause error) {
+	message := cause.Error()
+	e.LastError = &message
+	e.Status = EmailDead
+	e.redactParams()
+}
+
+// Retry puts a failed email back in the queue with a fresh attempt budget.
+func (e *EmailOutbox) Retry(now time.Time) error {
+	if e.Status == EmailSent {
+		return ErrEmailAlreadySent
+	}
+
+	if e.Status == EmailSending {
+		return ErrEmailSending
+	}
+
+	if e.Status == EmailDead && templates.HasSecrets(e.TemplateName) {
+		return ErrEmailRedacted
+	}
+
+	e.Status = EmailPending
+	e.Attempts = 0
+	e.NextAttemptAt = now
+
+	return nil
+}
+
+// redactParams removes credentials, such as one-time tokens, from the stored
+// params once the email will not be sent again. Params that cannot be
+// redacted are dropped rather than kept in clear.
+func (e *EmailOutbox) redactParams() {
+	if !templates.HasSecrets(e.TemplateName) {
+		return
+	}
+
+	params, err := templates.DecodeParams(e.TemplateName, e.Params)
+	if err != nil {
+		e.Params = nil
+		return
+	}
+
+	secret, ok := params.(templates.SecretParams)
+	if !ok {
+		e.Params = nil
+		return
+	}
+
+	redacted, err := json.Marshal(secret.Redacted())
+	if err != nil {
+		e.Params = nil
+		return
+	}
+
+	e.Params = redacted
+}
+
+func (e *EmailOutbox) ToEmailOutboxResponse() *EmailOutboxResponse {
+	var sentAt *time.Time
+	if e.SentAt.Valid {
+		sentAt = &e.SentAt.Time
+	}
+
+	return &EmailOutboxResponse{
+		ID:            e.ID,
+		To:            e.To,
+		Subject:       e.Subject,
+		TemplateName:  e.TemplateName,
+		Status:        e.Status,
+		Attempts:      e.Attempts,
+		NextAttemptAt: e.NextAttemptAt,
+		LastError:     e.LastError,
+		SentAt:        sentAt,
+		CreatedAt:     e.CreatedAt,
+	}
+}
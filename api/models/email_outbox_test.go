@@ -0,0 +1,146 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailOutbox_MarkFailed(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cause := errors.New("smtp unavailable")
+
+	t.Run("ShouldBackOffExponentially", func(t *testing.T) {
+		email, _ := NewEmailOutbox(SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+		email.Claim(now.Add(5 * time.Minute))
+
+		email.MarkFailed(cause, now, 5, time.Minute, time.Hour)
+		assert.Equal(t, now.Add(time.Minute), email.NextAttemptAt)
+
+		email.MarkFailed(cause, now, 5, time.Minute, time.Hour)
+		assert.Equal(t, now.Add(2*time.Minute), email.NextAttemptAt)
+
+		email.MarkFailed(cause, now, 5, time.Minute, time.Hour)
+		assert.Equal(t, now.Add(4*time.Minute), email.NextAttemptAt)
+
+		assert.Equal(t, EmailPending, email.Status)
+		assert.Equal(t, 3, email.Attempts)
+		assert.Equal(t, "smtp unavailable", *email.LastError)
+	})
+
+	t.Run("ShouldCapDelayAtMaximum", func(t *testing.T) {
+		email, _ := NewEmailOutbox(SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+		email.Attempts = 20
+
+		email.MarkFailed(cause, now, 30, time.Minute, time.Hour)
+
+		assert.Equal(t, now.Add(time.Hour), email.NextAttemptAt)
+	})
+
+	t.Run("WhenMaxAttemptsIsReached_ShouldMoveToDeadLetter", func(t *testing.T) {
+		email, _ := NewEmailOutbox(SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+		email.Attempts = 4
+
+		email.MarkFailed(cause, now, 5, time.Minute, time.Hour)
+
+		assert.Equal(t, EmailDead, email.Status)
+		assert.Equal(t, 5, email.Attempts)
+	})
+}
+
+func TestEmailOutbox_Retry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("WhenEmailIsDead_ShouldRequeueIt", func(t *testing.T) {
+		email, _ := NewEmailOutbox(SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+		email.Status = EmailDead
+		email.Attempts = 8
+
+		err := email.Retry(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, EmailPending, email.Status)
+		assert.Equal(t, 0, email.Attempts)
+		assert.Equal(t, now, email.NextAttemptAt)
+	})
+
+	t.Run("WhenEmailWasSent_ShouldReturnErrEmailAlreadySent", func(t *testing.T) {
+		email, _ := NewEmailOutbox(SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+		email.MarkSent(now)
+
+		err := email.Retry(now)
+
+		assert.ErrorIs(t, err, ErrEmailAlreadySent)
+		assert.Equal(t, EmailSent, email.Status)
+	})
+
+	t.Run("WhenDeadEmailCarriedAToken_ShouldReturnErrEmailRedacted", func(t *testing.T) {
+		email, _ := NewEmailOutbox(SendEmailPayload{To: "john@example.com", Params: templates.PasswordResetParams{ResetURL: "http://localhost/reset?token=secret"}})
+		email.MarkDead(errors.New("mailbox unavailable"))
+
+		err := email.Retry(now)
+
+		assert.ErrorIs(t, err, ErrEmailRedacted)
+		assert.Equal(t, EmailDead, email.Status)
+	})
+}
+
+func TestEmailOutbox_RedactParams(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cause := errors.New("smtp unavailable")
+
+	newEmail := func() *EmailOutbox {
+		email, _ := NewEmailOutbox(SendEmailPayload{
+			To: "john@example.com",
+			Params: templates.PasswordResetParams{
+				FullName:  "John",
+				ResetURL:  "http://localhost/reset-password?token=secret",
+				ExpiresIn: "30 minutos",
+			},
+		})
+
+		return email
+	}
+
+	assertRedacted := func(t *testing.T, email *EmailOutbox) {
+		var params templates.PasswordResetParams
+		assert.NoError(t, json.Unmarshal(email.Params, &params))
+		assert.Empty(t, params.ResetURL)
+		assert.Equal(t, "John", params.FullName)
+		assert.NotContains(t, string(email.Params), "secret")
+	}
+
+	t.Run("WhenEmailIsSent_ShouldRemoveTheToken", func(t *testing.T) {
+		email := newEmail()
+		email.MarkSent(now)
+
+		assertRedacted(t, email)
+	})
+
+	t.Run("WhenEmailRunsOutOfAttempts_ShouldRemoveTheToken", func(t *testing.T) {
+		email := newEmail()
+		email.Attempts = 4
+
+		email.MarkFailed(cause, now, 5, time.Minute, time.Hour)
+
+		assertRedacted(t, email)
+	})
+
+	t.Run("WhenEmailIsMarkedDead_ShouldRemoveTheToken", func(t *testing.T) {
+		email := newEmail()
+		email.MarkDead(cause)
+
+		assertRedacted(t, email)
+	})
+
+	t.Run("WhenEmailWillBeRetried_ShouldKeepTheToken", func(t *testing.T) {
+		email := newEmail()
+		email.MarkFailed(cause, now, 5, time.Minute, time.Hour)
+
+		assert.Contains(t, string(email.Params), "token=secret")
+	})
+}
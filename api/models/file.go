@@ -4,9 +4,11 @@ import "errors"
 
 var (
 	ErrImageTooLarge      = errors.New("image size exceeds 5MB")
+	ErrImageTooManyPixels = errors.New("image dimensions exceed the pixel limit")
 	ErrInvalidImageFormat = errors.New("invalid image format")
 	ErrImageCorrupted     = errors.New("image is corrupted or has an invalid format")
 	ErrOpenImage          = errors.New("error to open image")
+	ErrImageAlreadyUsed   = errors.New("image was already used as proof for another order")
 )
 
 const MaxImageSize = 5 * 1024 * 1024
@@ -15,3 +17,18 @@ var AllowedImageTypes = map[string]bool{
 	"image/jpeg": true,
 	"image/png":  true,
 }
+
+var ImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+type ProcessedImage struct {
+	Content     []byte
+	Thumbnail   []byte
+	ContentType string
+	Extension   string
+	Hash        string
+	Width       int
+	Height      int
+}
@@ -3,41 +3,56 @@ package models
 import (
 	"database/sql"
 	"errors"
+	"io"
 	"mime/multipart"
 	"time"
 
+	"github.com/G-Villarinho/fast-feet-api/utils"
 	"github.com/google/uuid"
 )
 
 var (
-	ErrOrderNotFound               = errors.New("order not found in database")
-	ErrCannotTransitionToDelivered = errors.New("cannot transition to 'Delivered' without passing through 'PicknUp'")
-	ErrCannotTransitionToPicknUp   = errors.New("cannot transition to 'PicknUp' unless the order is in 'Waiting' status")
-	ErrNotAssignedToOrder          = errors.New("delivery man is not assigned to this order")
+	ErrOrderNotFound      = errors.New("order not found in database")
+	ErrNotAssignedToOrder = errors.New("delivery man is not assigned to this order")
+	ErrProofNotFound      = errors.New("order has no proof of delivery")
 )
 
 type OrderStatus string
 
 const (
-	Waiting OrderStatus = "WAITING"
-	PicknUp OrderStatus = "PICKN_UP"
-	Done    OrderStatus = "DONE"
+	Waiting        OrderStatus = "WAITING"
+	PicknUp        OrderStatus = "PICKN_UP"
+	Done           OrderStatus = "DONE"
+	DeliveryFailed OrderStatus = "DELIVERY_FAILED"
+	Returning      OrderStatus = "RETURNING"
+	Returned       OrderStatus = "RETURNED"
+	Canceled       OrderStatus = "CANCELED"
 )
 
 type Order struct {
 	BaseModel
-	Title        string       `gorm:"not null"`
-	TrackingCode uuid.UUID    `gorm:"not null"`
-	Status       OrderStatus  `gorm:"not null;default:'WAITING';index"`
-	IsReturned   bool         `gorm:"not null"`
-	PicknUpAt    sql.NullTime `gorm:"default:null"`
-	DeliveryAt   sql.NullTime `gorm:"default:null"`
+	Title          string       `gorm:"not null"`
+	TrackingCode   uuid.UUID    `gorm:"not null"`
+	Status         OrderStatus  `gorm:"not null;default:'WAITING';index"`
+	IsReturned     bool         `gorm:"not null"`
+	PicknUpAt      sql.NullTime `gorm:"default:null"`
+	DeliveryAt     sql.NullTime `gorm:"default:null"`
+	CanceledAt     sql.NullTime `gorm:"default:null"`
+	CancelReason   *string      `gorm:"default:null"`
+	ReturnedAt     sql.NullTime `gorm:"default:null"`
+	ReturnReason   *string      `gorm:"default:null"`
+	ProofImageKey  *string      `gorm:"type:varchar(255);default:null"`
+	ProofThumbKey  *string      `gorm:"type:varchar(255);default:null"`
+	ProofImageHash *string      `gorm:"type:char(64);default:null;index"`
 
 	DeliverymanID *uuid.UUID `gorm:"type:uuid;null;default:null"`
 	Deliveryman   User       `gorm:"foreignKey:DeliverymanID;references:ID"`
 
 	RecipientID uuid.UUID `gorm:"type:uuid;not null"`
 	Recipient   Recipient `gorm:"foreignKey:RecipientID;references:ID"`
+
+	Events           []OrderEvent      `gorm:"foreignKey:OrderID;references:ID"`
+	DeliveryAttempts []DeliveryAttempt `gorm:"foreignKey:OrderID;references:ID"`
 }
 
 type CreateOrderPayload struct {
@@ -45,19 +60,44 @@ type CreateOrderPayload struct {
 	RecipientID uuid.UUID `json:"recipientId" validate:"required"`
 }
 
+type PickUpOrderPayload struct {
+	OrderEventMetadata
+}
+
 type DeliverOrderPayload struct {
+	OrderEventMetadata
 	OrderImage *multipart.FileHeader `json:"title" validate:"required"`
 }
 
+type CancelOrderPayload struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type ReturnOrderPayload struct {
+	OrderEventMetadata
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// InProgressStatuses are the statuses in which an order is still in the hands
+// of its delivery man.
+var InProgressStatuses = []OrderStatus{PicknUp, DeliveryFailed, Returning}
+
+type OrderPagination struct {
+	Pagination
+	Status     *OrderStatus `json:"status"`
+	IsReturned *bool        `json:"isReturned"`
+}
+
 type CreateOrderResponse struct {
 	OrderID uuid.UUID `json:"orderId"`
 }
 
 type OrderResponse struct {
-	ID        uuid.UUID   `json:"id"`
-	Title     string      `json:"title"`
-	Status    OrderStatus `json:"status"`
-	CreatedAt time.Time   `json:"createdAt"`
+	ID         uuid.UUID   `json:"id"`
+	Title      string      `json:"title"`
+	Status     OrderStatus `json:"status"`
+	IsReturned bool        `json:"isReturned"`
+	CreatedAt  time.Time   `json:"createdAt"`
 }
 
 type PickUpOrderResponse struct {
@@ -77,6 +117,37 @@ type OrderDetailsResponse struct {
 	CreatedAt        time.Time   `json:"createdAt"`
 	PicknUpAt        *time.Time  `json:"picknUpAt,omitempty"`
 	DeliveryAt       *time.Time  `json:"deliveryAt,omitempty"`
+	CanceledAt       *time.Time  `json:"canceledAt,omitempty"`
+	CancelReason     *string     `json:"cancelReason,omitempty"`
+	IsReturned       bool        `json:"isReturned"`
+	ReturnedAt       *time.Time  `json:"returnedAt,omitempty"`
+	ReturnReason     *string     `json:"returnReason,omitempty"`
+	DeliveryAttempts int         `json:"deliveryAttempts"`
+	HasProof         bool        `json:"hasProof"`
+}
+
+// OrderProof carries either a signed URL to redirect to or the image content
+// to stream when the storage driver cannot sign URLs.
+type OrderProof struct {
+	URL         string
+	Content     io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+type TrackingResponse struct {
+	TrackingCode    uuid.UUID                `json:"trackingCode"`
+	Status          OrderStatus              `json:"status"`
+	City            string                   `json:"city"`
+	DeliverymanName string                   `json:"deliverymanName,omitempty"`
+	PicknUpAt       *time.Time               `json:"picknUpAt,omitempty"`
+	DeliveryAt      *time.Time               `json:"deliveryAt,omitempty"`
+	Timeline        []*TrackingEventResponse `json:"timeline"`
+}
+
+type TrackingEventResponse struct {
+	Status     OrderStatus `json:"status"`
+	OccurredAt time.Time   `json:"occurredAt"`
 }
 
 func (p *CreateOrderPayload) ToOrder() *Order {
@@ -95,10 +166,11 @@ func (p *CreateOrderPayload) ToOrder() *Order {
 
 func (o *Order) ToOrderResponse() *OrderResponse {
 	return &OrderResponse{
-		ID:        o.ID,
-		Title:     o.Title,
-		Status:    o.Status,
-		CreatedAt: o.CreatedAt,
+		ID:         o.ID,
+		Title:      o.Title,
+		Status:     o.Status,
+		IsReturned: o.IsReturned,
+		CreatedAt:  o.CreatedAt,
 	}
 }
 
@@ -113,6 +185,16 @@ func (o *Order) ToOrderDetailsResponse() *OrderDetailsResponse {
 		deliveryAt = &o.DeliveryAt.Time
 	}
 
+	var canceledAt *time.Time
+	if o.CanceledAt.Valid {
+		canceledAt = &o.CanceledAt.Time
+	}
+
+	var returnedAt *time.Time
+	if o.ReturnedAt.Valid {
+		returnedAt = &o.ReturnedAt.Time
+	}
+
 	return &OrderDetailsResponse{
 		ID:               o.ID,
 		Status:           o.Status,
@@ -122,5 +204,57 @@ func (o *Order) ToOrderDetailsResponse() *OrderDetailsResponse {
 		CreatedAt:        o.CreatedAt,
 		PicknUpAt:        picknUpAt,
 		DeliveryAt:       deliveryAt,
+		CanceledAt:       canceledAt,
+		CancelReason:     o.CancelReason,
+		IsReturned:       o.IsReturned,
+		ReturnedAt:       returnedAt,
+		ReturnReason:     o.ReturnReason,
+		DeliveryAttempts: len(o.DeliveryAttempts),
+		HasProof:         o.ProofImageKey != nil,
+	}
+}
+
+func (o *Order) ToTrackingResponse() *TrackingResponse {
+	var picknUpAt *time.Time
+	if o.PicknUpAt.Valid {
+		picknUpAt = &o.PicknUpAt.Time
+	}
+
+	var deliveryAt *time.Time
+	if o.DeliveryAt.Valid {
+		deliveryAt = &o.DeliveryAt.Time
+	}
+
+	timeline := make([]*TrackingEventResponse, 0, len(o.Events))
+	for _, event := range o.Events {
+		timeline = append(timeline, &TrackingEventResponse{Status: event.ToStatus, OccurredAt: event.OccurredAt})
+	}
+
+	// Orders created before the event history existed only carry their timestamps.
+	if len(timeline) == 0 {
+		timeline = append(timeline, &TrackingEventResponse{Status: Waiting, OccurredAt: o.CreatedAt})
+
+		if picknUpAt != nil {
+			timeline = append(timeline, &TrackingEventResponse{Status: PicknUp, OccurredAt: *picknUpAt})
+		}
+
+		if deliveryAt != nil {
+			timeline = append(timeline, &TrackingEventResponse{Status: Done, OccurredAt: *deliveryAt})
+		}
+	}
+
+	var deliverymanName string
+	if o.DeliverymanID != nil {
+		deliverymanName = utils.MaskName(o.Deliveryman.FullName)
+	}
+
+	return &TrackingResponse{
+		TrackingCode:    o.TrackingCode,
+		Status:          o.Status,
+		City:            o.Recipient.City,
+		DeliverymanName: deliverymanName,
+		PicknUpAt:       picknUpAt,
+		DeliveryAt:      deliveryAt,
+		Timeline:        timeline,
 	}
 }
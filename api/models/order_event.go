@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderEvent struct {
+	BaseModel
+	OrderID    uuid.UUID    `gorm:"type:uuid;not null;index"`
+	FromStatus *OrderStatus `gorm:"default:null"`
+	ToStatus   OrderStatus  `gorm:"not null"`
+	Note       *string      `gorm:"default:null"`
+	Latitude   *float64     `gorm:"default:null"`
+	Longitude  *float64     `gorm:"default:null"`
+	OccurredAt time.Time    `gorm:"not null;index"`
+
+	ActorID *uuid.UUID `gorm:"type:uuid;null;default:null"`
+	Actor   User       `gorm:"foreignKey:ActorID;references:ID"`
+}
+
+type OrderEventMetadata struct {
+	Note      *string  `json:"note" form:"note" validate:"omitempty,max=500"`
+	Latitude  *float64 `json:"latitude" form:"latitude" validate:"omitempty,latitude"`
+	Longitude *float64 `json:"longitude" form:"longitude" validate:"omitempty,longitude"`
+}
+
+type OrderEventResponse struct {
+	ID         uuid.UUID    `json:"id"`
+	FromStatus *OrderStatus `json:"fromStatus,omitempty"`
+	ToStatus   OrderStatus  `json:"toStatus"`
+	ActorID    *uuid.UUID   `json:"actorId,omitempty"`
+	ActorName  string       `json:"actorName,omitempty"`
+	Note       *string      `json:"note,omitempty"`
+	Latitude   *float64     `json:"latitude,omitempty"`
+	Longitude  *float64     `json:"longitude,omitempty"`
+	OccurredAt time.Time    `json:"occurredAt"`
+}
+
+func NewOrderEvent(orderID uuid.UUID, from *OrderStatus, to OrderStatus, actorID *uuid.UUID, metadata OrderEventMetadata) *OrderEvent {
+	now := time.Now().UTC()
+
+	return &OrderEvent{
+		BaseModel: BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: now,
+		},
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Note:       metadata.Note,
+		Latitude:   metadata.Latitude,
+		Longitude:  metadata.Longitude,
+		OccurredAt: now,
+	}
+}
+
+func (e *OrderEvent) ToOrderEventResponse() *OrderEventResponse {
+	return &OrderEventResponse{
+		ID:         e.ID,
+		FromStatus: e.FromStatus,
+		ToStatus:   e.ToStatus,
+		ActorID:    e.ActorID,
+		ActorName:  e.Actor.FullName,
+		Note:       e.Note,
+		Latitude:   e.Latitude,
+		Longitude:  e.Longitude,
+		OccurredAt: e.OccurredAt,
+	}
+}
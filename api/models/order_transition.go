@@ -0,0 +1,123 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrCannotTransitionToPicknUp        = errors.New("cannot transition to 'PicknUp' unless the order is in 'Waiting' status")
+	ErrCannotTransitionToDelivered      = errors.New("cannot transition to 'Delivered' without passing through 'PicknUp'")
+	ErrCannotTransitionToDeliveryFailed = errors.New("cannot transition to 'DeliveryFailed' unless the order is out for delivery")
+	ErrCannotTransitionToReturning      = errors.New("cannot transition to 'Returning' unless the order is out for delivery")
+	ErrCannotTransitionToReturned       = errors.New("cannot transition to 'Returned' unless the order is out for delivery or returning")
+	ErrCannotTransitionToCanceled       = errors.New("cannot transition to 'Canceled' once the order is returning, delivered, returned or canceled")
+	ErrInvalidOrderTransition           = errors.New("order status transition is not allowed")
+)
+
+// OrderTransitionGuard rejects a transition that is allowed by the table but not for this order and actor.
+type OrderTransitionGuard func(order *Order, actor *User) error
+
+type OrderTransition struct {
+	From   OrderStatus
+	To     OrderStatus
+	Action Action
+	Guards []OrderTransitionGuard
+}
+
+var orderTransitions = []OrderTransition{
+	{From: Waiting, To: PicknUp, Action: UpdateStatus},
+	{From: PicknUp, To: Done, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: PicknUp, To: DeliveryFailed, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: DeliveryFailed, To: Done, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: DeliveryFailed, To: DeliveryFailed, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: PicknUp, To: Returning, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: DeliveryFailed, To: Returning, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: PicknUp, To: Returned, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: DeliveryFailed, To: Returned, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: Returning, To: Returned, Action: UpdateStatus, Guards: []OrderTransitionGuard{assignedToActor}},
+	{From: Waiting, To: Canceled, Action: Cancel},
+	{From: PicknUp, To: Canceled, Action: Cancel},
+	{From: DeliveryFailed, To: Canceled, Action: Cancel},
+}
+
+var transitionErrors = map[OrderStatus]error{
+	PicknUp:        ErrCannotTransitionToPicknUp,
+	Done:           ErrCannotTransitionToDelivered,
+	DeliveryFailed: ErrCannotTransitionToDeliveryFailed,
+	Returning:      ErrCannotTransitionToReturning,
+	Returned:       ErrCannotTransitionToReturned,
+	Canceled:       ErrCannotTransitionToCanceled,
+}
+
+// CanTransitionTo reports why actor cannot move the order to the given status, or nil when it can.
+func (o *Order) CanTransitionTo(to OrderStatus, actor *User) error {
+	transition, found := findOrderTransition(o.Status, to)
+	if !found {
+		if err, exists := transitionErrors[to]; exists {
+			return err
+		}
+
+		return ErrInvalidOrderTransition
+	}
+
+	if Cannot(actor.Role, transition.Action, Orders) {
+		return ErrInsufficientPermission
+	}
+
+	for _, guard := range transition.Guards {
+		if err := guard(o, actor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TransitionTo moves the order to the given status and returns the event that records it.
+func (o *Order) TransitionTo(to OrderStatus, actor *User, metadata OrderEventMetadata) (*OrderEvent, error) {
+	if err := o.CanTransitionTo(to, actor); err != nil {
+		return nil, err
+	}
+
+	from := o.Status
+	now := sql.NullTime{Time: time.Now().UTC(), Valid: true}
+
+	switch to {
+	case PicknUp:
+		o.DeliverymanID = &actor.ID
+		o.PicknUpAt = now
+	case Done:
+		o.DeliveryAt = now
+	case Returned:
+		o.IsReturned = true
+		o.ReturnedAt = now
+		o.ReturnReason = metadata.Note
+	case Canceled:
+		o.CanceledAt = now
+		o.CancelReason = metadata.Note
+	}
+
+	o.Status = to
+
+	return NewOrderEvent(o.ID, &from, to, &actor.ID, metadata), nil
+}
+
+func findOrderTransition(from, to OrderStatus) (OrderTransition, bool) {
+	for _, transition := range orderTransitions {
+		if transition.From == from && transition.To == to {
+			return transition, true
+		}
+	}
+
+	return OrderTransition{}, false
+}
+
+func assignedToActor(order *Order, actor *User) error {
+	if order.DeliverymanID == nil || *order.DeliverymanID != actor.ID {
+		return ErrNotAssignedToOrder
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestOrderCanTransitionTo(t *testing.T) {
+	deliveryMan := &User{BaseModel: BaseModel{ID: uuid.New()}, Role: DeliveryMan}
+	otherDeliveryMan := &User{BaseModel: BaseModel{ID: uuid.New()}, Role: DeliveryMan}
+	admin := &User{BaseModel: BaseModel{ID: uuid.New()}, Role: Admin}
+
+	tests := []struct {
+		name  string
+		order *Order
+		to    OrderStatus
+		actor *User
+		want  error
+	}{
+		{
+			name:  "Entregador pode retirar encomenda aguardando",
+			order: &Order{Status: Waiting},
+			to:    PicknUp,
+			actor: deliveryMan,
+			want:  nil,
+		},
+		{
+			name:  "Entregador NÃO pode retirar encomenda já retirada",
+			order: &Order{Status: PicknUp, DeliverymanID: &otherDeliveryMan.ID},
+			to:    PicknUp,
+			actor: deliveryMan,
+			want:  ErrCannotTransitionToPicknUp,
+		},
+		{
+			name:  "Admin NÃO pode retirar encomenda",
+			order: &Order{Status: Waiting},
+			to:    PicknUp,
+			actor: admin,
+			want:  ErrInsufficientPermission,
+		},
+		{
+			name:  "Entregador pode entregar encomenda que retirou",
+			order: &Order{Status: PicknUp, DeliverymanID: &deliveryMan.ID},
+			to:    Done,
+			actor: deliveryMan,
+			want:  nil,
+		},
+		{
+			name:  "Entregador NÃO pode entregar encomenda de outro entregador",
+			order: &Order{Status: PicknUp, DeliverymanID: &otherDeliveryMan.ID},
+			to:    Done,
+			actor: deliveryMan,
+			want:  ErrNotAssignedToOrder,
+		},
+		{
+			name:  "Entregador NÃO pode entregar encomenda sem retirar",
+			order: &Order{Status: Waiting},
+			to:    Done,
+			actor: deliveryMan,
+			want:  ErrCannotTransitionToDelivered,
+		},
+		{
+			name:  "Entregador pode registrar falha na entrega",
+			order: &Order{Status: PicknUp, DeliverymanID: &deliveryMan.ID},
+			to:    DeliveryFailed,
+			actor: deliveryMan,
+			want:  nil,
+		},
+		{
+			name:  "Entregador pode devolver encomenda em devolução",
+			order: &Order{Status: Returning, DeliverymanID: &deliveryMan.ID},
+			to:    Returned,
+			actor: deliveryMan,
+			want:  nil,
+		},
+		{
+			name:  "Entregador NÃO pode devolver encomenda aguardando",
+			order: &Order{Status: Waiting},
+			to:    Returned,
+			actor: deliveryMan,
+			want:  ErrCannotTransitionToReturned,
+		},
+		{
+			name:  "Admin pode cancelar encomenda aguardando",
+			order: &Order{Status: Waiting},
+			to:    Canceled,
+			actor: admin,
+			want:  nil,
+		},
+		{
+			name:  "Admin NÃO pode cancelar encomenda entregue",
+			order: &Order{Status: Done},
+			to:    Canceled,
+			actor: admin,
+			want:  ErrCannotTransitionToCanceled,
+		},
+		{
+			name:  "Admin NÃO pode cancelar encomenda em devolução",
+			order: &Order{Status: Returning},
+			to:    Canceled,
+			actor: admin,
+			want:  ErrCannotTransitionToCanceled,
+		},
+		{
+			name:  "Entregador NÃO pode cancelar encomenda",
+			order: &Order{Status: Waiting},
+			to:    Canceled,
+			actor: deliveryMan,
+			want:  ErrInsufficientPermission,
+		},
+		{
+			name:  "Encomenda cancelada NÃO pode voltar a aguardar",
+			order: &Order{Status: Canceled},
+			to:    Waiting,
+			actor: admin,
+			want:  ErrInvalidOrderTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.order.CanTransitionTo(tt.to, tt.actor)
+			if !errors.Is(got, tt.want) {
+				t.Errorf("CanTransitionTo(%v) from %v = %v, want %v", tt.to, tt.order.Status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderTransitionTo(t *testing.T) {
+	deliveryMan := &User{BaseModel: BaseModel{ID: uuid.New()}, Role: DeliveryMan}
+
+	t.Run("Retirada atribui entregador e registra evento", func(t *testing.T) {
+		order := &Order{BaseModel: BaseModel{ID: uuid.New()}, Status: Waiting}
+
+		event, err := order.TransitionTo(PicknUp, deliveryMan, OrderEventMetadata{})
+		if err != nil {
+			t.Fatalf("TransitionTo(PicknUp) returned error: %v", err)
+		}
+
+		if order.Status != PicknUp || order.DeliverymanID == nil || *order.DeliverymanID != deliveryMan.ID || !order.PicknUpAt.Valid {
+			t.Errorf("order was not picked up by the delivery man: %+v", order)
+		}
+
+		if *event.FromStatus != Waiting || event.ToStatus != PicknUp || *event.ActorID != deliveryMan.ID || event.OrderID != order.ID {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	})
+
+	t.Run("Devolução marca encomenda como devolvida", func(t *testing.T) {
+		order := &Order{Status: PicknUp, DeliverymanID: &deliveryMan.ID}
+
+		if _, err := order.TransitionTo(Returned, deliveryMan, OrderEventMetadata{}); err != nil {
+			t.Fatalf("TransitionTo(Returned) returned error: %v", err)
+		}
+
+		if order.Status != Returned || !order.IsReturned {
+			t.Errorf("order was not returned: %+v", order)
+		}
+	})
+
+	t.Run("Transição inválida não altera a encomenda", func(t *testing.T) {
+		order := &Order{Status: Waiting}
+
+		if _, err := order.TransitionTo(Done, deliveryMan, OrderEventMetadata{}); !errors.Is(err, ErrCannotTransitionToDelivered) {
+			t.Fatalf("TransitionTo(Done) = %v, want %v", err, ErrCannotTransitionToDelivered)
+		}
+
+		if order.Status != Waiting || order.DeliveryAt.Valid {
+			t.Errorf("order changed after invalid transition: %+v", order)
+		}
+	})
+}
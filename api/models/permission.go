@@ -20,6 +20,7 @@ const (
 	Delete            Action = "delete"
 	Manage            Action = "manage"
 	UpdateStatus      Action = "update_status"
+	Cancel            Action = "cancel"
 	TransferOwnership Action = "transfer_ownership"
 )
 
@@ -29,6 +30,8 @@ const (
 	Recipients Resource = "Recipients"
 	Orders     Resource = "Orders"
 	Ownership  Resource = "Ownership"
+	Emails     Resource = "Emails"
+	APIKeys    Resource = "APIKeys"
 )
 
 const (
@@ -108,6 +108,20 @@ func TestCan(t *testing.T) {
 			resource: Orders,
 			want:     true,
 		},
+		{
+			name:     "DeliveryMan NÃO pode cancelar um pedido",
+			role:     DeliveryMan,
+			action:   Cancel,
+			resource: Orders,
+			want:     false,
+		},
+		{
+			name:     "Admin pode cancelar um pedido",
+			role:     Admin,
+			action:   Cancel,
+			resource: Orders,
+			want:     true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session was revoked or has expired")
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or has expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+)
+
+type Session struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// RefreshTokenHash is the hash of the only refresh token that may still
+	// be exchanged for this session.
+	RefreshTokenHash string    `json:"refreshTokenHash"`
+	RefreshedAt      time.Time `json:"refreshedAt"`
+}
+
+// RefreshToken is stored under the hash of an issued refresh token. Rotated
+// tokens are kept with Used set, so presenting one again reveals a stolen
+// token and revokes its session.
+type RefreshToken struct {
+	SessionID uuid.UUID `json:"sessionId"`
+	UserID    uuid.UUID `json:"userId"`
+	Used      bool      `json:"used"`
+}
+
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type SessionResponse struct {
+	ID          uuid.UUID `json:"id"`
+	IP          string    `json:"ip"`
+	UserAgent   string    `json:"userAgent"`
+	CreatedAt   time.Time `json:"createdAt"`
+	RefreshedAt time.Time `json:"refreshedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	Current     bool      `json:"current"`
+}
+
+func NewSession(userID uuid.UUID, ip, userAgent string, now time.Time, ttl time.Duration) *Session {
+	return &Session{
+		ID:          uuid.New(),
+		UserID:      userID,
+		IP:          ip,
+		UserAgent:   userAgent,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+		RefreshedAt: now,
+	}
+}
+
+// Rotate binds a new refresh token to the session and slides its expiration.
+func (s *Session) Rotate(refreshTokenHash string, now time.Time, ttl time.Duration) {
+	s.RefreshTokenHash = refreshTokenHash
+	s.RefreshedAt = now
+	s.ExpiresAt = now.Add(ttl)
+}
+
+func (s *Session) ToSessionResponse(currentSessionID uuid.UUID) *SessionResponse {
+	return &SessionResponse{
+		ID:          s.ID,
+		IP:          s.IP,
+		UserAgent:   s.UserAgent,
+		CreatedAt:   s.CreatedAt,
+		RefreshedAt: s.RefreshedAt,
+		ExpiresAt:   s.ExpiresAt,
+		Current:     s.ID == currentSessionID,
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTwoFactorNotAvailable       = errors.New("two-factor authentication is not available for this role")
+	ErrTwoFactorAlreadyEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled         = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorRequired           = errors.New("two-factor authentication is required for this role")
+	ErrTwoFactorEnrollmentNotFound = errors.New("two-factor enrollment was not started or has expired")
+	ErrTwoFactorChallengeInvalid   = errors.New("two-factor challenge is invalid or has expired")
+	ErrInvalidTwoFactorCode        = errors.New("two-factor code is invalid")
+)
+
+// RecoveryCode replaces a TOTP code once, for when the authenticator is lost.
+// Only its SHA-256 hash is stored.
+type RecoveryCode struct {
+	BaseModel
+	UserID   uuid.UUID    `gorm:"type:uuid;not null;index"`
+	CodeHash string       `gorm:"not null;index"`
+	UsedAt   sql.NullTime `gorm:"default:null"`
+}
+
+// TwoFactorChallenge is stored in the cache under the hash of the challenge
+// token returned by a login that still needs a second factor.
+type TwoFactorChallenge struct {
+	UserID    uuid.UUID `json:"userId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type TwoFactorCodePayload struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+type TwoFactorChallengePayload struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+}
+
+type VerifyTwoFactorPayload struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required,max=32"`
+}
+
+type TwoFactorChallengeResponse struct {
+	ChallengeToken string `json:"challengeToken"`
+	// EnrollmentRequired tells the client to enroll the user, whose role
+	// requires two-factor authentication, before answering the challenge.
+	EnrollmentRequired bool `json:"enrollmentRequired"`
+	// ExpiresIn is the lifetime of ChallengeToken in seconds.
+	ExpiresIn int64 `json:"expiresIn"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth URI to show as a QR code.
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// TwoFactorLoginResponse is the session started by answering a challenge.
+// RecoveryCodes is only set when the answer also completed an enrollment.
+type TwoFactorLoginResponse struct {
+	LoginResponse
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+func NewRecoveryCode(userID uuid.UUID, code string, now time.Time) *RecoveryCode {
+	return &RecoveryCode{
+		BaseModel: BaseModel{
+			ID:        uuid.New(),
+			CreatedAt: now,
+		},
+		UserID:   userID,
+		CodeHash: HashRecoveryCode(code),
+	}
+}
+
+// HashRecoveryCode ignores case and separators, so users can type a code the
+// way it is easiest to read.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	return HashToken(normalized)
+}
+
+func (r *RecoveryCode) Use(now time.Time) {
+	r.UsedAt = sql.NullTime{Time: now, Valid: true}
+}
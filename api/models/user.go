@@ -15,6 +15,16 @@ var (
 	ErrCPFAlreadyExists      = errors.New("user with same CPF already exists")
 	ErrUserNotFoundInContext = errors.New("user not found in the context")
 	ErrUserBlocked           = errors.New("user is blocked")
+	ErrAccountLocked         = errors.New("account is temporarily locked after too many failed logins")
+	ErrTooManyLoginAttempts  = errors.New("too many login attempts from this address")
+	ErrTooManyCodeAttempts   = errors.New("too many wrong verification codes for this user")
+
+	ErrUserAlreadyBlocked = errors.New("user is already blocked")
+	ErrUserNotBlocked     = errors.New("user is not blocked")
+	ErrCannotBlockSelf    = errors.New("user cannot block themselves")
+
+	ErrDeliveryManNotFound        = errors.New("delivery man not found in the database")
+	ErrDeliveryManHasActiveOrders = errors.New("delivery man has orders in progress")
 )
 
 type Status string
@@ -33,6 +43,16 @@ type User struct {
 	Status       Status       `gorm:"not null;default:'ACTIVE';index"`
 	Role         Role         `gorm:"not null;index"`
 	BlockedAt    sql.NullTime `gorm:"default:null"`
+	BlockReason  *string      `gorm:"default:null"`
+	BlockedByID  *uuid.UUID   `gorm:"type:uuid;default:null"`
+
+	MustChangePassword bool         `gorm:"not null;default:false"`
+	SessionsRevokedAt  sql.NullTime `gorm:"default:null"`
+	LockedUntil        sql.NullTime `gorm:"default:null"`
+
+	TwoFactorSecret    *string      `gorm:"default:null"`
+	TwoFactorEnabledAt sql.NullTime `gorm:"default:null"`
+	TwoFactorLastStep  int64        `gorm:"not null;default:0"`
 
 	DeliverymanOrders []Order `gorm:"foreignKey:DeliverymanID;references:ID"`
 }
@@ -43,11 +63,42 @@ type CreateUserPayload struct {
 	CPF      string `json:"cpf" validate:"required,cpf"`
 }
 
+type UpdateUserPayload struct {
+	FullName string `json:"fullName" validate:"required,max=255"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	CPF      string `json:"cpf" validate:"required,cpf"`
+}
+
+type BlockUserPayload struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type DeliveryManPagination struct {
+	Pagination
+	Q      *string `json:"q"`
+	Status *Status `json:"status"`
+}
+
 type UserResponse struct {
 	ID       uuid.UUID `json:"id"`
 	FullName string    `json:"fullName"`
 	Email    string    `json:"email"`
 	Role     Role      `json:"role"`
+
+	MustChangePassword bool `json:"mustChangePassword"`
+	TwoFactorEnabled   bool `json:"twoFactorEnabled"`
+}
+
+type DeliveryManResponse struct {
+	ID        uuid.UUID `json:"id"`
+	FullName  string    `json:"fullName"`
+	Email     string    `json:"email"`
+	CPF       string    `json:"cpf"`
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	BlockedAt   *time.Time `json:"blockedAt,omitempty"`
+	BlockReason *string    `json:"blockReason,omitempty"`
 }
 
 func (cup *CreateUserPayload) ToUser(passwordHash string, role Role) *User {
@@ -61,6 +112,8 @@ func (cup *CreateUserPayload) ToUser(passwordHash string, role Role) *User {
 		Email:        cup.Email,
 		PasswordHash: passwordHash,
 		Role:         role,
+
+		MustChangePassword: true,
 	}
 }
 
@@ -70,5 +123,149 @@ func (u *User) ToUserResponse() *UserResponse {
 		FullName: u.FullName,
 		Email:    u.Email,
 		Role:     u.Role,
+
+		MustChangePassword: u.MustChangePassword,
+		TwoFactorEnabled:   u.TwoFactorEnabled(),
+	}
+}
+
+func (u *User) ToDeliveryManResponse() *DeliveryManResponse {
+	response := &DeliveryManResponse{
+		ID:          u.ID,
+		FullName:    u.FullName,
+		Email:       u.Email,
+		CPF:         u.CPF,
+		Status:      u.Status,
+		CreatedAt:   u.CreatedAt,
+		BlockReason: u.BlockReason,
+	}
+
+	if u.BlockedAt.Valid {
+		response.BlockedAt = &u.BlockedAt.Time
+	}
+
+	return response
+}
+
+// Block prevents the user from logging in and invalidates their sessions.
+func (u *User) Block(blockedBy uuid.UUID, reason string, now time.Time) error {
+	if u.Status == BlockedStatus {
+		return ErrUserAlreadyBlocked
+	}
+
+	if u.ID == blockedBy {
+		return ErrCannotBlockSelf
+	}
+
+	u.Status = BlockedStatus
+	u.BlockedAt = sql.NullTime{Time: now, Valid: true}
+	u.BlockReason = &reason
+	u.BlockedByID = &blockedBy
+
+	return nil
+}
+
+// Unblock lifts both a block made by an administrator and a lockout caused
+// by failed logins.
+func (u *User) Unblock() error {
+	if u.Status != BlockedStatus && !u.LockedUntil.Valid {
+		return ErrUserNotBlocked
+	}
+
+	u.Status = ActiveStatus
+	u.BlockedAt = sql.NullTime{}
+	u.BlockReason = nil
+	u.BlockedByID = nil
+	u.LockedUntil = sql.NullTime{}
+
+	return nil
+}
+
+// Lock keeps the user from logging in until the given time.
+func (u *User) Lock(until time.Time) {
+	u.LockedUntil = sql.NullTime{Time: until, Valid: true}
+}
+
+func (u *User) Unlock() {
+	u.LockedUntil = sql.NullTime{}
+}
+
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil.Valid && now.Before(u.LockedUntil.Time)
+}
+
+// CanUseTwoFactor reports whether the role of the user may enroll in
+// two-factor authentication, which is offered to those who manage the system.
+func (u *User) CanUseTwoFactor() bool {
+	return Can(u.Role, Manage, Users)
+}
+
+func (u *User) TwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt.Valid
+}
+
+// EnableTwoFactor stores a confirmed TOTP secret. step is the time step of
+// the code that confirmed it, which cannot be used again.
+func (u *User) EnableTwoFactor(secret string, step int64, now time.Time) {
+	u.TwoFactorSecret = &secret
+	u.TwoFactorEnabledAt = sql.NullTime{Time: now, Valid: true}
+	u.TwoFactorLastStep = step
+}
+
+func (u *User) DisableTwoFactor() {
+	u.TwoFactorSecret = nil
+	u.TwoFactorEnabledAt = sql.NullTime{}
+	u.TwoFactorLastStep = 0
+}
+
+// UseTwoFactorStep records the time step of an accepted TOTP code. It reports
+// false for a step at or before the last one used, so an observed code cannot
+// be replayed.
+func (u *User) UseTwoFactorStep(step int64) bool {
+	if step <= u.TwoFactorLastStep {
+		return false
+	}
+
+	u.TwoFactorLastStep = step
+
+	return true
+}
+
+func (u *User) ApplyUpdates(p *UpdateUserPayload) {
+	u.FullName = p.FullName
+	u.Email = p.Email
+	u.CPF = utils.RemoveCPFFormat(p.CPF)
+}
+
+// ChangePassword replaces the password hash and lifts the obligation to
+// change the password set when the account was created.
+func (u *User) ChangePassword(passwordHash string) {
+	u.PasswordHash = passwordHash
+	u.MustChangePassword = false
+}
+
+// RevokeSessions invalidates every token issued before now.
+func (u *User) RevokeSessions(now time.Time) {
+	u.SessionsRevokedAt = sql.NullTime{Time: now, Valid: true}
+}
+
+// SessionRevoked reports whether a token issued at issuedAt was revoked. Token
+// timestamps have second precision, so a token issued in the same second as
+// the revocation is still accepted.
+func (u *User) SessionRevoked(issuedAt time.Time) bool {
+	return u.SessionsRevokedAt.Valid && issuedAt.Before(u.SessionsRevokedAt.Time.Truncate(time.Second))
+}
+
+// RoleLabel returns the role as shown to users in emails.
+func (u *User) RoleLabel() string {
+	switch u.Role {
+	case Owner:
+		return "proprietário"
+	case Admin:
+		return "administrador"
+	case DeliveryMan:
+		return "entregador"
+	default:
+		return string(u.Role)
 	}
 }
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_Block(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	adminID := uuid.New()
+
+	t.Run("ShouldRecordReasonAndAuthor", func(t *testing.T) {
+		user := &User{BaseModel: BaseModel{ID: uuid.New()}, Status: ActiveStatus}
+
+		err := user.Block(adminID, "Comportamento inadequado", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, BlockedStatus, user.Status)
+		assert.Equal(t, now, user.BlockedAt.Time)
+		assert.Equal(t, "Comportamento inadequado", *user.BlockReason)
+		assert.Equal(t, adminID, *user.BlockedByID)
+	})
+
+	t.Run("WhenUserIsAlreadyBlocked_ShouldReturnErrUserAlreadyBlocked", func(t *testing.T) {
+		user := &User{BaseModel: BaseModel{ID: uuid.New()}, Status: BlockedStatus}
+
+		err := user.Block(adminID, "Motivo", now)
+
+		assert.ErrorIs(t, err, ErrUserAlreadyBlocked)
+	})
+
+	t.Run("WhenUserBlocksThemselves_ShouldReturnErrCannotBlockSelf", func(t *testing.T) {
+		user := &User{BaseModel: BaseModel{ID: adminID}, Status: ActiveStatus}
+
+		err := user.Block(adminID, "Motivo", now)
+
+		assert.ErrorIs(t, err, ErrCannotBlockSelf)
+		assert.Equal(t, ActiveStatus, user.Status)
+	})
+}
+
+func TestUser_Unblock(t *testing.T) {
+	t.Run("ShouldClearBlockInformation", func(t *testing.T) {
+		user := &User{BaseModel: BaseModel{ID: uuid.New()}, Status: ActiveStatus}
+		assert.NoError(t, user.Block(uuid.New(), "Motivo", time.Now()))
+
+		err := user.Unblock()
+
+		assert.NoError(t, err)
+		assert.Equal(t, ActiveStatus, user.Status)
+		assert.False(t, user.BlockedAt.Valid)
+		assert.Nil(t, user.BlockReason)
+		assert.Nil(t, user.BlockedByID)
+	})
+
+	t.Run("WhenUserIsLockedOut_ShouldLiftLock", func(t *testing.T) {
+		user := &User{Status: ActiveStatus}
+		user.Lock(time.Now().Add(time.Minute))
+
+		err := user.Unblock()
+
+		assert.NoError(t, err)
+		assert.False(t, user.IsLocked(time.Now()))
+	})
+
+	t.Run("WhenUserIsNotBlocked_ShouldReturnErrUserNotBlocked", func(t *testing.T) {
+		user := &User{Status: ActiveStatus}
+
+		err := user.Unblock()
+
+		assert.ErrorIs(t, err, ErrUserNotBlocked)
+	})
+}
+
+func TestUser_IsLocked(t *testing.T) {
+	now := time.Now()
+
+	t.Run("WhenLockIsActive_ShouldReturnTrue", func(t *testing.T) {
+		user := &User{}
+		user.Lock(now.Add(time.Minute))
+
+		assert.True(t, user.IsLocked(now))
+	})
+
+	t.Run("WhenLockExpired_ShouldReturnFalse", func(t *testing.T) {
+		user := &User{}
+		user.Lock(now.Add(-time.Second))
+
+		assert.False(t, user.IsLocked(now))
+	})
+}
+
+func TestUser_UseTwoFactorStep(t *testing.T) {
+	t.Run("WhenStepIsNewer_ShouldRecordIt", func(t *testing.T) {
+		user := &User{}
+		user.EnableTwoFactor("SECRET", 100, time.Now())
+
+		assert.True(t, user.UseTwoFactorStep(101))
+		assert.Equal(t, int64(101), user.TwoFactorLastStep)
+	})
+
+	t.Run("WhenStepWasAlreadyUsed_ShouldRejectIt", func(t *testing.T) {
+		user := &User{}
+		user.EnableTwoFactor("SECRET", 100, time.Now())
+
+		assert.False(t, user.UseTwoFactorStep(100))
+		assert.False(t, user.UseTwoFactorStep(99))
+		assert.Equal(t, int64(100), user.TwoFactorLastStep)
+	})
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=ActivationTokenRepository --filename=activation_token_repository.go --output=../mocks --outpkg=mocks
+type ActivationTokenRepository interface {
+	CreateActivationToken(ctx context.Context, token models.ActivationToken) error
+	GetActivationTokenByHash(ctx context.Context, tokenHash string) (*models.ActivationToken, error)
+	UpdateActivationToken(ctx context.Context, token models.ActivationToken) error
+}
+
+type activationTokenRepository struct {
+	i  *di.Injector
+	DB *gorm.DB
+}
+
+func NewActivationTokenRepository(i *di.Injector) (ActivationTokenRepository, error) {
+	DB, err := di.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke DB: %w", err)
+	}
+
+	return &activationTokenRepository{
+		i:  i,
+		DB: DB,
+	}, nil
+}
+
+func (a *activationTokenRepository) CreateActivationToken(ctx context.Context, token models.ActivationToken) error {
+	if err := conn(ctx, a.DB).
+		Create(&token).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *activationTokenRepository) GetActivationTokenByHash(ctx context.Context, tokenHash string) (*models.ActivationToken, error) {
+	var token models.ActivationToken
+
+	// Lock the row so concurrent requests cannot use the same token twice.
+	if err := conn(ctx, a.DB).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("User").
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (a *activationTokenRepository) UpdateActivationToken(ctx context.Context, token models.ActivationToken) error {
+	if err := conn(ctx, a.DB).
+		Omit("User").
+		Save(&token).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=APIKeyRepository --filename=api_key_repository.go --output=../mocks --outpkg=mocks
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, apiKey models.APIKey) error
+	GetAPIKeyByID(ctx context.Context, ID uuid.UUID) (*models.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	GetAPIKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	UpdateAPIKey(ctx context.Context, apiKey models.APIKey) error
+	UpdateAPIKeyLastUsedAt(ctx context.Context, ID uuid.UUID, lastUsedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	i  *di.Injector
+	DB *gorm.DB
+}
+
+func NewAPIKeyRepository(i *di.Injector) (APIKeyRepository, error) {
+	DB, err := di.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke DB: %w", err)
+	}
+
+	return &apiKeyRepository{
+		i:  i,
+		DB: DB,
+	}, nil
+}
+
+func (a *apiKeyRepository) CreateAPIKey(ctx context.Context, apiKey models.APIKey) error {
+	if err := conn(ctx, a.DB).
+		Create(&apiKey).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *apiKeyRepository) GetAPIKeyByID(ctx context.Context, ID uuid.UUID) (*models.APIKey, error) {
+	var apiKey models.APIKey
+
+	if err := conn(ctx, a.DB).
+		Where("id = ?", ID).
+		First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+func (a *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var apiKey models.APIKey
+
+	if err := conn(ctx, a.DB).
+		Where("key_hash = ?", keyHash).
+		First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// GetAPIKeys returns the personal keys of the user and the keys of every
+// integration, newest first.
+func (a *apiKeyRepository) GetAPIKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	var apiKeys []models.APIKey
+
+	if err := conn(ctx, a.DB).
+		Where("user_id = ? OR integration IS NOT NULL", userID).
+		Order("created_at DESC").
+		Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+func (a *apiKeyRepository) UpdateAPIKey(ctx context.Context, apiKey models.APIKey) error {
+	if err := conn(ctx, a.DB).
+		Save(&apiKey).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAPIKeyLastUsedAt writes only the last used time, so it cannot undo a
+// revocation that happened while the key was authenticating a request.
+func (a *apiKeyRepository) UpdateAPIKeyLastUsedAt(ctx context.Context, ID uuid.UUID, lastUsedAt time.Time) error {
+	if err := conn(ctx, a.DB).
+		Model(&models.APIKey{}).
+		Where("id = ?", ID).
+		UpdateColumn("last_used_at", lastUsedAt).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=AuditLogRepository --filename=audit_log_repository.go --output=../mocks --outpkg=mocks
+type AuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, log models.AuditLog) error
+}
+
+type auditLogRepository struct {
+	i  *di.Injector
+	DB *gorm.DB
+}
+
+func NewAuditLogRepository(i *di.Injector) (AuditLogRepository, error) {
+	DB, err := di.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke DB: %w", err)
+	}
+
+	return &auditLogRepository{
+		i:  i,
+		DB: DB,
+	}, nil
+}
+
+func (a *auditLogRepository) CreateAuditLog(ctx context.Context, log models.AuditLog) error {
+	if err := conn(ctx, a.DB).
+		Create(&log).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
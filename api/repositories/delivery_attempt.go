@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=DeliveryAttemptRepository --filename=delivery_attempt_repository.go --output=../mocks --outpkg=mocks
+type DeliveryAttemptRepository interface {
+	CreateDeliveryAttempt(ctx context.Context, attempt models.DeliveryAttempt) error
+}
+
+type deliveryAttemptRepository struct {
+	i  *di.Injector
+	DB *gorm.DB
+}
+
+func NewDeliveryAttemptRepository(i *di.Injector) (DeliveryAttemptRepository, error) {
+	DB, err := di.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke DB: %w", err)
+	}
+
+	return &deliveryAttemptRepository{
+		i:  i,
+		DB: DB,
+	}, nil
+}
+
+func (d *deliveryAttemptRepository) CreateDeliveryAttempt(ctx context.Context, attempt models.DeliveryAttempt) error {
+	if err := conn(ctx, d.DB).
+		Create(&attempt).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=EmailOutboxRepository --filename=email_outbox_repository.go --output=../mocks --outpkg=mocks
+type EmailOutboxRepository interface {
+	CreateEmailOutbox(ctx context.Context, email models.EmailOutbox) error
+	GetEmailOutboxByID(ctx context.Context, ID uuid.UUID) (*models.EmailOutbox, error)
+	GetDueEmailOutbox(ctx context.Context, now time.Time, limit int) ([]models.EmailOutbox, error)
+	ClaimEmailOutbox(ctx context.Context, IDs []uuid.UUID, leaseUntil time.Time) error
+	UpdateEmailOutbox(ctx context.Context, email models.EmailOutbox) error
+	GetEmailOutboxPagedList(ctx context.Context, pagination *models.EmailOutboxPagination) (*models.PaginatedResponse[models.EmailOutbox], error)
+}
+
+type emailOutboxRepository struct {
+	i  *di.Injector
+	DB *gorm.DB
+}
+
+func NewEmailOutboxRepository(i *di.Injector) (EmailOutboxRepository, error) {
+	DB, err := di.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke DB: %w", err)
+	}
+
+	return &emailOutboxRepository{
+		i:  i,
+		DB: DB,
+	}, nil
+}
+
+func (e *emailOutboxRepository) CreateEmailOutbox(ctx context.Context, email models.EmailOutbox) error {
+	if err := conn(ctx, e.DB).
+		Create(&email).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (e *emailOutboxRepository) GetEmailOutboxByID(ctx context.Context, ID uuid.UUID) (*models.EmailOutbox, error) {
+	var email models.EmailOutbox
+
+	if err := conn(ctx, e.DB).
+		Where("id = ?", ID).
+		First(&email).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &email, nil
+}
+
+// GetDueEmailOutbox locks the pending emails whose next attempt is due, and
+// the claimed ones whose lease ran out because their worker died. Rows already
+// locked by another worker are skipped, so several instances can drain the
+// outbox concurrently.
+func (e *emailOutboxRepository) GetDueEmailOutbox(ctx context.Context, now time.Time, limit int) ([]models.EmailOutbox, error) {
+	var emails []models.EmailOutbox
+
+	if err := conn(ctx, e.DB).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status IN ? AND next_attempt_at <= ?", []models.EmailOutboxStatus{models.EmailPending, models.EmailSending}, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&emails).Error; err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+func (e *emailOutboxRepository) ClaimEmailOutbox(ctx context.Context, IDs []uuid.UUID, leaseUntil time.Time) error {
+	if err := conn(ctx, e.DB).
+		Model(&models.EmailOutbox{}).
+		Where("id IN ?", IDs).
+		Updates(map[string]any{"status": models.EmailSending, "next_attempt_at": leaseUntil}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (e *emailOutboxRepository) UpdateEmailOutbox(ctx context.Context, email models.EmailOutbox) error {
+	if err := conn(ctx, e.DB).
+		Save(&email).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (e *emailOutboxRepository) GetEmailOutboxPagedList(ctx context.Context, pagination *models.EmailOutboxPagination) (*models.PaginatedResponse[models.EmailOutbox], error) {
+	query := conn(ctx, e.DB).
+		Model(&models.EmailOutbox{}).
+		Order("created_at DESC")
+
+	if pagination.Status != nil {
+		query = query.Where("status = ?", *pagination.Status)
+	}
+
+	emails, err := paginate[models.EmailOutbox](query, &pagination.Pagination, &models.EmailOutbox{})
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
@@ -11,13 +11,17 @@ import (
 	"gorm.io/gorm"
 )
 
+//go:generate mockery --name=OrderRepository --filename=order_repository.go --output=../mocks --outpkg=mocks
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, order models.Order) error
 	GetOrderByID(ctx context.Context, ID uuid.UUID) (*models.Order, error)
 	GetOrderByTrackingCode(ctx context.Context, trackingCode uuid.UUID) (*models.Order, error)
+	GetOrderByProofImageHash(ctx context.Context, hash string) (*models.Order, error)
 	DeleteOrder(ctx context.Context, ID uuid.UUID) error
 	UpdateOrder(ctx context.Context, order models.Order) error
-	GetOrdersPagedList(ctx context.Context, deliveryManID *uuid.UUID, pagination *models.Pagination) (*models.PaginatedResponse[models.Order], error)
+	GetOrdersPagedList(ctx context.Context, deliveryManID *uuid.UUID, pagination *models.OrderPagination) (*models.PaginatedResponse[models.Order], error)
+	GetDeliveryManOrdersPagedList(ctx context.Context, deliveryManID uuid.UUID, statuses []models.OrderStatus, pagination *models.Pagination) (*models.PaginatedResponse[models.Order], error)
+	CountDeliveryManOrders(ctx context.Context, deliveryManID uuid.UUID, statuses []models.OrderStatus) (int64, error)
 }
 
 type orderRepository struct {
@@ -38,8 +42,7 @@ func NewOrderRepository(i *di.Injector) (OrderRepository, error) {
 }
 
 func (o *orderRepository) CreateOrder(ctx context.Context, order models.Order) error {
-	if err := o.DB.
-		WithContext(ctx).
+	if err := conn(ctx, o.DB).
 		Create(&order).Error; err != nil {
 		return err
 	}
@@ -50,10 +53,10 @@ func (o *orderRepository) CreateOrder(ctx context.Context, order models.Order) e
 func (o *orderRepository) GetOrderByID(ctx context.Context, ID uuid.UUID) (*models.Order, error) {
 	var order models.Order
 
-	if err := o.DB.
-		WithContext(ctx).
+	if err := conn(ctx, o.DB).
 		Where("id = ?", ID).
 		Preload("Recipient").
+		Preload("DeliveryAttempts").
 		First(&order).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -64,8 +67,7 @@ func (o *orderRepository) GetOrderByID(ctx context.Context, ID uuid.UUID) (*mode
 }
 
 func (o *orderRepository) DeleteOrder(ctx context.Context, ID uuid.UUID) error {
-	if err := o.DB.
-		WithContext(ctx).
+	if err := conn(ctx, o.DB).
 		Where("id = ?", ID).
 		Delete(&models.Order{}).Error; err != nil {
 		return err
@@ -75,8 +77,7 @@ func (o *orderRepository) DeleteOrder(ctx context.Context, ID uuid.UUID) error {
 }
 
 func (o *orderRepository) UpdateOrder(ctx context.Context, order models.Order) error {
-	if err := o.DB.
-		WithContext(ctx).
+	if err := conn(ctx, o.DB).
 		Save(&order).
 		Error; err != nil {
 		return err
@@ -88,26 +89,71 @@ func (o *orderRepository) UpdateOrder(ctx context.Context, order models.Order) e
 func (o *orderRepository) GetOrderByTrackingCode(ctx context.Context, trackingCode uuid.UUID) (*models.Order, error) {
 	var order models.Order
 
-	if err := o.DB.
-		WithContext(ctx).
+	if err := conn(ctx, o.DB).
 		Where("tracking_code = ?", trackingCode).
+		Preload("Recipient").
+		Preload("Deliveryman").
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("occurred_at ASC")
+		}).
 		First(&order).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
+		return nil, err
 	}
 
 	return &order, nil
 }
 
-func (o *orderRepository) GetOrdersPagedList(ctx context.Context, deliveryManID *uuid.UUID, pagination *models.Pagination) (*models.PaginatedResponse[models.Order], error) {
-	query := o.DB.WithContext(ctx).
+func (o *orderRepository) GetOrderByProofImageHash(ctx context.Context, hash string) (*models.Order, error) {
+	var order models.Order
+
+	if err := conn(ctx, o.DB).
+		Where("proof_image_hash = ?", hash).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (o *orderRepository) GetOrdersPagedList(ctx context.Context, deliveryManID *uuid.UUID, pagination *models.OrderPagination) (*models.PaginatedResponse[models.Order], error) {
+	query := conn(ctx, o.DB).
 		Model(&models.Order{})
 
 	if deliveryManID != nil {
 		query = query.Where("status = ? OR deliveryman_id = ?", models.Waiting, deliveryManID)
 	}
 
+	if pagination.Status != nil {
+		query = query.Where("status = ?", *pagination.Status)
+	}
+
+	if pagination.IsReturned != nil {
+		query = query.Where("is_returned = ?", *pagination.IsReturned)
+	}
+
+	orders, err := paginate[models.Order](query, &pagination.Pagination, &models.Order{})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (o *orderRepository) GetDeliveryManOrdersPagedList(ctx context.Context, deliveryManID uuid.UUID, statuses []models.OrderStatus, pagination *models.Pagination) (*models.PaginatedResponse[models.Order], error) {
+	query := conn(ctx, o.DB).
+		Model(&models.Order{}).
+		Where("deliveryman_id = ? AND status IN ?", deliveryManID, statuses).
+		Order("created_at")
+
 	orders, err := paginate[models.Order](query, pagination, &models.Order{})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -118,3 +164,16 @@ func (o *orderRepository) GetOrdersPagedList(ctx context.Context, deliveryManID
 
 	return orders, nil
 }
+
+func (o *orderRepository) CountDeliveryManOrders(ctx context.Context, deliveryManID uuid.UUID, statuses []models.OrderStatus) (int64, error) {
+	var total int64
+
+	if err := conn(ctx, o.DB).
+		Model(&models.Order{}).
+		Where("deliveryman_id = ? AND status IN ?", deliveryManID, statuses).
+		Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
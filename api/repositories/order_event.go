@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=OrderEventRepository --filename=order_event_repository.go --output=../mocks --outpkg=mocks
+type OrderEventRepository interface {
+	CreateOrderEvent(ctx context.Context, event models.OrderEvent) error
+	GetOrderEventsByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.OrderEvent, error)
+}
+
+type orderEventRepository struct {
+	i  *di.Injector
+	DB *gorm.DB
+}
+
+func NewOrderEventRepository(i *di.Injector) (OrderEventRepository, error) {
+	DB, err := di.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke DB: %w", err)
+	}
+
+	return &orderEventRepository{
+		i:  i,
+		DB: DB,
+	}, nil
+}
+
+func (o *orderEventRepository) CreateOrderEvent(ctx context.Context, event models.OrderEvent) error {
+	if err := conn(ctx, o.DB).
+		Create(&event).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *orderEventRepository) GetOrderEventsByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.OrderEvent, error) {
+	var events []models.OrderEvent
+
+	if err := conn(ctx, o.DB).
+		Where("order_id = ?", orderID).
+		Preload("Actor").
+		Order("occurred_at ASC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -1,10 +1,16 @@
 package repositories
 
 import (
+	"strings"
+
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"gorm.io/gorm"
 )
 
+// likeEscaper escapes the LIKE wildcards in a search term, so it matches
+// literally when used with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func paginate[T any](db *gorm.DB, pagination *models.Pagination, model any) (*models.PaginatedResponse[T], error) {
 	var result models.PaginatedResponse[T]
 	var total int64
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=RecoveryCodeRepository --filename=recovery_code_repository.go --output=../mocks --outpkg=mocks
+type RecoveryCodeRepository interface {
+	CreateRecoveryCodes(ctx context.Context, codes []models.RecoveryCode) error
+	GetUnusedRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (*models.RecoveryCode, error)
+	UpdateRecoveryCode(ctx context.Context, code models.RecoveryCode) error
+	DeleteRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type recoveryCodeRepository struct {
+	i  *di.Injector
+	DB *gorm.DB
+}
+
+func NewRecoveryCodeRepository(i *di.Injector) (RecoveryCodeRepository, error) {
+	DB, err := di.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke DB: %w", err)
+	}
+
+	return &recoveryCodeRepository{
+		i:  i,
+		DB: DB,
+	}, nil
+}
+
+func (r *recoveryCodeRepository) CreateRecoveryCodes(ctx context.Context, codes []models.RecoveryCode) error {
+	if err := conn(ctx, r.DB).
+		Create(&codes).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *recoveryCodeRepository) GetUnusedRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (*models.RecoveryCode, error) {
+	var code models.RecoveryCode
+
+	// Lock the row so concurrent requests cannot use the same code twice.
+	if err := conn(ctx, r.DB).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		First(&code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+func (r *recoveryCodeRepository) UpdateRecoveryCode(ctx context.Context, code models.RecoveryCode) error {
+	if err := conn(ctx, r.DB).
+		Save(&code).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *recoveryCodeRepository) DeleteRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := conn(ctx, r.DB).
+		Where("user_id = ?", userID).
+		Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
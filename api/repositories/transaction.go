@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+//go:generate mockery --name=TransactionManager --filename=transaction_manager.go --output=../mocks --outpkg=mocks
+type TransactionManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactionManager struct {
+	i  *di.Injector
+	DB *gorm.DB
+}
+
+func NewTransactionManager(i *di.Injector) (TransactionManager, error) {
+	DB, err := di.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke DB: %w", err)
+	}
+
+	return &transactionManager{
+		i:  i,
+		DB: DB,
+	}, nil
+}
+
+func (t *transactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return t.DB.
+		WithContext(ctx).
+		Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, transactionKey{}, tx))
+		})
+}
+
+// conn returns the transaction bound to ctx by WithTransaction, falling back to DB.
+func conn(ctx context.Context, DB *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return DB.WithContext(ctx)
+}
@@ -9,15 +9,20 @@ import (
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //go:generate mockery --name=UserRepository --filename=user_repository.go --output=../mocks --outpkg=mocks
 type UserRepository interface {
 	CreateUser(ctx context.Context, user models.User) error
+	UpdateUser(ctx context.Context, user models.User) error
+	UseTwoFactorStep(ctx context.Context, ID uuid.UUID, step int64) (bool, error)
 	GetUserByID(ctx context.Context, ID uuid.UUID) (*models.User, error)
+	GetUserByIDForUpdate(ctx context.Context, ID uuid.UUID) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserByCPF(ctx context.Context, CPF string) (*models.User, error)
 	DeleteUser(ctx context.Context, ID uuid.UUID) error
+	GetDeliveryMenPagedList(ctx context.Context, pagination *models.DeliveryManPagination) (*models.PaginatedResponse[models.User], error)
 }
 
 type userRepository struct {
@@ -38,8 +43,7 @@ func NewUserRepository(i *di.Injector) (UserRepository, error) {
 }
 
 func (u *userRepository) CreateUser(ctx context.Context, user models.User) error {
-	if err := u.DB.
-		WithContext(ctx).
+	if err := conn(ctx, u.DB).
 		Create(&user).Error; err != nil {
 		return err
 	}
@@ -47,6 +51,31 @@ func (u *userRepository) CreateUser(ctx context.Context, user models.User) error
 	return nil
 }
 
+func (u *userRepository) UpdateUser(ctx context.Context, user models.User) error {
+	if err := conn(ctx, u.DB).
+		Omit("DeliverymanOrders").
+		Save(&user).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UseTwoFactorStep records the TOTP step only if it is after the last one
+// used, in a single statement, so concurrent requests cannot both accept the
+// same code. It reports whether the step was recorded.
+func (u *userRepository) UseTwoFactorStep(ctx context.Context, ID uuid.UUID, step int64) (bool, error) {
+	result := conn(ctx, u.DB).
+		Model(&models.User{}).
+		Where("id = ? AND two_factor_last_step < ?", ID, step).
+		UpdateColumn("two_factor_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
 func (u *userRepository) GetUserByID(ctx context.Context, ID uuid.UUID) (*models.User, error) {
 	var user models.User
 
@@ -57,6 +86,27 @@ func (u *userRepository) GetUserByID(ctx context.Context, ID uuid.UUID) (*models
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (u *userRepository) GetUserByIDForUpdate(ctx context.Context, ID uuid.UUID) (*models.User, error) {
+	var user models.User
+
+	// Lock the row so assigning orders to a delivery man and deactivating them
+	// cannot interleave.
+	if err := conn(ctx, u.DB).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", ID).
+		First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
 	}
 
 	return &user, nil
@@ -72,6 +122,8 @@ func (u *userRepository) GetUserByCPF(ctx context.Context, CPF string) (*models.
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
+
+		return nil, err
 	}
 
 	return &user, nil
@@ -87,14 +139,15 @@ func (u *userRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
+
+		return nil, err
 	}
 
 	return &user, nil
 }
 
 func (u *userRepository) DeleteUser(ctx context.Context, ID uuid.UUID) error {
-	if err := u.DB.
-		WithContext(ctx).
+	if err := conn(ctx, u.DB).
 		Where("id = ?", ID).
 		Delete(&models.User{}).Error; err != nil {
 		return err
@@ -102,3 +155,28 @@ func (u *userRepository) DeleteUser(ctx context.Context, ID uuid.UUID) error {
 
 	return nil
 }
+
+func (u *userRepository) GetDeliveryMenPagedList(ctx context.Context, pagination *models.DeliveryManPagination) (*models.PaginatedResponse[models.User], error) {
+	query := conn(ctx, u.DB).
+		Model(&models.User{}).
+		Where("role = ?", models.DeliveryMan)
+
+	if pagination.Q != nil {
+		q := fmt.Sprintf("%%%s%%", likeEscaper.Replace(*pagination.Q))
+		query = query.Where(`full_name ILIKE ? ESCAPE '\' OR email ILIKE ? ESCAPE '\' OR cpf LIKE ? ESCAPE '\'`, q, q, q)
+	}
+
+	if pagination.Status != nil {
+		query = query.Where("status = ?", *pagination.Status)
+	}
+
+	users, err := paginate[models.User](query.Order("full_name"), &pagination.Pagination, &models.User{})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -8,8 +8,21 @@ import (
 
 type contextKey string
 
+// AuthMethod tells how the caller presented its credentials.
+type AuthMethod string
+
+const (
+	CookieAuth AuthMethod = "cookie"
+	BearerAuth AuthMethod = "bearer"
+	APIKeyAuth AuthMethod = "api_key"
+)
+
 const userIDKey contextKey = "userID"
 const tokenKey contextKey = "userToken"
+const sessionIDKey contextKey = "sessionID"
+const clientIPKey contextKey = "clientIP"
+const userAgentKey contextKey = "userAgent"
+const authMethodKey contextKey = "authMethod"
 
 func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
 	return context.WithValue(ctx, userIDKey, userID)
@@ -19,6 +32,19 @@ func WithToken(ctx context.Context, token string) context.Context {
 	return context.WithValue(ctx, tokenKey, token)
 }
 
+func WithSessionID(ctx context.Context, sessionID uuid.UUID) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+func WithClientInfo(ctx context.Context, ip string, userAgent string) context.Context {
+	ctx = context.WithValue(ctx, clientIPKey, ip)
+	return context.WithValue(ctx, userAgentKey, userAgent)
+}
+
+func WithAuthMethod(ctx context.Context, method AuthMethod) context.Context {
+	return context.WithValue(ctx, authMethodKey, method)
+}
+
 func UserID(ctx context.Context) (uuid.UUID, bool) {
 	UserID, ok := ctx.Value(userIDKey).(uuid.UUID)
 	return UserID, ok
@@ -28,3 +54,19 @@ func Token(ctx context.Context) (string, bool) {
 	token, ok := ctx.Value(tokenKey).(string)
 	return token, ok
 }
+
+func SessionID(ctx context.Context) (uuid.UUID, bool) {
+	sessionID, ok := ctx.Value(sessionIDKey).(uuid.UUID)
+	return sessionID, ok
+}
+
+func ClientInfo(ctx context.Context) (ip string, userAgent string) {
+	ip, _ = ctx.Value(clientIPKey).(string)
+	userAgent, _ = ctx.Value(userAgentKey).(string)
+	return ip, userAgent
+}
+
+func AuthenticatedWith(ctx context.Context) (AuthMethod, bool) {
+	method, ok := ctx.Value(authMethodKey).(AuthMethod)
+	return method, ok
+}
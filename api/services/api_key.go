@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/repositories"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/google/uuid"
+)
+
+// apiKeyLastUsedInterval keeps busy integrations from writing the last used
+// time of their key on every request.
+const apiKeyLastUsedInterval = time.Minute
+
+//go:generate mockery --name=APIKeyService --filename=api_key_service.go --output=../mocks --outpkg=mocks
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, payload models.CreateAPIKeyPayload) (*models.CreateAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context) ([]models.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, apiKeyID uuid.UUID) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+type apiKeyService struct {
+	i   *di.Injector
+	ss  SecureService
+	akr repositories.APIKeyRepository
+	alr repositories.AuditLogRepository
+	ur  repositories.UserRepository
+	tm  repositories.TransactionManager
+	now func() time.Time
+}
+
+func NewAPIKeyService(i *di.Injector) (APIKeyService, error) {
+	ss, err := di.Invoke[SecureService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke secure service: %w", err)
+	}
+
+	akr, err := di.Invoke[repositories.APIKeyRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke api key repository: %w", err)
+	}
+
+	alr, err := di.Invoke[repositories.AuditLogRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke audit log repository: %w", err)
+	}
+
+	ur, err := di.Invoke[repositories.UserRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke user repository: %w", err)
+	}
+
+	tm, err := di.Invoke[repositories.TransactionManager](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke transaction manager: %w", err)
+	}
+
+	return &apiKeyService{
+		i:   i,
+		ss:  ss,
+		akr: akr,
+		alr: alr,
+		ur:  ur,
+		tm:  tm,
+		now: time.Now,
+	}, nil
+}
+
+// CreateAPIKey returns the new key in clear, which is the only time it can be
+// read. A key can only be scoped to what the role of its creator allows.
+func (a *apiKeyService) CreateAPIKey(ctx context.Context, payload models.CreateAPIKeyPayload) (*models.CreateAPIKeyResponse, error) {
+	user, err := a.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if models.Cannot(user.Role, models.Create, models.APIKeys) {
+		return nil, models.ErrInsufficientPermission
+	}
+
+	for _, scope := range payload.Scopes {
+		if !scope.Supported() {
+			return nil, models.ErrAPIKeyScopeUnsupported
+		}
+
+		if models.Cannot(user.Role, scope.Action, scope.Resource) {
+			return nil, models.ErrInsufficientPermission
+		}
+	}
+
+	now := a.now().UTC()
+
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(now) {
+		return nil, models.ErrAPIKeyExpirationInvalid
+	}
+
+	secret, err := a.ss.CreateToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create api key: %w", err)
+	}
+
+	key := models.APIKeyPrefix + secret
+	apiKey := payload.ToAPIKey(user.ID, key, now)
+
+	if err := a.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := a.akr.CreateAPIKey(ctx, *apiKey); err != nil {
+			return fmt.Errorf("create api key: %w", err)
+		}
+
+		return a.audit(ctx, models.APIKeyCreatedAction, user.ID, apiKey.ID)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &models.CreateAPIKeyResponse{
+		APIKeyResponse: *apiKey.ToAPIKeyResponse(),
+		Key:            key,
+	}, nil
+}
+
+// GetAPIKeys lists the personal keys of the authenticated user and the keys
+// of every integration, including revoked and expired ones.
+func (a *apiKeyService) GetAPIKeys(ctx context.Context) ([]models.APIKeyResponse, error) {
+	user, err := a.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if models.Cannot(user.Role, models.Read, models.APIKeys) {
+		return nil, models.ErrInsufficientPermission
+	}
+
+	apiKeys, err := a.akr.GetAPIKeys(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get api keys of user %q: %w", user.ID, err)
+	}
+
+	response := make([]models.APIKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		response = append(response, *apiKey.ToAPIKeyResponse())
+	}
+
+	return response, nil
+}
+
+// RevokeAPIKey stops a key from authenticating requests. Personal keys can
+// only be revoked by their owner; revoking a key twice does nothing.
+func (a *apiKeyService) RevokeAPIKey(ctx context.Context, apiKeyID uuid.UUID) error {
+	user, err := a.getAuthenticatedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if models.Cannot(user.Role, models.Delete, models.APIKeys) {
+		return models.ErrInsufficientPermission
+	}
+
+	apiKey, err := a.akr.GetAPIKeyByID(ctx, apiKeyID)
+	if err != nil {
+		return fmt.Errorf("get api key by id %q: %w", apiKeyID, err)
+	}
+
+	if apiKey == nil || (apiKey.Integration == nil && apiKey.UserID != user.ID) {
+		return models.ErrAPIKeyNotFound
+	}
+
+	if apiKey.RevokedAt.Valid {
+		return nil
+	}
+
+	apiKey.Revoke(a.now().UTC())
+
+	return a.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := a.akr.UpdateAPIKey(ctx, *apiKey); err != nil {
+			return fmt.Errorf("update api key %q: %w", apiKey.ID, err)
+		}
+
+		return a.audit(ctx, models.APIKeyRevokedAction, user.ID, apiKey.ID)
+	})
+}
+
+// AuthenticateAPIKey returns the key if it exists and is still active, and
+// records when it was used.
+func (a *apiKeyService) AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKey, error) {
+	if !strings.HasPrefix(key, models.APIKeyPrefix) {
+		return nil, models.ErrAPIKeyInvalid
+	}
+
+	apiKey, err := a.akr.GetAPIKeyByHash(ctx, models.HashToken(key))
+	if err != nil {
+		return nil, fmt.Errorf("get api key by hash: %w", err)
+	}
+
+	now := a.now().UTC()
+
+	if apiKey == nil || !apiKey.Active(now) {
+		return nil, models.ErrAPIKeyInvalid
+	}
+
+	if !apiKey.LastUsedAt.Valid || now.Sub(apiKey.LastUsedAt.Time) >= apiKeyLastUsedInterval {
+		if err := a.akr.UpdateAPIKeyLastUsedAt(ctx, apiKey.ID, now); err != nil {
+			return nil, fmt.Errorf("update last used time of api key %q: %w", apiKey.ID, err)
+		}
+
+		apiKey.LastUsedAt = sql.NullTime{Time: now, Valid: true}
+	}
+
+	return apiKey, nil
+}
+
+func (a *apiKeyService) audit(ctx context.Context, action models.AuditAction, userID uuid.UUID, apiKeyID uuid.UUID) error {
+	ip, userAgent := request.ClientInfo(ctx)
+	details := fmt.Sprintf("api key %s", apiKeyID)
+
+	if err := a.alr.CreateAuditLog(ctx, *models.NewAuditLog(action, &userID, ip, userAgent, &details)); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (a *apiKeyService) getAuthenticatedUser(ctx context.Context) (*models.User, error) {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return nil, models.ErrUserNotFoundInContext
+	}
+
+	user, err := a.ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", userID, err)
+	}
+
+	if user == nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	return user, nil
+}
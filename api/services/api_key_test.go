@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	now := time.Date(2025, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	t.Run("WhenPayloadIsValid_ShouldStoreHashAndReturnKeyOnce", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		auditLogRepoMock := new(mocks.AuditLogRepository)
+		secureServiceMock := new(mocks.SecureService)
+
+		service := &apiKeyService{
+			ss:  secureServiceMock,
+			akr: apiKeyRepoMock,
+			alr: auditLogRepoMock,
+			ur:  userRepoMock,
+			tm:  newTransactionManager(),
+			now: func() time.Time { return now },
+		}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.Admin}
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		secureServiceMock.On("CreateToken", mock.Anything).Return("random-secret", nil)
+
+		var stored models.APIKey
+		apiKeyRepoMock.On("CreateAPIKey", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				stored = args.Get(1).(models.APIKey)
+			}).
+			Return(nil)
+		auditLogRepoMock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l models.AuditLog) bool {
+			return l.Action == models.APIKeyCreatedAction && *l.UserID == user.ID
+		})).Return(nil)
+
+		integration := "erp"
+		payload := models.CreateAPIKeyPayload{
+			Name:        "ERP orders",
+			Integration: &integration,
+			Scopes:      []models.APIKeyScope{{Action: models.Create, Resource: models.Orders}},
+		}
+
+		ctx := request.WithUserID(context.Background(), user.ID)
+		response, err := service.CreateAPIKey(ctx, payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ffk_random-secret", response.Key)
+		assert.Equal(t, models.HashToken(response.Key), stored.KeyHash)
+		assert.Equal(t, user.ID, stored.UserID)
+		assert.Equal(t, "ffk_random-s", response.Prefix)
+		assert.Equal(t, &integration, response.Integration)
+		apiKeyRepoMock.AssertExpectations(t)
+		auditLogRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenNoRouteAcceptsTheScope_ShouldReturnErrAPIKeyScopeUnsupported", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		service := &apiKeyService{ur: userRepoMock, akr: apiKeyRepoMock, now: func() time.Time { return now }}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.Owner}
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+		payload := models.CreateAPIKeyPayload{
+			Name:   "tracking",
+			Scopes: []models.APIKeyScope{{Action: models.Read, Resource: models.Orders}, {Action: models.Manage, Resource: models.Users}},
+		}
+
+		ctx := request.WithUserID(context.Background(), user.ID)
+		response, err := service.CreateAPIKey(ctx, payload)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrAPIKeyScopeUnsupported)
+		apiKeyRepoMock.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenUserCannotCreateAPIKeys_ShouldReturnErrInsufficientPermission", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		service := &apiKeyService{ur: userRepoMock, akr: apiKeyRepoMock, now: func() time.Time { return now }}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.DeliveryMan}
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+		payload := models.CreateAPIKeyPayload{
+			Name:   "tracking",
+			Scopes: []models.APIKeyScope{{Action: models.Read, Resource: models.Orders}},
+		}
+
+		ctx := request.WithUserID(context.Background(), user.ID)
+		response, err := service.CreateAPIKey(ctx, payload)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrInsufficientPermission)
+		apiKeyRepoMock.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenExpirationIsInThePast_ShouldReturnErrAPIKeyExpirationInvalid", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := &apiKeyService{ur: userRepoMock, now: func() time.Time { return now }}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.Owner}
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+		expiresAt := now.Add(-time.Hour)
+		payload := models.CreateAPIKeyPayload{
+			Name:      "erp",
+			Scopes:    []models.APIKeyScope{{Action: models.Read, Resource: models.Orders}},
+			ExpiresAt: &expiresAt,
+		}
+
+		ctx := request.WithUserID(context.Background(), user.ID)
+		_, err := service.CreateAPIKey(ctx, payload)
+
+		assert.ErrorIs(t, err, models.ErrAPIKeyExpirationInvalid)
+	})
+}
+
+func TestAPIKeyService_RevokeAPIKey(t *testing.T) {
+	now := time.Date(2025, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	t.Run("WhenPersonalKeyBelongsToAnotherUser_ShouldReturnErrAPIKeyNotFound", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		service := &apiKeyService{ur: userRepoMock, akr: apiKeyRepoMock, now: func() time.Time { return now }}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.Admin}
+		apiKey := &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: uuid.New()}
+
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		apiKeyRepoMock.On("GetAPIKeyByID", mock.Anything, apiKey.ID).Return(apiKey, nil)
+
+		ctx := request.WithUserID(context.Background(), user.ID)
+		err := service.RevokeAPIKey(ctx, apiKey.ID)
+
+		assert.ErrorIs(t, err, models.ErrAPIKeyNotFound)
+		assert.False(t, apiKey.RevokedAt.Valid)
+		apiKeyRepoMock.AssertNotCalled(t, "UpdateAPIKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenKeyBelongsToAnIntegration_ShouldRevokeAndAudit", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		auditLogRepoMock := new(mocks.AuditLogRepository)
+
+		service := &apiKeyService{
+			ur:  userRepoMock,
+			akr: apiKeyRepoMock,
+			alr: auditLogRepoMock,
+			tm:  newTransactionManager(),
+			now: func() time.Time { return now },
+		}
+
+		integration := "erp"
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.Admin}
+		apiKey := &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: uuid.New(), Integration: &integration}
+
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		apiKeyRepoMock.On("GetAPIKeyByID", mock.Anything, apiKey.ID).Return(apiKey, nil)
+		apiKeyRepoMock.On("UpdateAPIKey", mock.Anything, mock.MatchedBy(func(k models.APIKey) bool {
+			return k.ID == apiKey.ID && k.RevokedAt.Valid && k.RevokedAt.Time.Equal(now)
+		})).Return(nil)
+		auditLogRepoMock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l models.AuditLog) bool {
+			return l.Action == models.APIKeyRevokedAction && *l.UserID == user.ID
+		})).Return(nil)
+
+		ctx := request.WithUserID(context.Background(), user.ID)
+		err := service.RevokeAPIKey(ctx, apiKey.ID)
+
+		assert.NoError(t, err)
+		apiKeyRepoMock.AssertExpectations(t)
+		auditLogRepoMock.AssertExpectations(t)
+	})
+}
+
+func TestAPIKeyService_AuthenticateAPIKey(t *testing.T) {
+	now := time.Date(2025, 3, 15, 14, 30, 0, 0, time.UTC)
+	key := models.APIKeyPrefix + "random-secret"
+
+	t.Run("WhenKeyIsActive_ShouldRecordLastUse", func(t *testing.T) {
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		service := &apiKeyService{akr: apiKeyRepoMock, now: func() time.Time { return now }}
+
+		apiKey := &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}}
+		apiKeyRepoMock.On("GetAPIKeyByHash", mock.Anything, models.HashToken(key)).Return(apiKey, nil)
+		apiKeyRepoMock.On("UpdateAPIKeyLastUsedAt", mock.Anything, apiKey.ID, now).Return(nil)
+
+		response, err := service.AuthenticateAPIKey(context.Background(), key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, now, response.LastUsedAt.Time)
+		apiKeyRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenKeyWasUsedRecently_ShouldNotWriteLastUseAgain", func(t *testing.T) {
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		service := &apiKeyService{akr: apiKeyRepoMock, now: func() time.Time { return now }}
+
+		apiKey := &models.APIKey{
+			BaseModel:  models.BaseModel{ID: uuid.New()},
+			LastUsedAt: sql.NullTime{Time: now.Add(-10 * time.Second), Valid: true},
+		}
+		apiKeyRepoMock.On("GetAPIKeyByHash", mock.Anything, models.HashToken(key)).Return(apiKey, nil)
+
+		_, err := service.AuthenticateAPIKey(context.Background(), key)
+
+		assert.NoError(t, err)
+		apiKeyRepoMock.AssertNotCalled(t, "UpdateAPIKeyLastUsedAt", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenKeyWasRevoked_ShouldReturnErrAPIKeyInvalid", func(t *testing.T) {
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		service := &apiKeyService{akr: apiKeyRepoMock, now: func() time.Time { return now }}
+
+		apiKey := &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}}
+		apiKey.Revoke(now.Add(-time.Minute))
+		apiKeyRepoMock.On("GetAPIKeyByHash", mock.Anything, models.HashToken(key)).Return(apiKey, nil)
+
+		response, err := service.AuthenticateAPIKey(context.Background(), key)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrAPIKeyInvalid)
+		apiKeyRepoMock.AssertNotCalled(t, "UpdateAPIKeyLastUsedAt", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenKeyHasNoPrefix_ShouldNotQueryRepository", func(t *testing.T) {
+		apiKeyRepoMock := new(mocks.APIKeyRepository)
+		service := &apiKeyService{akr: apiKeyRepoMock, now: func() time.Time { return now }}
+
+		_, err := service.AuthenticateAPIKey(context.Background(), "random-secret")
+
+		assert.ErrorIs(t, err, models.ErrAPIKeyInvalid)
+		apiKeyRepoMock.AssertNotCalled(t, "GetAPIKeyByHash", mock.Anything, mock.Anything)
+	})
+}
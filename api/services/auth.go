@@ -2,24 +2,49 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/G-Villarinho/fast-feet-api/repositories"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/services/email"
+	"github.com/G-Villarinho/fast-feet-api/storage"
 	"github.com/G-Villarinho/fast-feet-api/utils"
+	"github.com/google/uuid"
 )
 
 //go:generate mockery --name=AuthService --filename=auth_service.go --output=../mocks --outpkg=mocks
 type AuthService interface {
-	Login(ctx context.Context, payload models.LoginPayload) (*models.LoginResponse, error)
+	Login(ctx context.Context, payload models.LoginPayload) (*models.LoginResult, error)
+	StartTwoFactorEnrollment(ctx context.Context, payload models.TwoFactorChallengePayload) (*models.TwoFactorEnrollmentResponse, error)
+	VerifyTwoFactor(ctx context.Context, payload models.VerifyTwoFactorPayload) (*models.TwoFactorLoginResponse, error)
+	Logout(ctx context.Context, accessToken string, refreshToken string) error
+	Refresh(ctx context.Context, payload models.RefreshTokenPayload) (*models.LoginResponse, error)
+	ActivateAccount(ctx context.Context, payload models.ActivateAccountPayload) error
+	ChangePassword(ctx context.Context, payload models.ChangePasswordPayload) (*models.LoginResponse, error)
+	ForgotPassword(ctx context.Context, payload models.ForgotPasswordPayload) error
+	ResetPassword(ctx context.Context, payload models.ResetPasswordPayload) error
 }
 
 type authService struct {
-	i  *di.Injector
-	ss SecureService
-	ts TokenService
-	ur repositories.UserRepository
+	i   *di.Injector
+	ss  SecureService
+	ses SessionService
+	tfs TwoFactorService
+	ur  repositories.UserRepository
+	atr repositories.ActivationTokenRepository
+	eor repositories.EmailOutboxRepository
+	alr repositories.AuditLogRepository
+	tm  repositories.TransactionManager
+	lg  LoginGuard
+	c   storage.Cache
+	ef  *email.EmailFactory
 }
 
 func NewAuthService(i *di.Injector) (AuthService, error) {
@@ -28,9 +53,14 @@ func NewAuthService(i *di.Injector) (AuthService, error) {
 		return nil, fmt.Errorf("invoke secure service: %w", err)
 	}
 
-	ts, err := di.Invoke[TokenService](i)
+	ses, err := di.Invoke[SessionService](i)
 	if err != nil {
-		return nil, fmt.Errorf("invoke token service: %w", err)
+		return nil, fmt.Errorf("invoke session service: %w", err)
+	}
+
+	tfs, err := di.Invoke[TwoFactorService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke two-factor service: %w", err)
 	}
 
 	ur, err := di.Invoke[repositories.UserRepository](i)
@@ -38,42 +68,552 @@ func NewAuthService(i *di.Injector) (AuthService, error) {
 		return nil, fmt.Errorf("invoke user repository: %w", err)
 	}
 
+	atr, err := di.Invoke[repositories.ActivationTokenRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke activation token repository: %w", err)
+	}
+
+	eor, err := di.Invoke[repositories.EmailOutboxRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email outbox repository: %w", err)
+	}
+
+	alr, err := di.Invoke[repositories.AuditLogRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke audit log repository: %w", err)
+	}
+
+	tm, err := di.Invoke[repositories.TransactionManager](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke transaction manager: %w", err)
+	}
+
+	lg, err := di.Invoke[LoginGuard](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke login guard: %w", err)
+	}
+
+	c, err := di.Invoke[storage.Cache](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke cache: %w", err)
+	}
+
 	return &authService{
-		i:  i,
-		ss: ss,
-		ts: ts,
-		ur: ur,
+		i:   i,
+		ss:  ss,
+		ses: ses,
+		tfs: tfs,
+		ur:  ur,
+		atr: atr,
+		eor: eor,
+		alr: alr,
+		tm:  tm,
+		lg:  lg,
+		c:   c,
+		ef:  email.NewEmailFactory(),
 	}, nil
 }
 
-func (a *authService) Login(ctx context.Context, payload models.LoginPayload) (*models.LoginResponse, error) {
-	userFromCPF, err := a.ur.GetUserByCPF(ctx, utils.RemoveCPFFormat(payload.CPF))
+// Login checks the credentials of a user. Failed attempts are counted per CPF
+// and per address; once a CPF reaches the limit its account is locked for a
+// while and the owner is warned by email. Users with two-factor
+// authentication, or whose role requires it, get a challenge to answer
+// through VerifyTwoFactor instead of a session.
+func (a *authService) Login(ctx context.Context, payload models.LoginPayload) (*models.LoginResult, error) {
+	cpf := utils.RemoveCPFFormat(payload.CPF)
+	ip, _ := request.ClientInfo(ctx)
+
+	if err := a.lg.CheckIP(ctx, ip); err != nil {
+		return nil, err
+	}
+
+	userFromCPF, err := a.ur.GetUserByCPF(ctx, cpf)
 	if err != nil {
 		return nil, fmt.Errorf("get user by CPF: %w", err)
 	}
 
 	if userFromCPF == nil {
-		return nil, models.ErrInvalidCredentials
+		return nil, a.loginFailed(ctx, cpf, nil)
 	}
 
 	if userFromCPF.Status == models.BlockedStatus {
 		return nil, models.ErrUserBlocked
 	}
 
+	if userFromCPF.IsLocked(time.Now().UTC()) {
+		return nil, models.ErrAccountLocked
+	}
+
 	if err := a.ss.CheckPassword(ctx, userFromCPF.PasswordHash, payload.Password); err != nil {
-		return nil, models.ErrInvalidCredentials
+		return nil, a.loginFailed(ctx, cpf, userFromCPF)
+	}
+
+	// The failures of the CPF are kept until the second factor is answered,
+	// so guessing codes counts towards the lockout too.
+	if needsTwoFactor(userFromCPF) {
+		challenge, err := a.createTwoFactorChallenge(ctx, userFromCPF)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.LoginResult{Challenge: challenge}, nil
+	}
+
+	if err := a.lg.Reset(ctx, cpf); err != nil {
+		return nil, err
+	}
+
+	session, err := a.ses.CreateSession(ctx, userFromCPF.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResult{Session: session}, nil
+}
+
+// StartTwoFactorEnrollment lets a user whose role requires two-factor
+// authentication enroll with the challenge of their login, before they hold
+// a session.
+func (a *authService) StartTwoFactorEnrollment(ctx context.Context, payload models.TwoFactorChallengePayload) (*models.TwoFactorEnrollmentResponse, error) {
+	user, err := a.getChallengeUser(ctx, payload.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled() {
+		return nil, models.ErrTwoFactorAlreadyEnabled
+	}
+
+	return a.tfs.BeginEnrollment(ctx, *user)
+}
+
+// VerifyTwoFactor answers the challenge of a login with a TOTP or recovery
+// code and starts the session. For a user still enrolling, the code confirms
+// the enrollment and the recovery codes are returned along with the session.
+// Wrong codes count as failed logins.
+func (a *authService) VerifyTwoFactor(ctx context.Context, payload models.VerifyTwoFactorPayload) (*models.TwoFactorLoginResponse, error) {
+	challenge, user, err := a.claimChallenge(ctx, payload.ChallengeToken)
+	if err != nil {
+		return nil, err
 	}
 
-	tokenPayload := models.TokenPayload{
-		UserID: userFromCPF.ID,
+	var recoveryCodes []string
+	if user.TwoFactorEnabled() {
+		err = a.tfs.VerifyCode(ctx, user, payload.Code)
+	} else {
+		recoveryCodes, err = a.tfs.CompleteEnrollment(ctx, user, payload.Code)
+	}
+
+	// Once wrong codes lock the account, the challenge is not restored and
+	// challengeUser would refuse it anyway.
+	if errors.Is(err, models.ErrInvalidTwoFactorCode) {
+		if err := a.loginFailed(ctx, user.CPF, user); !errors.Is(err, models.ErrInvalidCredentials) {
+			return nil, err
+		}
+
+		// A mistyped code should not send the user back to the password step.
+		if err := a.restoreChallenge(ctx, payload.ChallengeToken, challenge); err != nil {
+			return nil, err
+		}
+
+		return nil, models.ErrInvalidTwoFactorCode
 	}
 
-	token, err := a.ts.CreateToken(ctx, tokenPayload)
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.LoginResponse{
-		Token: token,
+	if err := a.lg.Reset(ctx, user.CPF); err != nil {
+		return nil, err
+	}
+
+	session, err := a.ses.CreateSession(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorLoginResponse{
+		LoginResponse: *session,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (a *authService) createTwoFactorChallenge(ctx context.Context, user *models.User) (*models.TwoFactorChallengeResponse, error) {
+	token, err := a.ss.CreateToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create challenge token: %w", err)
+	}
+
+	ttl := time.Duration(config.Env.TwoFactor.ChallengeExp) * time.Minute
+	challenge := models.TwoFactorChallenge{UserID: user.ID, ExpiresAt: time.Now().UTC().Add(ttl)}
+
+	if err := a.c.Set(ctx, twoFactorChallengeKey(models.HashToken(token)), challenge, ttl); err != nil {
+		return nil, fmt.Errorf("store two-factor challenge: %w", err)
+	}
+
+	return &models.TwoFactorChallengeResponse{
+		ChallengeToken:     token,
+		EnrollmentRequired: !user.TwoFactorEnabled(),
+		ExpiresIn:          int64(ttl / time.Second),
 	}, nil
 }
+
+// getChallengeUser returns the user of a pending challenge, leaving the
+// challenge in place.
+func (a *authService) getChallengeUser(ctx context.Context, challengeToken string) (*models.User, error) {
+	var challenge models.TwoFactorChallenge
+	if err := a.c.Get(ctx, twoFactorChallengeKey(models.HashToken(challengeToken)), &challenge); err != nil {
+		if errors.Is(err, storage.ErrCacheMiss) {
+			return nil, models.ErrTwoFactorChallengeInvalid
+		}
+
+		return nil, fmt.Errorf("get two-factor challenge: %w", err)
+	}
+
+	return a.challengeUser(ctx, challenge)
+}
+
+// claimChallenge removes a pending challenge and returns it with its user.
+// Removing it before the code is checked keeps concurrent requests from
+// answering the same challenge twice.
+func (a *authService) claimChallenge(ctx context.Context, challengeToken string) (*models.TwoFactorChallenge, *models.User, error) {
+	var challenge models.TwoFactorChallenge
+	if err := a.c.GetDel(ctx, twoFactorChallengeKey(models.HashToken(challengeToken)), &challenge); err != nil {
+		if errors.Is(err, storage.ErrCacheMiss) {
+			return nil, nil, models.ErrTwoFactorChallengeInvalid
+		}
+
+		return nil, nil, fmt.Errorf("claim two-factor challenge: %w", err)
+	}
+
+	user, err := a.challengeUser(ctx, challenge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &challenge, user, nil
+}
+
+// challengeUser returns the user of a challenge, checking again that they
+// may still log in.
+func (a *authService) challengeUser(ctx context.Context, challenge models.TwoFactorChallenge) (*models.User, error) {
+	user, err := a.ur.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", challenge.UserID, err)
+	}
+
+	if user == nil {
+		return nil, models.ErrTwoFactorChallengeInvalid
+	}
+
+	if user.Status == models.BlockedStatus {
+		return nil, models.ErrUserBlocked
+	}
+
+	if user.IsLocked(time.Now().UTC()) {
+		return nil, models.ErrAccountLocked
+	}
+
+	return user, nil
+}
+
+// restoreChallenge puts a claimed challenge back until its original
+// expiration, so it can be answered again.
+func (a *authService) restoreChallenge(ctx context.Context, challengeToken string, challenge *models.TwoFactorChallenge) error {
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := a.c.Set(ctx, twoFactorChallengeKey(models.HashToken(challengeToken)), *challenge, ttl); err != nil {
+		return fmt.Errorf("restore two-factor challenge: %w", err)
+	}
+
+	return nil
+}
+
+// needsTwoFactor reports whether logging in as the user takes a second step,
+// either because they enrolled or because their role requires it.
+func needsTwoFactor(user *models.User) bool {
+	return user.TwoFactorEnabled() || (user.CanUseTwoFactor() && twoFactorRequired(user.Role))
+}
+
+// loginFailed registers a failed login and returns the error to report. The
+// failure that reaches the limit locks the account.
+func (a *authService) loginFailed(ctx context.Context, cpf string, user *models.User) error {
+	ip, _ := request.ClientInfo(ctx)
+
+	failures, err := a.lg.RegisterFailure(ctx, cpf, ip)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || failures < config.Env.Login.MaxAttempts {
+		return models.ErrInvalidCredentials
+	}
+
+	if err := a.lockAccount(ctx, user, failures); err != nil {
+		return err
+	}
+
+	return models.ErrAccountLocked
+}
+
+func (a *authService) lockAccount(ctx context.Context, user *models.User, failures int) error {
+	ip, userAgent := request.ClientInfo(ctx)
+	lockedUntil := time.Now().UTC().Add(time.Duration(config.Env.Login.LockoutDuration) * time.Minute)
+
+	user.Lock(lockedUntil)
+
+	err := a.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := a.ur.UpdateUser(ctx, *user); err != nil {
+			return fmt.Errorf("update user %q: %w", user.ID, err)
+		}
+
+		details := fmt.Sprintf("%d failed logins, locked until %s", failures, lockedUntil.Format(time.RFC3339))
+		auditLog := models.NewAuditLog(models.AccountLockedAction, &user.ID, ip, userAgent, &details)
+
+		if err := a.alr.CreateAuditLog(ctx, *auditLog); err != nil {
+			return fmt.Errorf("create audit log: %w", err)
+		}
+
+		sendEmailPayload := a.ef.CreateAccountLockedSendEmail(user.Email, "Sua conta foi bloqueada temporariamente", user.FullName, ip, failures, lockedUntil)
+
+		email, err := models.NewEmailOutbox(sendEmailPayload)
+		if err != nil {
+			return err
+		}
+
+		if err := a.eor.CreateEmailOutbox(ctx, *email); err != nil {
+			return fmt.Errorf("queue account locked email for user %q: %w", user.ID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The lockout itself now slows the CPF down; it starts with a clean slate
+	// once the lock expires.
+	return a.lg.Reset(ctx, user.CPF)
+}
+
+// Logout revokes the session of the given tokens, so copies of the cookies
+// stop working immediately. It does not require a valid access token: the
+// refresh token is enough to end a session whose access token has expired.
+func (a *authService) Logout(ctx context.Context, accessToken string, refreshToken string) error {
+	return a.ses.EndSession(ctx, accessToken, refreshToken)
+}
+
+func (a *authService) ActivateAccount(ctx context.Context, payload models.ActivateAccountPayload) error {
+	passwordHash, err := a.ss.HashPassword(ctx, payload.Password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	return a.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		activationToken, err := a.atr.GetActivationTokenByHash(ctx, models.HashToken(payload.Token))
+		if err != nil {
+			return fmt.Errorf("get activation token: %w", err)
+		}
+
+		if activationToken == nil {
+			return models.ErrActivationTokenInvalid
+		}
+
+		if err := activationToken.Use(time.Now().UTC()); err != nil {
+			return err
+		}
+
+		user := activationToken.User
+		user.ChangePassword(passwordHash)
+
+		if err := a.ur.UpdateUser(ctx, user); err != nil {
+			return fmt.Errorf("update user %q: %w", user.ID, err)
+		}
+
+		if err := a.atr.UpdateActivationToken(ctx, *activationToken); err != nil {
+			return fmt.Errorf("update activation token %q: %w", activationToken.ID, err)
+		}
+
+		return nil
+	})
+}
+
+func (a *authService) Refresh(ctx context.Context, payload models.RefreshTokenPayload) (*models.LoginResponse, error) {
+	return a.ses.RefreshSession(ctx, payload.RefreshToken)
+}
+
+// ChangePassword replaces the password of the authenticated user and revokes
+// all of their sessions. The returned token starts a fresh session for the
+// caller.
+func (a *authService) ChangePassword(ctx context.Context, payload models.ChangePasswordPayload) (*models.LoginResponse, error) {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return nil, models.ErrUserNotFoundInContext
+	}
+
+	user, err := a.ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", userID, err)
+	}
+
+	if user == nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	if err := a.ss.CheckPassword(ctx, user.PasswordHash, payload.CurrentPassword); err != nil {
+		return nil, models.ErrInvalidPassword
+	}
+
+	if payload.NewPassword == payload.CurrentPassword {
+		return nil, models.ErrSamePassword
+	}
+
+	passwordHash, err := a.ss.HashPassword(ctx, payload.NewPassword)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	user.ChangePassword(passwordHash)
+	user.RevokeSessions(time.Now().UTC())
+
+	if err := a.ur.UpdateUser(ctx, *user); err != nil {
+		return nil, fmt.Errorf("update user %q: %w", userID, err)
+	}
+
+	if err := a.ses.RevokeAllSessions(ctx, userID); err != nil {
+		return nil, fmt.Errorf("revoke sessions of user %q: %w", userID, err)
+	}
+
+	return a.ses.CreateSession(ctx, userID)
+}
+
+// ForgotPassword emails a single-use reset link. It succeeds even when no
+// user matches the login, so the endpoint cannot be used to find accounts.
+func (a *authService) ForgotPassword(ctx context.Context, payload models.ForgotPasswordPayload) error {
+	user, err := a.getUserByLogin(ctx, payload.Login)
+	if err != nil {
+		return err
+	}
+
+	if user == nil || user.Status == models.BlockedStatus {
+		return nil
+	}
+
+	token, err := a.ss.CreateToken(ctx)
+	if err != nil {
+		return fmt.Errorf("create reset token: %w", err)
+	}
+
+	ttl := time.Duration(config.Env.PasswordReset.TokenExp) * time.Minute
+	tokenHash := models.HashToken(token)
+
+	// Only the latest link stays valid.
+	var previousHash string
+	if err := a.c.Get(ctx, passwordResetUserKey(user.ID), &previousHash); err == nil {
+		if err := a.c.Delete(ctx, passwordResetKey(previousHash)); err != nil {
+			return fmt.Errorf("delete previous reset token: %w", err)
+		}
+	} else if !errors.Is(err, storage.ErrCacheMiss) {
+		return fmt.Errorf("get previous reset token: %w", err)
+	}
+
+	if err := a.c.Set(ctx, passwordResetKey(tokenHash), models.PasswordReset{UserID: user.ID}, ttl); err != nil {
+		return fmt.Errorf("store reset token: %w", err)
+	}
+
+	if err := a.c.Set(ctx, passwordResetUserKey(user.ID), tokenHash, ttl); err != nil {
+		return fmt.Errorf("store user reset token: %w", err)
+	}
+
+	resetURL := fmt.Sprintf("%s?token=%s", config.Env.PasswordReset.URL, url.QueryEscape(token))
+	sendEmailPayload := a.ef.CreatePasswordResetSendEmail(user.Email, "Redefinição de senha", user.FullName, resetURL, ttl)
+
+	email, err := models.NewEmailOutbox(sendEmailPayload)
+	if err != nil {
+		return err
+	}
+
+	if err := a.eor.CreateEmailOutbox(ctx, *email); err != nil {
+		return fmt.Errorf("queue password reset email for user %q: %w", user.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword consumes a reset token, sets the new password and revokes
+// every session of the user.
+func (a *authService) ResetPassword(ctx context.Context, payload models.ResetPasswordPayload) error {
+	passwordHash, err := a.ss.HashPassword(ctx, payload.Password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	var reset models.PasswordReset
+	if err := a.c.GetDel(ctx, passwordResetKey(models.HashToken(payload.Token)), &reset); err != nil {
+		if errors.Is(err, storage.ErrCacheMiss) {
+			return models.ErrResetTokenInvalid
+		}
+
+		return fmt.Errorf("get reset token: %w", err)
+	}
+
+	if err := a.c.Delete(ctx, passwordResetUserKey(reset.UserID)); err != nil {
+		return fmt.Errorf("delete user reset token: %w", err)
+	}
+
+	user, err := a.ur.GetUserByID(ctx, reset.UserID)
+	if err != nil {
+		return fmt.Errorf("get user by id %q: %w", reset.UserID, err)
+	}
+
+	if user == nil {
+		return models.ErrResetTokenInvalid
+	}
+
+	user.ChangePassword(passwordHash)
+	user.RevokeSessions(time.Now().UTC())
+	user.Unlock()
+
+	if err := a.ur.UpdateUser(ctx, *user); err != nil {
+		return fmt.Errorf("update user %q: %w", user.ID, err)
+	}
+
+	if err := a.ses.RevokeAllSessions(ctx, user.ID); err != nil {
+		return fmt.Errorf("revoke sessions of user %q: %w", user.ID, err)
+	}
+
+	return nil
+}
+
+func (a *authService) getUserByLogin(ctx context.Context, login string) (*models.User, error) {
+	if strings.Contains(login, "@") {
+		user, err := a.ur.GetUserByEmail(ctx, login)
+		if err != nil {
+			return nil, fmt.Errorf("get user by email: %w", err)
+		}
+
+		return user, nil
+	}
+
+	user, err := a.ur.GetUserByCPF(ctx, utils.RemoveCPFFormat(login))
+	if err != nil {
+		return nil, fmt.Errorf("get user by CPF: %w", err)
+	}
+
+	return user, nil
+}
+
+func passwordResetKey(tokenHash string) string {
+	return fmt.Sprintf("password-reset:%s", tokenHash)
+}
+
+func passwordResetUserKey(userID uuid.UUID) string {
+	return fmt.Sprintf("password-reset:user:%s", userID)
+}
+
+func twoFactorChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("two-factor:challenge:%s", tokenHash)
+}
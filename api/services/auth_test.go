@@ -4,24 +4,40 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/mocks"
 	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/services/email"
+	"github.com/G-Villarinho/fast-feet-api/storage"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestAuthService_Login(t *testing.T) {
+	config.Env.Login = config.Login{MaxAttempts: 5, LockoutDuration: 15}
+
+	newLoginGuard := func() *mocks.LoginGuard {
+		mockLoginGuard := new(mocks.LoginGuard)
+		mockLoginGuard.On("CheckIP", mock.Anything, mock.Anything).Return(nil).Maybe()
+		mockLoginGuard.On("RegisterFailure", mock.Anything, mock.Anything, mock.Anything).Return(1, nil).Maybe()
+		mockLoginGuard.On("Reset", mock.Anything, mock.Anything).Return(nil).Maybe()
+		return mockLoginGuard
+	}
+
 	t.Run("WhenUserNotFound_ShouldReturnErrInvalidCredentials", func(t *testing.T) {
 		mockRepo := new(mocks.UserRepository)
-		mockTokenService := new(mocks.TokenService)
+		mockSessionService := new(mocks.SessionService)
 		mockSecureService := new(mocks.SecureService)
 
 		service := authService{
-			ur: mockRepo,
-			ts: mockTokenService,
-			ss: mockSecureService,
+			ur:  mockRepo,
+			ses: mockSessionService,
+			ss:  mockSecureService,
+			lg:  newLoginGuard(),
 		}
 
 		payload := models.LoginPayload{
@@ -41,13 +57,14 @@ func TestAuthService_Login(t *testing.T) {
 
 	t.Run("WhenUserIsBlocked_ShouldReturnErrUserBlocked", func(t *testing.T) {
 		mockRepo := new(mocks.UserRepository)
-		mockTokenService := new(mocks.TokenService)
+		mockSessionService := new(mocks.SessionService)
 		mockSecureService := new(mocks.SecureService)
 
 		service := authService{
-			ur: mockRepo,
-			ts: mockTokenService,
-			ss: mockSecureService,
+			ur:  mockRepo,
+			ses: mockSessionService,
+			ss:  mockSecureService,
+			lg:  newLoginGuard(),
 		}
 
 		payload := models.LoginPayload{
@@ -71,13 +88,14 @@ func TestAuthService_Login(t *testing.T) {
 
 	t.Run("WhenPasswordIsIncorrect_ShouldReturnErrInvalidCredentials", func(t *testing.T) {
 		mockRepo := new(mocks.UserRepository)
-		mockTokenService := new(mocks.TokenService)
+		mockSessionService := new(mocks.SessionService)
 		mockSecureService := new(mocks.SecureService)
 
 		service := authService{
-			ur: mockRepo,
-			ts: mockTokenService,
-			ss: mockSecureService,
+			ur:  mockRepo,
+			ses: mockSessionService,
+			ss:  mockSecureService,
+			lg:  newLoginGuard(),
 		}
 
 		payload := models.LoginPayload{
@@ -105,13 +123,14 @@ func TestAuthService_Login(t *testing.T) {
 
 	t.Run("WhenLoginIsSuccessful_ShouldReturnToken", func(t *testing.T) {
 		mockRepo := new(mocks.UserRepository)
-		mockTokenService := new(mocks.TokenService)
+		mockSessionService := new(mocks.SessionService)
 		mockSecureService := new(mocks.SecureService)
 
 		service := authService{
-			ur: mockRepo,
-			ts: mockTokenService,
-			ss: mockSecureService,
+			ur:  mockRepo,
+			ses: mockSessionService,
+			ss:  mockSecureService,
+			lg:  newLoginGuard(),
 		}
 
 		payload := models.LoginPayload{
@@ -134,16 +153,681 @@ func TestAuthService_Login(t *testing.T) {
 		mockSecureService.On("CheckPassword", mock.Anything, user.PasswordHash, payload.Password).
 			Return(nil)
 
-		mockTokenService.On("CreateToken", mock.Anything, mock.Anything).
-			Return("some-jwt-token", nil)
+		mockSessionService.On("CreateSession", mock.Anything, user.ID).
+			Return(&models.LoginResponse{Token: "some-jwt-token"}, nil)
 
 		response, err := service.Login(context.Background(), payload)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
-		assert.Equal(t, "some-jwt-token", response.Token)
+		assert.Equal(t, "some-jwt-token", response.Session.Token)
 		mockRepo.AssertExpectations(t)
 		mockSecureService.AssertExpectations(t)
-		mockTokenService.AssertExpectations(t)
+		mockSessionService.AssertExpectations(t)
+	})
+
+	t.Run("WhenUserHasTwoFactor_ShouldReturnChallengeAndKeepFailures", func(t *testing.T) {
+		config.Env.TwoFactor = config.TwoFactor{ChallengeExp: 5}
+
+		mockRepo := new(mocks.UserRepository)
+		mockSessionService := new(mocks.SessionService)
+		mockSecureService := new(mocks.SecureService)
+		mockLoginGuard := new(mocks.LoginGuard)
+		mockCache := new(mocks.Cache)
+
+		service := authService{
+			ur:  mockRepo,
+			ses: mockSessionService,
+			ss:  mockSecureService,
+			lg:  mockLoginGuard,
+			c:   mockCache,
+		}
+
+		user := &models.User{
+			BaseModel:    models.BaseModel{ID: uuid.New()},
+			PasswordHash: "hash",
+			Role:         models.Admin,
+		}
+		user.EnableTwoFactor("SECRET", 0, time.Now())
+
+		mockLoginGuard.On("CheckIP", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetUserByCPF", mock.Anything, "12345678900").Return(user, nil)
+		mockSecureService.On("CheckPassword", mock.Anything, "hash", "password").Return(nil)
+		mockSecureService.On("CreateToken", mock.Anything).Return("challenge-token", nil)
+		mockCache.On("Set", mock.Anything, "two-factor:challenge:"+models.HashToken("challenge-token"), mock.MatchedBy(func(c models.TwoFactorChallenge) bool {
+			return c.UserID == user.ID && time.Until(c.ExpiresAt) > 4*time.Minute
+		}), 5*time.Minute).
+			Return(nil)
+
+		result, err := service.Login(context.Background(), models.LoginPayload{CPF: "12345678900", Password: "password"})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Session)
+		assert.Equal(t, "challenge-token", result.Challenge.ChallengeToken)
+		assert.False(t, result.Challenge.EnrollmentRequired)
+		assert.Equal(t, int64(300), result.Challenge.ExpiresIn)
+		mockCache.AssertExpectations(t)
+		mockLoginGuard.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+		mockSessionService.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenRoleRequiresTwoFactorWithoutEnrollment_ShouldRequireEnrollment", func(t *testing.T) {
+		config.Env.TwoFactor = config.TwoFactor{ChallengeExp: 5, RequiredRoles: []string{string(models.Owner)}}
+		defer func() { config.Env.TwoFactor = config.TwoFactor{} }()
+
+		mockRepo := new(mocks.UserRepository)
+		mockSecureService := new(mocks.SecureService)
+		mockCache := new(mocks.Cache)
+
+		service := authService{
+			ur: mockRepo,
+			ss: mockSecureService,
+			lg: newLoginGuard(),
+			c:  mockCache,
+		}
+
+		user := &models.User{
+			BaseModel:    models.BaseModel{ID: uuid.New()},
+			PasswordHash: "hash",
+			Role:         models.Owner,
+		}
+
+		mockRepo.On("GetUserByCPF", mock.Anything, "12345678900").Return(user, nil)
+		mockSecureService.On("CheckPassword", mock.Anything, "hash", "password").Return(nil)
+		mockSecureService.On("CreateToken", mock.Anything).Return("challenge-token", nil)
+		mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		result, err := service.Login(context.Background(), models.LoginPayload{CPF: "12345678900", Password: "password"})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Challenge.EnrollmentRequired)
+	})
+
+	t.Run("WhenAddressExceededAttempts_ShouldReturnErrTooManyLoginAttempts", func(t *testing.T) {
+		mockRepo := new(mocks.UserRepository)
+		mockLoginGuard := new(mocks.LoginGuard)
+
+		service := authService{
+			ur: mockRepo,
+			lg: mockLoginGuard,
+		}
+
+		ctx := request.WithClientInfo(context.Background(), "203.0.113.42", "curl/8.0")
+		mockLoginGuard.On("CheckIP", mock.Anything, "203.0.113.42").
+			Return(models.ErrTooManyLoginAttempts)
+
+		response, err := service.Login(ctx, models.LoginPayload{CPF: "12345678900", Password: "password"})
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrTooManyLoginAttempts)
+		mockRepo.AssertNotCalled(t, "GetUserByCPF", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenAccountIsLocked_ShouldReturnErrAccountLockedWithoutCheckingPassword", func(t *testing.T) {
+		mockRepo := new(mocks.UserRepository)
+		mockSecureService := new(mocks.SecureService)
+
+		service := authService{
+			ur: mockRepo,
+			ss: mockSecureService,
+			lg: newLoginGuard(),
+		}
+
+		user := &models.User{Status: models.ActiveStatus}
+		user.Lock(time.Now().Add(time.Minute))
+
+		mockRepo.On("GetUserByCPF", mock.Anything, "12345678900").
+			Return(user, nil)
+
+		response, err := service.Login(context.Background(), models.LoginPayload{CPF: "123.456.789-00", Password: "password"})
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrAccountLocked)
+		mockSecureService.AssertNotCalled(t, "CheckPassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenFailuresReachLimit_ShouldLockAccountAuditAndWarnOwner", func(t *testing.T) {
+		mockRepo := new(mocks.UserRepository)
+		mockSecureService := new(mocks.SecureService)
+		mockLoginGuard := new(mocks.LoginGuard)
+		mockAuditLogRepo := new(mocks.AuditLogRepository)
+		mockEmailOutboxRepo := new(mocks.EmailOutboxRepository)
+		mockTransactionManager := new(mocks.TransactionManager)
+
+		service := authService{
+			ur:  mockRepo,
+			ss:  mockSecureService,
+			lg:  mockLoginGuard,
+			alr: mockAuditLogRepo,
+			eor: mockEmailOutboxRepo,
+			tm:  mockTransactionManager,
+			ef:  email.NewEmailFactory(),
+		}
+
+		user := &models.User{
+			BaseModel:    models.BaseModel{ID: uuid.New()},
+			CPF:          "12345678900",
+			Email:        "john@example.com",
+			PasswordHash: "hash",
+			Status:       models.ActiveStatus,
+		}
+
+		ctx := request.WithClientInfo(context.Background(), "203.0.113.42", "curl/8.0")
+
+		mockLoginGuard.On("CheckIP", mock.Anything, "203.0.113.42").Return(nil)
+		mockRepo.On("GetUserByCPF", mock.Anything, "12345678900").Return(user, nil)
+		mockSecureService.On("CheckPassword", mock.Anything, "hash", "wrong").Return(errors.New("mismatch"))
+		mockLoginGuard.On("RegisterFailure", mock.Anything, "12345678900", "203.0.113.42").Return(5, nil)
+
+		mockTransactionManager.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		mockRepo.On("UpdateUser", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+			return u.IsLocked(time.Now())
+		})).Return(nil)
+
+		mockAuditLogRepo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l models.AuditLog) bool {
+			return l.Action == models.AccountLockedAction && *l.UserID == user.ID && l.IP == "203.0.113.42"
+		})).Return(nil)
+
+		mockEmailOutboxRepo.On("CreateEmailOutbox", mock.Anything, mock.MatchedBy(func(e models.EmailOutbox) bool {
+			return e.To == user.Email
+		})).Return(nil)
+
+		mockLoginGuard.On("Reset", mock.Anything, "12345678900").Return(nil)
+
+		response, err := service.Login(ctx, models.LoginPayload{CPF: "12345678900", Password: "wrong"})
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrAccountLocked)
+		mockRepo.AssertExpectations(t)
+		mockAuditLogRepo.AssertExpectations(t)
+		mockEmailOutboxRepo.AssertExpectations(t)
+		mockLoginGuard.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_VerifyTwoFactor(t *testing.T) {
+	config.Env.Login = config.Login{MaxAttempts: 5, LockoutDuration: 15}
+
+	challengeKey := "two-factor:challenge:" + models.HashToken("challenge-token")
+	payload := models.VerifyTwoFactorPayload{ChallengeToken: "challenge-token", Code: "123456"}
+
+	newUser := func() *models.User {
+		user := &models.User{
+			BaseModel: models.BaseModel{ID: uuid.New()},
+			CPF:       "12345678900",
+			Status:    models.ActiveStatus,
+			Role:      models.Admin,
+		}
+		user.EnableTwoFactor("SECRET", 0, time.Now())
+		return user
+	}
+
+	withChallenge := func(mockCache *mocks.Cache, userID uuid.UUID) {
+		mockCache.On("GetDel", mock.Anything, challengeKey, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.TwoFactorChallenge) = models.TwoFactorChallenge{
+					UserID:    userID,
+					ExpiresAt: time.Now().Add(3 * time.Minute),
+				}
+			}).
+			Return(nil).
+			Once()
+	}
+
+	t.Run("WhenChallengeIsUnknown_ShouldReturnErrTwoFactorChallengeInvalid", func(t *testing.T) {
+		mockCache := new(mocks.Cache)
+		service := authService{c: mockCache}
+
+		mockCache.On("GetDel", mock.Anything, challengeKey, mock.Anything).Return(storage.ErrCacheMiss)
+
+		response, err := service.VerifyTwoFactor(context.Background(), payload)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrTwoFactorChallengeInvalid)
+	})
+
+	t.Run("WhenCodeIsWrong_ShouldCountFailedLoginAndRestoreChallenge", func(t *testing.T) {
+		mockRepo := new(mocks.UserRepository)
+		mockTwoFactorService := new(mocks.TwoFactorService)
+		mockLoginGuard := new(mocks.LoginGuard)
+		mockCache := new(mocks.Cache)
+
+		service := authService{
+			ur:  mockRepo,
+			tfs: mockTwoFactorService,
+			lg:  mockLoginGuard,
+			c:   mockCache,
+		}
+
+		user := newUser()
+		withChallenge(mockCache, user.ID)
+
+		mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		mockTwoFactorService.On("VerifyCode", mock.Anything, user, "123456").Return(models.ErrInvalidTwoFactorCode)
+		mockLoginGuard.On("RegisterFailure", mock.Anything, "12345678900", mock.Anything).Return(2, nil)
+		mockCache.On("Set", mock.Anything, challengeKey, mock.MatchedBy(func(c models.TwoFactorChallenge) bool {
+			return c.UserID == user.ID
+		}), mock.MatchedBy(func(ttl time.Duration) bool {
+			return ttl > 0 && ttl <= 3*time.Minute
+		})).Return(nil)
+
+		response, err := service.VerifyTwoFactor(context.Background(), payload)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrInvalidTwoFactorCode)
+		mockLoginGuard.AssertExpectations(t)
+		mockCache.AssertExpectations(t)
+	})
+
+	t.Run("WhenChallengeIsAnsweredTwice_ShouldRefuseSecondAnswer", func(t *testing.T) {
+		mockRepo := new(mocks.UserRepository)
+		mockTwoFactorService := new(mocks.TwoFactorService)
+		mockSessionService := new(mocks.SessionService)
+		mockLoginGuard := new(mocks.LoginGuard)
+		mockCache := new(mocks.Cache)
+
+		service := authService{
+			ur:  mockRepo,
+			tfs: mockTwoFactorService,
+			ses: mockSessionService,
+			lg:  mockLoginGuard,
+			c:   mockCache,
+		}
+
+		user := newUser()
+		withChallenge(mockCache, user.ID)
+		mockCache.On("GetDel", mock.Anything, challengeKey, mock.Anything).Return(storage.ErrCacheMiss)
+
+		mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		mockTwoFactorService.On("VerifyCode", mock.Anything, user, "123456").Return(nil)
+		mockLoginGuard.On("Reset", mock.Anything, "12345678900").Return(nil)
+		mockSessionService.On("CreateSession", mock.Anything, user.ID).
+			Return(&models.LoginResponse{Token: "some-jwt-token"}, nil)
+
+		_, err := service.VerifyTwoFactor(context.Background(), payload)
+		assert.NoError(t, err)
+
+		response, err := service.VerifyTwoFactor(context.Background(), payload)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrTwoFactorChallengeInvalid)
+		mockSessionService.AssertNumberOfCalls(t, "CreateSession", 1)
+	})
+
+	t.Run("WhenCodeIsValid_ShouldConsumeChallengeAndCreateSession", func(t *testing.T) {
+		mockRepo := new(mocks.UserRepository)
+		mockTwoFactorService := new(mocks.TwoFactorService)
+		mockSessionService := new(mocks.SessionService)
+		mockLoginGuard := new(mocks.LoginGuard)
+		mockCache := new(mocks.Cache)
+
+		service := authService{
+			ur:  mockRepo,
+			tfs: mockTwoFactorService,
+			ses: mockSessionService,
+			lg:  mockLoginGuard,
+			c:   mockCache,
+		}
+
+		user := newUser()
+		withChallenge(mockCache, user.ID)
+
+		mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		mockTwoFactorService.On("VerifyCode", mock.Anything, user, "123456").Return(nil)
+		mockLoginGuard.On("Reset", mock.Anything, "12345678900").Return(nil)
+		mockSessionService.On("CreateSession", mock.Anything, user.ID).
+			Return(&models.LoginResponse{Token: "some-jwt-token"}, nil)
+
+		response, err := service.VerifyTwoFactor(context.Background(), payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "some-jwt-token", response.Token)
+		assert.Empty(t, response.RecoveryCodes)
+		mockCache.AssertExpectations(t)
+		mockLoginGuard.AssertExpectations(t)
+		mockSessionService.AssertExpectations(t)
+	})
+
+	t.Run("WhenUserIsEnrolling_ShouldCompleteEnrollmentAndReturnRecoveryCodes", func(t *testing.T) {
+		mockRepo := new(mocks.UserRepository)
+		mockTwoFactorService := new(mocks.TwoFactorService)
+		mockSessionService := new(mocks.SessionService)
+		mockLoginGuard := new(mocks.LoginGuard)
+		mockCache := new(mocks.Cache)
+
+		service := authService{
+			ur:  mockRepo,
+			tfs: mockTwoFactorService,
+			ses: mockSessionService,
+			lg:  mockLoginGuard,
+			c:   mockCache,
+		}
+
+		user := newUser()
+		user.DisableTwoFactor()
+		withChallenge(mockCache, user.ID)
+
+		mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		mockTwoFactorService.On("CompleteEnrollment", mock.Anything, user, "123456").Return([]string{"abcde-fghjk"}, nil)
+		mockLoginGuard.On("Reset", mock.Anything, "12345678900").Return(nil)
+		mockSessionService.On("CreateSession", mock.Anything, user.ID).
+			Return(&models.LoginResponse{Token: "some-jwt-token"}, nil)
+
+		response, err := service.VerifyTwoFactor(context.Background(), payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"abcde-fghjk"}, response.RecoveryCodes)
+		mockTwoFactorService.AssertNotCalled(t, "VerifyCode", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_ActivateAccount(t *testing.T) {
+	newService := func() (*authService, *mocks.ActivationTokenRepository, *mocks.UserRepository, *mocks.SecureService) {
+		activationTokenRepoMock := new(mocks.ActivationTokenRepository)
+		userRepoMock := new(mocks.UserRepository)
+		secureServiceMock := new(mocks.SecureService)
+		transactionManagerMock := new(mocks.TransactionManager)
+
+		transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		return &authService{
+			atr: activationTokenRepoMock,
+			ur:  userRepoMock,
+			ss:  secureServiceMock,
+			tm:  transactionManagerMock,
+		}, activationTokenRepoMock, userRepoMock, secureServiceMock
+	}
+
+	payload := models.ActivateAccountPayload{
+		Token:    "activation-token",
+		Password: "new-password",
+	}
+
+	t.Run("WhenTokenIsValid_ShouldSetPasswordAndConsumeToken", func(t *testing.T) {
+		service, activationTokenRepoMock, userRepoMock, secureServiceMock := newService()
+
+		user := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, PasswordHash: "random", MustChangePassword: true}
+		token := models.NewActivationToken(user.ID, payload.Token, time.Now().UTC(), time.Hour)
+		token.User = user
+
+		secureServiceMock.On("HashPassword", mock.Anything, payload.Password).
+			Return("$2y$10$hash", nil)
+
+		activationTokenRepoMock.On("GetActivationTokenByHash", mock.Anything, models.HashToken(payload.Token)).
+			Return(token, nil)
+
+		userRepoMock.On("UpdateUser", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+			return u.ID == user.ID && u.PasswordHash == "$2y$10$hash" && !u.MustChangePassword
+		})).Return(nil)
+
+		activationTokenRepoMock.On("UpdateActivationToken", mock.Anything, mock.MatchedBy(func(at models.ActivationToken) bool {
+			return at.ID == token.ID && at.UsedAt.Valid
+		})).Return(nil)
+
+		err := service.ActivateAccount(context.Background(), payload)
+
+		assert.NoError(t, err)
+		userRepoMock.AssertExpectations(t)
+		activationTokenRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenTokenNotFound_ShouldReturnErrActivationTokenInvalid", func(t *testing.T) {
+		service, activationTokenRepoMock, userRepoMock, secureServiceMock := newService()
+
+		secureServiceMock.On("HashPassword", mock.Anything, payload.Password).
+			Return("$2y$10$hash", nil)
+
+		activationTokenRepoMock.On("GetActivationTokenByHash", mock.Anything, models.HashToken(payload.Token)).
+			Return(nil, nil)
+
+		err := service.ActivateAccount(context.Background(), payload)
+
+		assert.ErrorIs(t, err, models.ErrActivationTokenInvalid)
+		userRepoMock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenTokenIsExpired_ShouldReturnErrActivationTokenExpired", func(t *testing.T) {
+		service, activationTokenRepoMock, userRepoMock, secureServiceMock := newService()
+
+		token := models.NewActivationToken(uuid.New(), payload.Token, time.Now().UTC().Add(-2*time.Hour), time.Hour)
+
+		secureServiceMock.On("HashPassword", mock.Anything, payload.Password).
+			Return("$2y$10$hash", nil)
+
+		activationTokenRepoMock.On("GetActivationTokenByHash", mock.Anything, models.HashToken(payload.Token)).
+			Return(token, nil)
+
+		err := service.ActivateAccount(context.Background(), payload)
+
+		assert.ErrorIs(t, err, models.ErrActivationTokenExpired)
+		userRepoMock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	newService := func() (*authService, *mocks.UserRepository, *mocks.SecureService, *mocks.SessionService) {
+		userRepoMock := new(mocks.UserRepository)
+		secureServiceMock := new(mocks.SecureService)
+		sessionServiceMock := new(mocks.SessionService)
+
+		return &authService{
+			ur:  userRepoMock,
+			ss:  secureServiceMock,
+			ses: sessionServiceMock,
+		}, userRepoMock, secureServiceMock, sessionServiceMock
+	}
+
+	userID := uuid.New()
+	ctx := request.WithUserID(context.Background(), userID)
+	payload := models.ChangePasswordPayload{
+		CurrentPassword: "Current123",
+		NewPassword:     "NewPassword123",
+	}
+
+	t.Run("WhenCurrentPasswordIsWrong_ShouldReturnErrInvalidPassword", func(t *testing.T) {
+		service, userRepoMock, secureServiceMock, _ := newService()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, PasswordHash: "hash"}, nil)
+
+		secureServiceMock.On("CheckPassword", mock.Anything, "hash", payload.CurrentPassword).
+			Return(errors.New("mismatch"))
+
+		response, err := service.ChangePassword(ctx, payload)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrInvalidPassword)
+		userRepoMock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenNewPasswordEqualsCurrent_ShouldReturnErrSamePassword", func(t *testing.T) {
+		service, userRepoMock, secureServiceMock, _ := newService()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, PasswordHash: "hash"}, nil)
+
+		secureServiceMock.On("CheckPassword", mock.Anything, "hash", payload.CurrentPassword).
+			Return(nil)
+
+		response, err := service.ChangePassword(ctx, models.ChangePasswordPayload{
+			CurrentPassword: payload.CurrentPassword,
+			NewPassword:     payload.CurrentPassword,
+		})
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrSamePassword)
+	})
+
+	t.Run("WhenPasswordChanges_ShouldRevokeSessionsAndReturnNewToken", func(t *testing.T) {
+		service, userRepoMock, secureServiceMock, sessionServiceMock := newService()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, PasswordHash: "hash", MustChangePassword: true}, nil)
+
+		secureServiceMock.On("CheckPassword", mock.Anything, "hash", payload.CurrentPassword).
+			Return(nil)
+
+		secureServiceMock.On("HashPassword", mock.Anything, payload.NewPassword).
+			Return("new-hash", nil)
+
+		userRepoMock.On("UpdateUser", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+			return u.PasswordHash == "new-hash" && !u.MustChangePassword && u.SessionsRevokedAt.Valid
+		})).Return(nil)
+
+		sessionServiceMock.On("RevokeAllSessions", mock.Anything, userID).
+			Return(nil)
+
+		sessionServiceMock.On("CreateSession", mock.Anything, userID).
+			Return(&models.LoginResponse{Token: "new-token"}, nil)
+
+		response, err := service.ChangePassword(ctx, payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new-token", response.Token)
+		userRepoMock.AssertExpectations(t)
+		sessionServiceMock.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_ForgotPassword(t *testing.T) {
+	config.Env.PasswordReset = config.PasswordReset{URL: "http://localhost/reset-password", TokenExp: 30}
+
+	newService := func() (*authService, *mocks.UserRepository, *mocks.Cache, *mocks.EmailOutboxRepository) {
+		userRepoMock := new(mocks.UserRepository)
+		secureServiceMock := new(mocks.SecureService)
+		cacheMock := new(mocks.Cache)
+		emailOutboxRepoMock := new(mocks.EmailOutboxRepository)
+
+		secureServiceMock.On("CreateToken", mock.Anything).
+			Return("reset-token", nil)
+
+		return &authService{
+			ur:  userRepoMock,
+			ss:  secureServiceMock,
+			c:   cacheMock,
+			eor: emailOutboxRepoMock,
+			ef:  email.NewEmailFactory(),
+		}, userRepoMock, cacheMock, emailOutboxRepoMock
+	}
+
+	t.Run("WhenUserNotFound_ShouldSucceedWithoutSendingEmail", func(t *testing.T) {
+		service, userRepoMock, cacheMock, emailOutboxRepoMock := newService()
+
+		userRepoMock.On("GetUserByEmail", mock.Anything, "ghost@example.com").
+			Return(nil, nil)
+
+		err := service.ForgotPassword(context.Background(), models.ForgotPasswordPayload{Login: "ghost@example.com"})
+
+		assert.NoError(t, err)
+		cacheMock.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		emailOutboxRepoMock.AssertNotCalled(t, "CreateEmailOutbox", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenUserExists_ShouldReplacePreviousTokenAndQueueEmail", func(t *testing.T) {
+		service, userRepoMock, cacheMock, emailOutboxRepoMock := newService()
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, FullName: "John Doe", Email: "john@example.com", Status: models.ActiveStatus}
+		userRepoMock.On("GetUserByCPF", mock.Anything, "12345678900").
+			Return(user, nil)
+
+		userKey := "password-reset:user:" + user.ID.String()
+		cacheMock.On("Get", mock.Anything, userKey, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*string) = "previous-hash"
+			}).
+			Return(nil)
+
+		cacheMock.On("Delete", mock.Anything, "password-reset:previous-hash").
+			Return(nil)
+
+		tokenHash := models.HashToken("reset-token")
+		cacheMock.On("Set", mock.Anything, "password-reset:"+tokenHash, models.PasswordReset{UserID: user.ID}, 30*time.Minute).
+			Return(nil)
+
+		cacheMock.On("Set", mock.Anything, userKey, tokenHash, 30*time.Minute).
+			Return(nil)
+
+		emailOutboxRepoMock.On("CreateEmailOutbox", mock.Anything, mock.Anything).
+			Return(nil)
+
+		err := service.ForgotPassword(context.Background(), models.ForgotPasswordPayload{Login: "123.456.789-00"})
+
+		assert.NoError(t, err)
+		cacheMock.AssertExpectations(t)
+		emailOutboxRepoMock.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	newService := func() (*authService, *mocks.UserRepository, *mocks.Cache, *mocks.SessionService) {
+		userRepoMock := new(mocks.UserRepository)
+		secureServiceMock := new(mocks.SecureService)
+		cacheMock := new(mocks.Cache)
+		sessionServiceMock := new(mocks.SessionService)
+
+		secureServiceMock.On("HashPassword", mock.Anything, "NewPassword123").
+			Return("new-hash", nil)
+
+		return &authService{
+			ur:  userRepoMock,
+			ss:  secureServiceMock,
+			c:   cacheMock,
+			ses: sessionServiceMock,
+		}, userRepoMock, cacheMock, sessionServiceMock
+	}
+
+	payload := models.ResetPasswordPayload{
+		Token:    "reset-token",
+		Password: "NewPassword123",
+	}
+
+	t.Run("WhenTokenIsUnknown_ShouldReturnErrResetTokenInvalid", func(t *testing.T) {
+		service, userRepoMock, cacheMock, _ := newService()
+
+		cacheMock.On("GetDel", mock.Anything, "password-reset:"+models.HashToken(payload.Token), mock.Anything).
+			Return(storage.ErrCacheMiss)
+
+		err := service.ResetPassword(context.Background(), payload)
+
+		assert.ErrorIs(t, err, models.ErrResetTokenInvalid)
+		userRepoMock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenTokenIsValid_ShouldSetPasswordAndRevokeSessions", func(t *testing.T) {
+		service, userRepoMock, cacheMock, sessionServiceMock := newService()
+
+		userID := uuid.New()
+		cacheMock.On("GetDel", mock.Anything, "password-reset:"+models.HashToken(payload.Token), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.PasswordReset) = models.PasswordReset{UserID: userID}
+			}).
+			Return(nil)
+
+		cacheMock.On("Delete", mock.Anything, "password-reset:user:"+userID.String()).
+			Return(nil)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, PasswordHash: "old-hash"}, nil)
+
+		userRepoMock.On("UpdateUser", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+			return u.PasswordHash == "new-hash" && u.SessionsRevokedAt.Valid
+		})).Return(nil)
+
+		sessionServiceMock.On("RevokeAllSessions", mock.Anything, userID).
+			Return(nil)
+
+		err := service.ResetPassword(context.Background(), payload)
+
+		assert.NoError(t, err)
+		cacheMock.AssertExpectations(t)
+		userRepoMock.AssertExpectations(t)
+		sessionServiceMock.AssertExpectations(t)
 	})
 }
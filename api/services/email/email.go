@@ -2,22 +2,26 @@ package email
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/G-Villarinho/fast-feet-api/templates"
-	"gopkg.in/gomail.v2"
 )
 
+//go:generate mockery --name=EmailService --filename=email_service.go --output=../../mocks --outpkg=mocks
 type EmailService interface {
-	SendEmail(ctx context.Context, payload models.SendEmailPayload)
+	SendEmail(ctx context.Context, payload models.SendEmailPayload) error
 }
 
 type emailService struct {
-	i *di.Injector
-	t templates.Template
+	i   *di.Injector
+	t   templates.Template
+	et  EmailTransport
+	now func() time.Time
 }
 
 func NewEmailService(i *di.Injector) (EmailService, error) {
@@ -26,36 +30,52 @@ func NewEmailService(i *di.Injector) (EmailService, error) {
 		return nil, err
 	}
 
+	et, err := di.Invoke[EmailTransport](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &emailService{
-		i: i,
-		t: t,
+		i:   i,
+		t:   t,
+		et:  et,
+		now: time.Now,
 	}, nil
 }
 
-func (e *emailService) SendEmail(ctx context.Context, payload models.SendEmailPayload) {
+func (e *emailService) SendEmail(ctx context.Context, payload models.SendEmailPayload) error {
 	log := slog.With(
 		slog.String("service", "email"),
 		slog.String("func", "SendEmail"),
 	)
 
-	content, err := e.t.RenderTemplate(payload.TemplateName, payload.Params)
+	content, err := e.t.RenderTemplate(payload.Params)
 	if err != nil {
-		log.Error("render %s.html email template: %w", string(payload.TemplateName), err)
-		return
+		return fmt.Errorf("render email template: %w", err)
 	}
 
-	dialer := gomail.NewDialer(config.Env.SMTP.Host, config.Env.SMTP.Port, config.Env.SMTP.User, config.Env.SMTP.Password)
-
-	msg := gomail.NewMessage()
-	msg.SetHeader("From", config.Env.SMTP.User)
-	msg.SetHeader("To", payload.To)
-	msg.SetHeader("Subject", payload.Subject)
-	msg.SetBody("text/html", content)
+	msg := models.EmailMessage{
+		From:    sender(),
+		To:      payload.To,
+		Subject: payload.Subject,
+		Text:    content.Text,
+		HTML:    content.HTML,
+		SentAt:  e.now(),
+	}
 
-	if err := dialer.DialAndSend(msg); err != nil {
-		log.Error(err.Error())
-		return
+	if err := e.et.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send email to %q: %w", payload.To, err)
 	}
 
 	log.Info("email sent succefully")
+
+	return nil
+}
+
+func sender() string {
+	if config.Env.Mail.From != "" {
+		return config.Env.Mail.From
+	}
+
+	return config.Env.SMTP.User
 }
@@ -0,0 +1,64 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailService_SendEmail(t *testing.T) {
+	config.Env.Mail.From = "noreply@fastfeet.com"
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	params := templates.PickUpParams{RecipientName: "John", TrackingCode: "abc-123"}
+	payload := models.SendEmailPayload{To: "john@example.com", Subject: "Sua encomenda", Params: params}
+
+	t.Run("ShouldRenderTemplateAndSendThroughTransport", func(t *testing.T) {
+		templateMock := new(mocks.Template)
+		transport := NewMemoryTransport()
+		service := &emailService{t: templateMock, et: transport, now: func() time.Time { return now }}
+
+		templateMock.On("RenderTemplate", params).
+			Return(&templates.RenderedTemplate{HTML: "<p>Olá John</p>", Text: "Olá John"}, nil)
+
+		err := service.SendEmail(context.Background(), payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.EmailMessage{{
+			From:    "noreply@fastfeet.com",
+			To:      "john@example.com",
+			Subject: "Sua encomenda",
+			Text:    "Olá John",
+			HTML:    "<p>Olá John</p>",
+			SentAt:  now,
+		}}, transport.Messages())
+	})
+
+	t.Run("WhenTransportFails_ShouldReturnError", func(t *testing.T) {
+		templateMock := new(mocks.Template)
+		transportMock := new(mocks.EmailTransport)
+		service := &emailService{t: templateMock, et: transportMock, now: func() time.Time { return now }}
+
+		templateMock.On("RenderTemplate", params).
+			Return(&templates.RenderedTemplate{HTML: "<p>Olá John</p>", Text: "Olá John"}, nil)
+		transportMock.On("Send", context.Background(), models.EmailMessage{
+			From:    "noreply@fastfeet.com",
+			To:      "john@example.com",
+			Subject: "Sua encomenda",
+			Text:    "Olá John",
+			HTML:    "<p>Olá John</p>",
+			SentAt:  now,
+		}).Return(errors.New("smtp down"))
+
+		err := service.SendEmail(context.Background(), payload)
+
+		assert.ErrorContains(t, err, "smtp down")
+		transportMock.AssertExpectations(t)
+	})
+}
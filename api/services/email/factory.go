@@ -1,6 +1,7 @@
 package email
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
@@ -14,15 +15,99 @@ func NewEmailFactory() *EmailFactory {
 	return &EmailFactory{}
 }
 
+func (f *EmailFactory) CreateCreatedSendEmail(to, subject, recipientName, orderTitle, trackingCode string) models.SendEmailPayload {
+	return models.SendEmailPayload{
+		To:      to,
+		Subject: subject,
+		Params: templates.CreatedParams{
+			RecipientName: recipientName,
+			OrderTitle:    orderTitle,
+			TrackingCode:  trackingCode,
+		},
+	}
+}
+
 func (f *EmailFactory) CreatePickUpSendEmail(to, subject, recipientName, trackingCode string) models.SendEmailPayload {
 	return models.SendEmailPayload{
-		To:           to,
-		Subject:      subject,
-		TemplateName: templates.PickUpTemplate,
-		Params: map[string]string{
-			"recipient_name": recipientName,
-			"tracking_code":  trackingCode,
-			"current_year":   strconv.Itoa(time.Now().Year()),
+		To:      to,
+		Subject: subject,
+		Params: templates.PickUpParams{
+			RecipientName: recipientName,
+			TrackingCode:  trackingCode,
+		},
+	}
+}
+
+func (f *EmailFactory) CreateDeliveredSendEmail(to, subject, recipientName, trackingCode string, deliveredAt time.Time) models.SendEmailPayload {
+	return models.SendEmailPayload{
+		To:      to,
+		Subject: subject,
+		Params: templates.DeliveredParams{
+			RecipientName: recipientName,
+			TrackingCode:  trackingCode,
+			DeliveredAt:   deliveredAt.Format("02/01/2006 às 15:04"),
+		},
+	}
+}
+
+func (f *EmailFactory) CreateCancelSendEmail(to, subject, recipientName, trackingCode, reason string) models.SendEmailPayload {
+	return models.SendEmailPayload{
+		To:      to,
+		Subject: subject,
+		Params: templates.CancelParams{
+			RecipientName: recipientName,
+			TrackingCode:  trackingCode,
+			Reason:        reason,
+		},
+	}
+}
+
+func (f *EmailFactory) CreateReturnSendEmail(to, subject, recipientName, trackingCode, reason string) models.SendEmailPayload {
+	return models.SendEmailPayload{
+		To:      to,
+		Subject: subject,
+		Params: templates.ReturnParams{
+			RecipientName: recipientName,
+			TrackingCode:  trackingCode,
+			Reason:        reason,
+		},
+	}
+}
+
+func (f *EmailFactory) CreateWelcomeSendEmail(to, subject, fullName, role, activationURL string, expiresAt time.Time) models.SendEmailPayload {
+	return models.SendEmailPayload{
+		To:      to,
+		Subject: subject,
+		Params: templates.WelcomeParams{
+			FullName:      fullName,
+			Role:          role,
+			ActivationURL: activationURL,
+			ExpiresAt:     expiresAt.Format("02/01/2006 às 15:04"),
+		},
+	}
+}
+
+func (f *EmailFactory) CreatePasswordResetSendEmail(to, subject, fullName, resetURL string, expiresIn time.Duration) models.SendEmailPayload {
+	return models.SendEmailPayload{
+		To:      to,
+		Subject: subject,
+		Params: templates.PasswordResetParams{
+			FullName:  fullName,
+			ResetURL:  resetURL,
+			ExpiresIn: fmt.Sprintf("%d minutos", int(expiresIn.Minutes())),
+		},
+	}
+}
+
+func (f *EmailFactory) CreateAccountLockedSendEmail(to, subject, fullName, ip string, attempts int, lockedUntil time.Time) models.SendEmailPayload {
+	return models.SendEmailPayload{
+		To:      to,
+		Subject: subject,
+		Params: templates.AccountLockedParams{
+			FullName:    fullName,
+			Attempts:    strconv.Itoa(attempts),
+			IP:          ip,
+			LockedUntil: lockedUntil.Format("02/01/2006 às 15:04"),
 		},
 	}
 }
@@ -0,0 +1,174 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/repositories"
+	"github.com/google/uuid"
+)
+
+//go:generate mockery --name=OutboxWorker --filename=outbox_worker.go --output=../../mocks --outpkg=mocks
+type OutboxWorker interface {
+	Run(ctx context.Context)
+	ProcessBatch(ctx context.Context) (int, error)
+}
+
+type outboxWorker struct {
+	i   *di.Injector
+	es  EmailService
+	eor repositories.EmailOutboxRepository
+	tm  repositories.TransactionManager
+	now func() time.Time
+}
+
+func NewOutboxWorker(i *di.Injector) (OutboxWorker, error) {
+	es, err := di.Invoke[EmailService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email service: %w", err)
+	}
+
+	eor, err := di.Invoke[repositories.EmailOutboxRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email outbox repository: %w", err)
+	}
+
+	tm, err := di.Invoke[repositories.TransactionManager](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke transaction manager: %w", err)
+	}
+
+	return &outboxWorker{
+		i:   i,
+		es:  es,
+		eor: eor,
+		tm:  tm,
+		now: time.Now,
+	}, nil
+}
+
+// Run drains the outbox every interval until ctx is canceled.
+func (o *outboxWorker) Run(ctx context.Context) {
+	log := slog.With(
+		slog.String("worker", "outbox"),
+		slog.String("func", "Run"),
+	)
+
+	ticker := time.NewTicker(time.Duration(config.Env.Outbox.Interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processed, err := o.ProcessBatch(ctx)
+			if err != nil {
+				log.Error(err.Error())
+				continue
+			}
+
+			if processed > 0 {
+				log.Info("outbox batch processed", slog.Int("emails", processed))
+			}
+		}
+	}
+}
+
+// ProcessBatch sends the emails that are due. They are claimed in a short
+// transaction and sent after it commits, so no row lock or connection is held
+// while the SMTP server answers. Each result is saved on its own; if the worker
+// dies before saving, the lease runs out and the email is sent again: delivery
+// is at least once.
+func (o *outboxWorker) ProcessBatch(ctx context.Context) (int, error) {
+	var emails []models.EmailOutbox
+
+	now := o.now().UTC()
+	leaseUntil := now.Add(time.Duration(config.Env.Outbox.Lease) * time.Second)
+
+	if err := o.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		due, err := o.eor.GetDueEmailOutbox(ctx, now, config.Env.Outbox.BatchSize)
+		if err != nil {
+			return fmt.Errorf("get due emails: %w", err)
+		}
+
+		if len(due) == 0 {
+			return nil
+		}
+
+		IDs := make([]uuid.UUID, len(due))
+		for i := range due {
+			due[i].Claim(leaseUntil)
+			IDs[i] = due[i].ID
+		}
+
+		if err := o.eor.ClaimEmailOutbox(ctx, IDs, leaseUntil); err != nil {
+			return fmt.Errorf("claim due emails: %w", err)
+		}
+
+		emails = due
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	// Results are saved even when shutdown cancels ctx halfway through a send.
+	saveCtx := context.WithoutCancel(ctx)
+
+	var processed int
+
+	for _, email := range emails {
+		// Emails left unsent here are picked up again once their lease ends.
+		if ctx.Err() != nil {
+			break
+		}
+
+		o.deliver(ctx, &email, o.now().UTC())
+
+		if err := o.eor.UpdateEmailOutbox(saveCtx, email); err != nil {
+			slog.Error("save email delivery result",
+				slog.String("emailId", email.ID.String()),
+				slog.String("status", string(email.Status)),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (o *outboxWorker) deliver(ctx context.Context, email *models.EmailOutbox, now time.Time) {
+	payload, err := email.ToSendEmailPayload()
+	if err != nil {
+		// The stored params no longer match the template: retrying cannot help.
+		email.MarkDead(err)
+		return
+	}
+
+	if err := o.es.SendEmail(ctx, payload); err != nil {
+		email.MarkFailed(err, now,
+			config.Env.Outbox.MaxAttempts,
+			time.Duration(config.Env.Outbox.BackoffBase)*time.Second,
+			time.Duration(config.Env.Outbox.BackoffMax)*time.Second,
+		)
+
+		slog.Warn("email delivery failed",
+			slog.String("emailId", email.ID.String()),
+			slog.Int("attempts", email.Attempts),
+			slog.String("status", string(email.Status)),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	email.MarkSent(now)
+}
@@ -0,0 +1,137 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutboxWorker_ProcessBatch(t *testing.T) {
+	config.Env.Outbox = config.Outbox{BatchSize: 10, MaxAttempts: 3, BackoffBase: 30, BackoffMax: 3600, Lease: 300}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	leaseUntil := now.Add(300 * time.Second)
+
+	// inTransaction tells whether the claim transaction of the worker is open.
+	var inTransaction bool
+
+	newWorker := func() (*outboxWorker, *mocks.EmailService, *mocks.EmailOutboxRepository) {
+		emailServiceMock := new(mocks.EmailService)
+		emailOutboxRepoMock := new(mocks.EmailOutboxRepository)
+		transactionManagerMock := new(mocks.TransactionManager)
+
+		transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				inTransaction = true
+				defer func() { inTransaction = false }()
+				return fn(ctx)
+			})
+
+		emailOutboxRepoMock.On("ClaimEmailOutbox", mock.Anything, mock.Anything, leaseUntil).Return(nil)
+
+		return &outboxWorker{
+			es:  emailServiceMock,
+			eor: emailOutboxRepoMock,
+			tm:  transactionManagerMock,
+			now: func() time.Time { return now },
+		}, emailServiceMock, emailOutboxRepoMock
+	}
+
+	t.Run("WhenEmailIsSent_ShouldMarkItAsSent", func(t *testing.T) {
+		worker, emailServiceMock, emailOutboxRepoMock := newWorker()
+		email, _ := models.NewEmailOutbox(models.SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+
+		emailOutboxRepoMock.On("GetDueEmailOutbox", mock.Anything, now, 10).
+			Return([]models.EmailOutbox{*email}, nil)
+
+		emailServiceMock.On("SendEmail", mock.Anything, models.SendEmailPayload{
+			To:     "john@example.com",
+			Params: &templates.PickUpParams{RecipientName: "John"},
+		}).
+			Run(func(args mock.Arguments) {
+				assert.False(t, inTransaction, "email sent while the claim transaction was open")
+			}).
+			Return(nil)
+
+		emailOutboxRepoMock.On("UpdateEmailOutbox", mock.Anything, mock.MatchedBy(func(e models.EmailOutbox) bool {
+			return e.ID == email.ID && e.Status == models.EmailSent && e.SentAt.Valid && e.Attempts == 1
+		})).Return(nil)
+
+		processed, err := worker.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		emailOutboxRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenSendFails_ShouldScheduleRetry", func(t *testing.T) {
+		worker, emailServiceMock, emailOutboxRepoMock := newWorker()
+		email, _ := models.NewEmailOutbox(models.SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+
+		emailOutboxRepoMock.On("GetDueEmailOutbox", mock.Anything, now, 10).
+			Return([]models.EmailOutbox{*email}, nil)
+
+		emailServiceMock.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("smtp unavailable"))
+
+		emailOutboxRepoMock.On("UpdateEmailOutbox", mock.Anything, mock.MatchedBy(func(e models.EmailOutbox) bool {
+			return e.Status == models.EmailPending && e.Attempts == 1 && e.NextAttemptAt.Equal(now.Add(30*time.Second))
+		})).Return(nil)
+
+		processed, err := worker.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		emailOutboxRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenLastAttemptFails_ShouldMoveToDeadLetter", func(t *testing.T) {
+		worker, emailServiceMock, emailOutboxRepoMock := newWorker()
+		email, _ := models.NewEmailOutbox(models.SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+		email.Attempts = 2
+
+		emailOutboxRepoMock.On("GetDueEmailOutbox", mock.Anything, now, 10).
+			Return([]models.EmailOutbox{*email}, nil)
+
+		emailServiceMock.On("SendEmail", mock.Anything, mock.Anything).Return(errors.New("mailbox unavailable"))
+
+		emailOutboxRepoMock.On("UpdateEmailOutbox", mock.Anything, mock.MatchedBy(func(e models.EmailOutbox) bool {
+			return e.Status == models.EmailDead && e.Attempts == 3
+		})).Return(nil)
+
+		_, err := worker.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		emailOutboxRepoMock.AssertExpectations(t)
+	})
+	t.Run("WhenSavingOneResultFails_ShouldKeepTheOtherResults", func(t *testing.T) {
+		worker, emailServiceMock, emailOutboxRepoMock := newWorker()
+		first, _ := models.NewEmailOutbox(models.SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+		second, _ := models.NewEmailOutbox(models.SendEmailPayload{To: "mary@example.com", Params: templates.PickUpParams{RecipientName: "Mary"}})
+
+		emailOutboxRepoMock.On("GetDueEmailOutbox", mock.Anything, now, 10).
+			Return([]models.EmailOutbox{*first, *second}, nil)
+
+		emailServiceMock.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+
+		emailOutboxRepoMock.On("UpdateEmailOutbox", mock.Anything, mock.MatchedBy(func(e models.EmailOutbox) bool {
+			return e.ID == first.ID
+		})).Return(errors.New("connection reset"))
+		emailOutboxRepoMock.On("UpdateEmailOutbox", mock.Anything, mock.MatchedBy(func(e models.EmailOutbox) bool {
+			return e.ID == second.ID && e.Status == models.EmailSent
+		})).Return(nil)
+
+		processed, err := worker.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		emailServiceMock.AssertNumberOfCalls(t, "SendEmail", 2)
+		emailOutboxRepoMock.AssertExpectations(t)
+	})
+}
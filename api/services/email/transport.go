@@ -0,0 +1,56 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"gopkg.in/gomail.v2"
+)
+
+const (
+	SMTPDriver   = "smtp"
+	FileDriver   = "file"
+	MemoryDriver = "memory"
+)
+
+//go:generate mockery --name=EmailTransport --filename=email_transport.go --output=../../mocks --outpkg=mocks
+type EmailTransport interface {
+	Send(ctx context.Context, msg models.EmailMessage) error
+	Close() error
+}
+
+func NewEmailTransport(i *di.Injector) (EmailTransport, error) {
+	switch config.Env.Mail.Transport {
+	case SMTPDriver, "":
+		return NewSMTPTransport(SMTPOptions{
+			Host:        config.Env.SMTP.Host,
+			Port:        config.Env.SMTP.Port,
+			User:        config.Env.SMTP.User,
+			Password:    config.Env.SMTP.Password,
+			PoolSize:    config.Env.SMTP.PoolSize,
+			IdleTimeout: time.Duration(config.Env.SMTP.IdleTimeout) * time.Second,
+		}), nil
+	case FileDriver:
+		return NewFileTransport(config.Env.Mail.FilePath)
+	case MemoryDriver:
+		return NewMemoryTransport(), nil
+	default:
+		return nil, fmt.Errorf("unknown email transport %q", config.Env.Mail.Transport)
+	}
+}
+
+func toGomail(m models.EmailMessage) *gomail.Message {
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", m.From)
+	msg.SetHeader("To", m.To)
+	msg.SetHeader("Subject", m.Subject)
+	msg.SetDateHeader("Date", m.SentAt)
+	msg.SetBody("text/plain", m.Text)
+	msg.AddAlternative("text/html", m.HTML)
+
+	return msg
+}
@@ -0,0 +1,56 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/google/uuid"
+)
+
+// fileTransport writes every message as an .eml file, which any mail client
+// can open. Meant for local development.
+type fileTransport struct {
+	dir string
+}
+
+func NewFileTransport(dir string) (EmailTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create mail directory %q: %w", dir, err)
+	}
+
+	return &fileTransport{dir: dir}, nil
+}
+
+func (f *fileTransport) Send(ctx context.Context, msg models.EmailMessage) error {
+	name := fmt.Sprintf("%s-%s.eml", msg.SentAt.UTC().Format("20060102T150405"), uuid.NewString())
+	path := filepath.Join(f.dir, name)
+
+	// Write to a temp file first so readers never see a partial message.
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create mail file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := toGomail(msg).WriteTo(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write mail file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close mail file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename mail file: %w", err)
+	}
+
+	return nil
+}
+
+func (f *fileTransport) Close() error {
+	return nil
+}
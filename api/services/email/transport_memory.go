@@ -0,0 +1,62 @@
+package email
+
+import (
+	"context"
+	"sync"
+
+	"github.com/G-Villarinho/fast-feet-api/models"
+)
+
+// MemoryTransport keeps sent messages in memory so tests can assert on them
+// without a mail server.
+type MemoryTransport struct {
+	mu       sync.Mutex
+	messages []models.EmailMessage
+}
+
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+func (m *MemoryTransport) Send(ctx context.Context, msg models.EmailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *MemoryTransport) Close() error {
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far, oldest first.
+func (m *MemoryTransport) Messages() []models.EmailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]models.EmailMessage(nil), m.messages...)
+}
+
+// MessagesTo returns the messages sent to the given address.
+func (m *MemoryTransport) MessagesTo(to string) []models.EmailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var messages []models.EmailMessage
+	for _, msg := range m.messages {
+		if msg.To == to {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages
+}
+
+// Reset discards every recorded message.
+func (m *MemoryTransport) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = nil
+}
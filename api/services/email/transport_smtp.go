@@ -0,0 +1,143 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"gopkg.in/gomail.v2"
+)
+
+type SMTPOptions struct {
+	Host        string
+	Port        int
+	User        string
+	Password    string
+	PoolSize    int
+	IdleTimeout time.Duration
+}
+
+type smtpConn struct {
+	sc       gomail.SendCloser
+	lastUsed time.Time
+}
+
+// smtpTransport keeps up to PoolSize authenticated connections open and
+// reuses them across messages instead of dialing once per email.
+type smtpTransport struct {
+	dial        func() (gomail.SendCloser, error)
+	idleTimeout time.Duration
+	slots       chan struct{}
+	now         func() time.Time
+
+	mu     sync.Mutex
+	idle   []*smtpConn
+	closed bool
+}
+
+func NewSMTPTransport(opts SMTPOptions) EmailTransport {
+	dialer := gomail.NewDialer(opts.Host, opts.Port, opts.User, opts.Password)
+	return newSMTPTransport(dialer.Dial, opts.PoolSize, opts.IdleTimeout)
+}
+
+func newSMTPTransport(dial func() (gomail.SendCloser, error), poolSize int, idleTimeout time.Duration) *smtpTransport {
+	if poolSize < 1 {
+		poolSize = 1
+	}
+
+	return &smtpTransport{
+		dial:        dial,
+		idleTimeout: idleTimeout,
+		slots:       make(chan struct{}, poolSize),
+		now:         time.Now,
+	}
+}
+
+func (s *smtpTransport) Send(ctx context.Context, msg models.EmailMessage) error {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-s.slots }()
+
+	conn, reused, err := s.acquire()
+	if err != nil {
+		return err
+	}
+
+	err = gomail.Send(conn.sc, toGomail(msg))
+	if err != nil && reused {
+		// The server may have dropped an idle connection: redial once.
+		conn.sc.Close()
+		if conn, err = s.connect(); err != nil {
+			return err
+		}
+		err = gomail.Send(conn.sc, toGomail(msg))
+	}
+
+	if err != nil {
+		conn.sc.Close()
+		return fmt.Errorf("smtp send: %w", err)
+	}
+
+	s.release(conn)
+	return nil
+}
+
+func (s *smtpTransport) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for _, conn := range s.idle {
+		conn.sc.Close()
+	}
+	s.idle = nil
+
+	return nil
+}
+
+func (s *smtpTransport) acquire() (*smtpConn, bool, error) {
+	s.mu.Lock()
+	for len(s.idle) > 0 {
+		conn := s.idle[len(s.idle)-1]
+		s.idle = s.idle[:len(s.idle)-1]
+
+		if s.idleTimeout > 0 && s.now().Sub(conn.lastUsed) > s.idleTimeout {
+			conn.sc.Close()
+			continue
+		}
+
+		s.mu.Unlock()
+		return conn, true, nil
+	}
+	s.mu.Unlock()
+
+	conn, err := s.connect()
+	return conn, false, err
+}
+
+func (s *smtpTransport) connect() (*smtpConn, error) {
+	sc, err := s.dial()
+	if err != nil {
+		return nil, fmt.Errorf("smtp dial: %w", err)
+	}
+
+	return &smtpConn{sc: sc}, nil
+}
+
+func (s *smtpTransport) release(conn *smtpConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		conn.sc.Close()
+		return
+	}
+
+	conn.lastUsed = s.now()
+	s.idle = append(s.idle, conn)
+}
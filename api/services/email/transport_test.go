@@ -0,0 +1,148 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/gomail.v2"
+)
+
+type fakeSendCloser struct {
+	sent   int
+	fail   bool
+	closed bool
+}
+
+func (f *fakeSendCloser) Send(from string, to []string, msg io.WriterTo) error {
+	if f.fail {
+		return errors.New("connection reset")
+	}
+
+	f.sent++
+	return nil
+}
+
+func (f *fakeSendCloser) Close() error {
+	f.closed = true
+	return nil
+}
+
+func newTestMessage() models.EmailMessage {
+	return models.EmailMessage{
+		From:    "noreply@fastfeet.com",
+		To:      "john@example.com",
+		Subject: "Sua encomenda",
+		Text:    "Olá John",
+		HTML:    "<p>Olá John</p>",
+		SentAt:  time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSMTPTransport_Send(t *testing.T) {
+	t.Run("ShouldReuseIdleConnection", func(t *testing.T) {
+		var dialed []*fakeSendCloser
+		transport := newSMTPTransport(func() (gomail.SendCloser, error) {
+			sc := &fakeSendCloser{}
+			dialed = append(dialed, sc)
+			return sc, nil
+		}, 2, time.Minute)
+
+		assert.NoError(t, transport.Send(context.Background(), newTestMessage()))
+		assert.NoError(t, transport.Send(context.Background(), newTestMessage()))
+
+		assert.Len(t, dialed, 1)
+		assert.Equal(t, 2, dialed[0].sent)
+	})
+
+	t.Run("WhenIdleConnectionIsDropped_ShouldRedial", func(t *testing.T) {
+		var dialed []*fakeSendCloser
+		transport := newSMTPTransport(func() (gomail.SendCloser, error) {
+			sc := &fakeSendCloser{}
+			dialed = append(dialed, sc)
+			return sc, nil
+		}, 1, time.Minute)
+
+		assert.NoError(t, transport.Send(context.Background(), newTestMessage()))
+		dialed[0].fail = true
+
+		assert.NoError(t, transport.Send(context.Background(), newTestMessage()))
+
+		assert.Len(t, dialed, 2)
+		assert.True(t, dialed[0].closed)
+		assert.Equal(t, 1, dialed[1].sent)
+	})
+
+	t.Run("WhenConnectionExceedsIdleTimeout_ShouldCloseIt", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		var dialed []*fakeSendCloser
+		transport := newSMTPTransport(func() (gomail.SendCloser, error) {
+			sc := &fakeSendCloser{}
+			dialed = append(dialed, sc)
+			return sc, nil
+		}, 1, 30*time.Second)
+		transport.now = func() time.Time { return now }
+
+		assert.NoError(t, transport.Send(context.Background(), newTestMessage()))
+		now = now.Add(time.Minute)
+		assert.NoError(t, transport.Send(context.Background(), newTestMessage()))
+
+		assert.Len(t, dialed, 2)
+		assert.True(t, dialed[0].closed)
+	})
+
+	t.Run("WhenDialFails_ShouldReturnError", func(t *testing.T) {
+		transport := newSMTPTransport(func() (gomail.SendCloser, error) {
+			return nil, errors.New("connection refused")
+		}, 1, time.Minute)
+
+		err := transport.Send(context.Background(), newTestMessage())
+
+		assert.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestFileTransport_Send(t *testing.T) {
+	t.Run("ShouldWriteMessageAsEmlFile", func(t *testing.T) {
+		dir := t.TempDir()
+		transport, err := NewFileTransport(dir)
+		assert.NoError(t, err)
+
+		assert.NoError(t, transport.Send(context.Background(), newTestMessage()))
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+		assert.NoError(t, err)
+		assert.Len(t, files, 1)
+		assert.True(t, strings.HasPrefix(filepath.Base(files[0]), "20250101T120000-"))
+
+		content, err := os.ReadFile(files[0])
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "To: john@example.com")
+		assert.Contains(t, string(content), "Content-Type: text/html")
+	})
+}
+
+func TestMemoryTransport(t *testing.T) {
+	t.Run("ShouldRecordAndResetMessages", func(t *testing.T) {
+		transport := NewMemoryTransport()
+		other := newTestMessage()
+		other.To = "mary@example.com"
+
+		assert.NoError(t, transport.Send(context.Background(), newTestMessage()))
+		assert.NoError(t, transport.Send(context.Background(), other))
+
+		assert.Len(t, transport.Messages(), 2)
+		assert.Equal(t, []models.EmailMessage{other}, transport.MessagesTo("mary@example.com"))
+
+		transport.Reset()
+
+		assert.Empty(t, transport.Messages())
+	})
+}
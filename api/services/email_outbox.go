@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/repositories"
+	"github.com/google/uuid"
+)
+
+//go:generate mockery --name=EmailOutboxService --filename=email_outbox_service.go --output=../mocks --outpkg=mocks
+type EmailOutboxService interface {
+	GetEmails(ctx context.Context, pagination *models.EmailOutboxPagination) (*models.PaginatedResponse[*models.EmailOutboxResponse], error)
+	RetryEmail(ctx context.Context, emailID uuid.UUID) error
+}
+
+type emailOutboxService struct {
+	i   *di.Injector
+	eor repositories.EmailOutboxRepository
+	ur  repositories.UserRepository
+}
+
+func NewEmailOutboxService(i *di.Injector) (EmailOutboxService, error) {
+	eor, err := di.Invoke[repositories.EmailOutboxRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email outbox repository: %w", err)
+	}
+
+	ur, err := di.Invoke[repositories.UserRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke user repository: %w", err)
+	}
+
+	return &emailOutboxService{
+		i:   i,
+		eor: eor,
+		ur:  ur,
+	}, nil
+}
+
+func (e *emailOutboxService) GetEmails(ctx context.Context, pagination *models.EmailOutboxPagination) (*models.PaginatedResponse[*models.EmailOutboxResponse], error) {
+	if _, err := authorizeUser(ctx, e.ur, models.Read, models.Emails); err != nil {
+		return nil, err
+	}
+
+	paginatedEmails, err := e.eor.GetEmailOutboxPagedList(ctx, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("get paginated emails: %w", err)
+	}
+
+	return models.MapPaginatedResult(paginatedEmails, func(email models.EmailOutbox) *models.EmailOutboxResponse {
+		return email.ToEmailOutboxResponse()
+	}), nil
+}
+
+func (e *emailOutboxService) RetryEmail(ctx context.Context, emailID uuid.UUID) error {
+	if _, err := authorizeUser(ctx, e.ur, models.Update, models.Emails); err != nil {
+		return err
+	}
+
+	email, err := e.eor.GetEmailOutboxByID(ctx, emailID)
+	if err != nil {
+		return fmt.Errorf("get email by id %q: %w", emailID, err)
+	}
+
+	if email == nil {
+		return models.ErrEmailNotFound
+	}
+
+	if err := email.Retry(time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := e.eor.UpdateEmailOutbox(ctx, *email); err != nil {
+		return fmt.Errorf("update email %q: %w", emailID, err)
+	}
+
+	return nil
+}
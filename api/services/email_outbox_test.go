@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEmailOutboxService_RetryEmail(t *testing.T) {
+	t.Run("WhenUserIsDeliveryMan_ShouldReturnErrInsufficientPermission", func(t *testing.T) {
+		emailOutboxRepoMock := new(mocks.EmailOutboxRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := emailOutboxService{
+			eor: emailOutboxRepoMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.DeliveryMan}, nil)
+
+		err := service.RetryEmail(request.WithUserID(context.Background(), userID), uuid.New())
+
+		assert.ErrorIs(t, err, models.ErrInsufficientPermission)
+		emailOutboxRepoMock.AssertNotCalled(t, "GetEmailOutboxByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenUserWasDeleted_ShouldReturnErrUserNotFound", func(t *testing.T) {
+		emailOutboxRepoMock := new(mocks.EmailOutboxRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := emailOutboxService{
+			eor: emailOutboxRepoMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).Return(nil, nil)
+
+		err := service.RetryEmail(request.WithUserID(context.Background(), userID), uuid.New())
+
+		assert.ErrorIs(t, err, models.ErrUserNotFound)
+		emailOutboxRepoMock.AssertNotCalled(t, "GetEmailOutboxByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenEmailNotFound_ShouldReturnErrEmailNotFound", func(t *testing.T) {
+		emailOutboxRepoMock := new(mocks.EmailOutboxRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := emailOutboxService{
+			eor: emailOutboxRepoMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		emailID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		emailOutboxRepoMock.On("GetEmailOutboxByID", mock.Anything, emailID).Return(nil, nil)
+
+		err := service.RetryEmail(request.WithUserID(context.Background(), userID), emailID)
+
+		assert.ErrorIs(t, err, models.ErrEmailNotFound)
+	})
+
+	t.Run("WhenEmailIsDead_ShouldRequeueIt", func(t *testing.T) {
+		emailOutboxRepoMock := new(mocks.EmailOutboxRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := emailOutboxService{
+			eor: emailOutboxRepoMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		email, _ := models.NewEmailOutbox(models.SendEmailPayload{To: "john@example.com", Params: templates.PickUpParams{RecipientName: "John"}})
+		email.Status = models.EmailDead
+		email.Attempts = 8
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		emailOutboxRepoMock.On("GetEmailOutboxByID", mock.Anything, email.ID).Return(email, nil)
+
+		emailOutboxRepoMock.On("UpdateEmailOutbox", mock.Anything, mock.MatchedBy(func(e models.EmailOutbox) bool {
+			return e.Status == models.EmailPending && e.Attempts == 0
+		})).Return(nil)
+
+		err := service.RetryEmail(request.WithUserID(context.Background(), userID), email.ID)
+
+		assert.NoError(t, err)
+		emailOutboxRepoMock.AssertExpectations(t)
+	})
+}
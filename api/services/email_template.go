@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/repositories"
+	"github.com/G-Villarinho/fast-feet-api/services/email"
+	"github.com/G-Villarinho/fast-feet-api/templates"
+)
+
+//go:generate mockery --name=EmailTemplateService --filename=email_template_service.go --output=../mocks --outpkg=mocks
+type EmailTemplateService interface {
+	PreviewTemplate(ctx context.Context, name templates.TemplateName, params map[string]string) (*templates.RenderedTemplate, error)
+	SendTestEmail(ctx context.Context, name templates.TemplateName, params map[string]string) error
+}
+
+type emailTemplateService struct {
+	i  *di.Injector
+	es email.EmailService
+	t  templates.Template
+	ur repositories.UserRepository
+}
+
+func NewEmailTemplateService(i *di.Injector) (EmailTemplateService, error) {
+	es, err := di.Invoke[email.EmailService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email service: %w", err)
+	}
+
+	t, err := di.Invoke[templates.Template](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke template: %w", err)
+	}
+
+	ur, err := di.Invoke[repositories.UserRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke user repository: %w", err)
+	}
+
+	return &emailTemplateService{
+		i:  i,
+		es: es,
+		t:  t,
+		ur: ur,
+	}, nil
+}
+
+func (e *emailTemplateService) PreviewTemplate(ctx context.Context, name templates.TemplateName, params map[string]string) (*templates.RenderedTemplate, error) {
+	if _, err := authorizeUser(ctx, e.ur, models.Read, models.Emails); err != nil {
+		return nil, err
+	}
+
+	templateParams, err := templates.SampleParams(name, params)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := e.t.RenderTemplate(templateParams)
+	if err != nil {
+		return nil, fmt.Errorf("render template %q: %w", name, err)
+	}
+
+	return content, nil
+}
+
+func (e *emailTemplateService) SendTestEmail(ctx context.Context, name templates.TemplateName, params map[string]string) error {
+	user, err := authorizeUser(ctx, e.ur, models.Create, models.Emails)
+	if err != nil {
+		return err
+	}
+
+	templateParams, err := templates.SampleParams(name, params)
+	if err != nil {
+		return err
+	}
+
+	payload := models.SendEmailPayload{
+		To:      user.Email,
+		Subject: fmt.Sprintf("[Teste] %s", name),
+		Params:  templateParams,
+	}
+
+	if err := e.es.SendEmail(ctx, payload); err != nil {
+		return fmt.Errorf("send test email %q: %w", name, err)
+	}
+
+	return nil
+}
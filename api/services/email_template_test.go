@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEmailTemplateService_PreviewTemplate(t *testing.T) {
+	t.Run("WhenUserIsDeliveryMan_ShouldReturnErrInsufficientPermission", func(t *testing.T) {
+		templateMock := new(mocks.Template)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := emailTemplateService{
+			t:  templateMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.DeliveryMan}, nil)
+
+		content, err := service.PreviewTemplate(request.WithUserID(context.Background(), userID), templates.PickUpTemplate, nil)
+
+		assert.Nil(t, content)
+		assert.ErrorIs(t, err, models.ErrInsufficientPermission)
+		templateMock.AssertNotCalled(t, "RenderTemplate", mock.Anything)
+	})
+
+	t.Run("ShouldRenderSampleWithSuppliedParams", func(t *testing.T) {
+		templateMock := new(mocks.Template)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := emailTemplateService{
+			t:  templateMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		rendered := &templates.RenderedTemplate{HTML: "<h2>Olá Ana,</h2>", Text: "Olá Ana,"}
+		templateMock.On("RenderTemplate", &templates.PickUpParams{RecipientName: "Ana", TrackingCode: "FF-7Q2K9X"}).
+			Return(rendered, nil)
+
+		content, err := service.PreviewTemplate(request.WithUserID(context.Background(), userID), templates.PickUpTemplate, map[string]string{"recipientName": "Ana"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, rendered, content)
+	})
+}
+
+func TestEmailTemplateService_SendTestEmail(t *testing.T) {
+	t.Run("ShouldSendToCallerAddress", func(t *testing.T) {
+		emailServiceMock := new(mocks.EmailService)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := emailTemplateService{
+			es: emailServiceMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin, Email: "admin@fastfeet.com"}, nil)
+
+		emailServiceMock.On("SendEmail", mock.Anything, mock.MatchedBy(func(payload models.SendEmailPayload) bool {
+			return payload.To == "admin@fastfeet.com" && payload.Params.TemplateName() == templates.CancelTemplate
+		})).Return(nil)
+
+		err := service.SendTestEmail(request.WithUserID(context.Background(), userID), templates.CancelTemplate, nil)
+
+		assert.NoError(t, err)
+		emailServiceMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenParamIsUnknown_ShouldReturnErrInvalidParam", func(t *testing.T) {
+		emailServiceMock := new(mocks.EmailService)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := emailTemplateService{
+			es: emailServiceMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin, Email: "admin@fastfeet.com"}, nil)
+
+		err := service.SendTestEmail(request.WithUserID(context.Background(), userID), templates.CancelTemplate, map[string]string{"orderTitle": "x"})
+
+		assert.ErrorIs(t, err, templates.ErrInvalidParam)
+		emailServiceMock.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything)
+	})
+}
@@ -1,19 +1,29 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
 	"mime/multipart"
+	"net/http"
 
-	_ "image/jpeg"
-	_ "image/png"
-
+	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/utils"
 )
 
+const defaultMaxImagePixels = 40_000_000
+
+//go:generate mockery --name=FileService --filename=file_service.go --output=../mocks --outpkg=mocks
 type FileService interface {
-	ValidateImage(ctx context.Context, imageFile *multipart.FileHeader) error
+	ProcessImage(ctx context.Context, imageFile *multipart.FileHeader) (*models.ProcessedImage, error)
 }
 
 type fileService struct {
@@ -26,25 +36,102 @@ func NewFileService(i *di.Injector) (FileService, error) {
 	}, nil
 }
 
-func (f *fileService) ValidateImage(ctx context.Context, imageFile *multipart.FileHeader) error {
+// ProcessImage validates an uploaded image and normalizes it before storage:
+// the content type is sniffed from the bytes, the EXIF orientation is applied
+// and the image is re-encoded, which drops every metadata segment. The hash is
+// computed over the normalized content so the same photo is recognized even if
+// its metadata was edited.
+func (f *fileService) ProcessImage(ctx context.Context, imageFile *multipart.FileHeader) (*models.ProcessedImage, error) {
 	if imageFile.Size > models.MaxImageSize {
-		return models.ErrImageTooLarge
-	}
-
-	if !models.AllowedImageTypes[imageFile.Header.Get("Content-Type")] {
-		return models.ErrInvalidImageFormat
+		return nil, models.ErrImageTooLarge
 	}
 
 	file, err := imageFile.Open()
 	if err != nil {
-		return err
+		return nil, models.ErrOpenImage
 	}
 	defer file.Close()
 
-	_, _, err = image.Decode(file)
+	data, err := io.ReadAll(io.LimitReader(file, models.MaxImageSize+1))
 	if err != nil {
-		return models.ErrImageCorrupted
+		return nil, models.ErrOpenImage
+	}
+
+	if len(data) > models.MaxImageSize {
+		return nil, models.ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if !models.AllowedImageTypes[contentType] {
+		return nil, models.ErrInvalidImageFormat
+	}
+
+	// A few kilobytes can declare billions of pixels, so the header is checked
+	// before anything is allocated for them.
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, models.ErrImageCorrupted
+	}
+
+	maxPixels := config.Env.Image.MaxPixels
+	if maxPixels <= 0 {
+		maxPixels = defaultMaxImagePixels
+	}
+
+	if int64(imageConfig.Width)*int64(imageConfig.Height) > int64(maxPixels) {
+		return nil, models.ErrImageTooManyPixels
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, models.ErrImageCorrupted
+	}
+
+	oriented := utils.Orient(decoded, utils.JPEGOrientation(data))
+	resized := utils.Fit(oriented, config.Env.Image.MaxDimension)
+	thumbnail := utils.Fit(resized, config.Env.Image.ThumbnailDimension)
+
+	content, err := f.encode(resized, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+
+	thumbnailContent, err := f.encode(thumbnail, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("encode thumbnail: %w", err)
+	}
+
+	hash := sha256.Sum256(content)
+
+	return &models.ProcessedImage{
+		Content:     content,
+		Thumbnail:   thumbnailContent,
+		ContentType: contentType,
+		Extension:   models.ImageExtensions[contentType],
+		Hash:        hex.EncodeToString(hash[:]),
+		Width:       resized.Bounds().Dx(),
+		Height:      resized.Bounds().Dy(),
+	}, nil
+}
+
+func (f *fileService) encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch contentType {
+	case "image/png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	default:
+		quality := config.Env.Image.JPEGQuality
+		if quality <= 0 {
+			quality = jpeg.DefaultQuality
+		}
+
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return buf.Bytes(), nil
 }
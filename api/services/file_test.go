@@ -0,0 +1,161 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newImageFileHeader(t *testing.T, contentType string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreatePart(map[string][]string{
+		"Content-Disposition": {`form-data; name="image"; filename="proof"`},
+		"Content-Type":        {contentType},
+	})
+	assert.NoError(t, err)
+
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	assert.NoError(t, req.ParseMultipartForm(1<<20))
+
+	return req.MultipartForm.File["image"][0]
+}
+
+func newTestImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	return img
+}
+
+// withOrientation inserts an EXIF APP1 segment carrying the given orientation
+// right after the JPEG SOI marker.
+func withOrientation(jpegData []byte, orientation byte) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+
+	app1 := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, segment...)
+
+	return append(append([]byte{0xFF, 0xD8}, app1...), jpegData[2:]...)
+}
+
+// pngHeader returns a PNG made of just a signature and an IHDR chunk that
+// declares the given dimensions, as a decompression bomb would.
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	ihdr[12] = 8 // bit depth
+	ihdr[13] = 6 // RGBA
+
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, 13)
+	data = append(data, ihdr...)
+
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestFileService_ProcessImage(t *testing.T) {
+	config.Env.Image = config.Image{MaxDimension: 64, ThumbnailDimension: 16, JPEGQuality: 85, MaxPixels: 100_000}
+	service := &fileService{}
+
+	t.Run("WhenJPEGHasOrientation_ShouldRotateAndStripMetadata", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, newTestImage(40, 20), nil))
+		data := withOrientation(buf.Bytes(), 6)
+
+		processed, err := service.ProcessImage(context.Background(), newImageFileHeader(t, "image/jpeg", data))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/jpeg", processed.ContentType)
+		assert.Equal(t, 20, processed.Width)
+		assert.Equal(t, 40, processed.Height)
+		assert.False(t, bytes.Contains(processed.Content, []byte("Exif")))
+		assert.Len(t, processed.Hash, 64)
+	})
+
+	t.Run("WhenImageIsLarge_ShouldDownscaleAndCreateThumbnail", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, newTestImage(200, 100)))
+
+		processed, err := service.ProcessImage(context.Background(), newImageFileHeader(t, "image/png", buf.Bytes()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 64, processed.Width)
+		assert.Equal(t, 32, processed.Height)
+
+		thumbnail, err := png.Decode(bytes.NewReader(processed.Thumbnail))
+		assert.NoError(t, err)
+		assert.Equal(t, 16, thumbnail.Bounds().Dx())
+		assert.Equal(t, 8, thumbnail.Bounds().Dy())
+	})
+
+	t.Run("WhenHeaderDisagreesWithContent_ShouldTrustContent", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, newTestImage(10, 10)))
+
+		processed, err := service.ProcessImage(context.Background(), newImageFileHeader(t, "image/jpeg", buf.Bytes()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", processed.ContentType)
+		assert.Equal(t, ".png", processed.Extension)
+	})
+
+	t.Run("WhenContentIsNotAnImage_ShouldReturnErrInvalidImageFormat", func(t *testing.T) {
+		processed, err := service.ProcessImage(context.Background(), newImageFileHeader(t, "image/png", []byte("<html>not an image</html>")))
+
+		assert.Nil(t, processed)
+		assert.ErrorIs(t, err, models.ErrInvalidImageFormat)
+	})
+
+	t.Run("WhenSamePhotoIsProcessedTwice_ShouldProduceSameHash", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, newTestImage(30, 30), nil))
+
+		first, err := service.ProcessImage(context.Background(), newImageFileHeader(t, "image/jpeg", buf.Bytes()))
+		assert.NoError(t, err)
+
+		second, err := service.ProcessImage(context.Background(), newImageFileHeader(t, "image/jpeg", withOrientation(buf.Bytes(), 1)))
+		assert.NoError(t, err)
+
+		assert.Equal(t, first.Hash, second.Hash)
+	})
+	t.Run("WhenHeaderDeclaresTooManyPixels_ShouldRejectBeforeDecoding", func(t *testing.T) {
+		processed, err := service.ProcessImage(context.Background(), newImageFileHeader(t, "image/png", pngHeader(30000, 30000)))
+
+		assert.Nil(t, processed)
+		assert.ErrorIs(t, err, models.ErrImageTooManyPixels)
+	})
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrUnknownSigningKey = errors.New("token was signed with an unknown key")
+
+const minRSAKeyBits = 2048
+
+// KeySet holds the keys that sign and verify session tokens. Every key
+// verifies tokens carrying its kid; only the signing key creates new ones.
+type KeySet struct {
+	signing *signingKey
+	keys    map[string]*signingKey
+}
+
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// LoadKeySet reads every <kid>.pem file of dir. A file may hold a private key
+// (PKCS #8, or PKCS #1 for RSA) or just a public key, for keys that are kept
+// only to verify tokens issued before a rotation. Supported keys are RSA,
+// signing with RS256, and Ed25519, signing with EdDSA.
+//
+// Rotating keys without logging anyone out:
+//  1. Add the new key file and deploy. It is published in the JWKS but does
+//     not sign yet, so other services pick it up ahead of time.
+//  2. Point JWT_SIGNING_KEY_ID at the new kid and deploy. Tokens signed with
+//     the old key keep being accepted.
+//  3. Once the longest access token lifetime has passed, remove the old file.
+func LoadKeySet(dir, signingKeyID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("list keys in %q: %w", dir, err)
+	}
+
+	keySet := &KeySet{keys: make(map[string]*signingKey, len(paths))}
+
+	for _, path := range paths {
+		key, err := loadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		keySet.keys[key.id] = key
+	}
+
+	signing, found := keySet.keys[signingKeyID]
+	if !found {
+		return nil, fmt.Errorf("signing key %q not found in %q", signingKeyID, dir)
+	}
+
+	if signing.private == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKeyID)
+	}
+
+	keySet.signing = signing
+
+	return keySet, nil
+}
+
+// Sign signs the claims with the signing key and sets its kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	token.Header["kid"] = k.signing.id
+
+	return token.SignedString(k.signing.private)
+}
+
+// Parse verifies a token with the key named by its kid header. The algorithm
+// must be the one of that key, so a public key can never be used as an HMAC
+// secret.
+func (k *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		key, found := k.keys[kid]
+		if !found {
+			return nil, ErrUnknownSigningKey
+		}
+
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), kid)
+		}
+
+		return key.public, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}), jwt.WithExpirationRequired())
+}
+
+// JWKS returns the public keys in JSON Web Key format, ordered by kid.
+func (k *KeySet) JWKS() *models.JWKS {
+	jwks := &models.JWKS{Keys: make([]models.JWK, 0, len(k.keys))}
+
+	for _, key := range k.keys {
+		jwks.Keys = append(jwks.Keys, key.jwk())
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].KeyID < jwks.Keys[j].KeyID })
+
+	return jwks
+}
+
+func (s *signingKey) jwk() models.JWK {
+	jwk := models.JWK{
+		KeyID:     s.id,
+		Use:       "sig",
+		Algorithm: s.method.Alg(),
+	}
+
+	switch public := s.public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return jwk
+}
+
+func loadSigningKey(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key %q: %w", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %q is not PEM encoded", path)
+	}
+
+	key := &signingKey{id: strings.TrimSuffix(filepath.Base(path), ".pem")}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse private key %q: %w", path, err)
+		}
+
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("key %q cannot sign", path)
+		}
+
+		key.private = signer
+		key.public = signer.Public()
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse private key %q: %w", path, err)
+		}
+
+		key.private = parsed
+		key.public = parsed.Public()
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse public key %q: %w", path, err)
+		}
+
+		key.public = parsed
+	default:
+		return nil, fmt.Errorf("key %q has unsupported PEM type %q", path, block.Type)
+	}
+
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key %q must have at least %d bits", path, minRSAKeyBits)
+		}
+
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("key %q must be RSA or Ed25519", path)
+	}
+
+	return key, nil
+}
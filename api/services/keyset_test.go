@@ -0,0 +1,157 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePEM(t *testing.T, dir, kid, blockType string, der []byte) {
+	err := os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	assert.NoError(t, err)
+}
+
+func writeEd25519Key(t *testing.T, dir, kid string) ed25519.PublicKey {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	assert.NoError(t, err)
+
+	writePEM(t, dir, kid, "PRIVATE KEY", der)
+
+	return public
+}
+
+func newTestClaims() models.TokenClaims {
+	return models.TokenClaims{
+		UserID: uuid.New(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	writeEd25519Key(t, dir, "2025-01")
+
+	oldKeys, err := LoadKeySet(dir, "2025-01")
+	assert.NoError(t, err)
+
+	oldToken, err := oldKeys.Sign(newTestClaims())
+	assert.NoError(t, err)
+
+	t.Run("WhenSigningKeyChanges_ShouldStillVerifyTokensOfPreviousKey", func(t *testing.T) {
+		writeEd25519Key(t, dir, "2025-02")
+
+		newKeys, err := LoadKeySet(dir, "2025-02")
+		assert.NoError(t, err)
+
+		newToken, err := newKeys.Sign(newTestClaims())
+		assert.NoError(t, err)
+
+		parsed, err := newKeys.Parse(oldToken, &models.TokenClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "2025-01", parsed.Header["kid"])
+
+		parsed, err = newKeys.Parse(newToken, &models.TokenClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "2025-02", parsed.Header["kid"])
+		assert.Equal(t, "EdDSA", parsed.Method.Alg())
+	})
+
+	t.Run("WhenKeyWasRemoved_ShouldRejectItsTokens", func(t *testing.T) {
+		otherDir := t.TempDir()
+		writeEd25519Key(t, otherDir, "2025-02")
+
+		keys, err := LoadKeySet(otherDir, "2025-02")
+		assert.NoError(t, err)
+
+		_, err = keys.Parse(oldToken, &models.TokenClaims{})
+
+		assert.ErrorIs(t, err, ErrUnknownSigningKey)
+	})
+}
+
+func TestLoadKeySet(t *testing.T) {
+	t.Run("WhenSigningKeyIsOnlyPublic_ShouldReturnError", func(t *testing.T) {
+		dir := t.TempDir()
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+
+		der, err := x509.MarshalPKIXPublicKey(public)
+		assert.NoError(t, err)
+		writePEM(t, dir, "retired", "PUBLIC KEY", der)
+
+		_, err = LoadKeySet(dir, "retired")
+
+		assert.ErrorContains(t, err, "has no private key")
+	})
+
+	t.Run("WhenSigningKeyIsMissing_ShouldReturnError", func(t *testing.T) {
+		_, err := LoadKeySet(t.TempDir(), "2025-01")
+
+		assert.ErrorContains(t, err, `signing key "2025-01" not found`)
+	})
+
+	t.Run("WhenRSAKeyIsTooShort_ShouldReturnError", func(t *testing.T) {
+		dir := t.TempDir()
+		private, err := rsa.GenerateKey(rand.Reader, 1024)
+		assert.NoError(t, err)
+
+		writePEM(t, dir, "weak", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private))
+
+		_, err = LoadKeySet(dir, "weak")
+
+		assert.ErrorContains(t, err, "at least 2048 bits")
+	})
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	dir := t.TempDir()
+	edPublic := writeEd25519Key(t, dir, "ed-key")
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	writePEM(t, dir, "rsa-key", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaPrivate))
+
+	keys, err := LoadKeySet(dir, "rsa-key")
+	assert.NoError(t, err)
+
+	jwks := keys.JWKS()
+
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, models.JWK{
+		KeyType:   "OKP",
+		KeyID:     "ed-key",
+		Use:       "sig",
+		Algorithm: "EdDSA",
+		Curve:     "Ed25519",
+		X:         base64.RawURLEncoding.EncodeToString(edPublic),
+	}, jwks.Keys[0])
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "RS256", jwks.Keys[1].Algorithm)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+
+	token, err := keys.Sign(newTestClaims())
+	assert.NoError(t, err)
+
+	parsed, err := keys.Parse(token, &models.TokenClaims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/storage"
+)
+
+//go:generate mockery --name=LoginGuard --filename=login_guard.go --output=../mocks --outpkg=mocks
+type LoginGuard interface {
+	CheckIP(ctx context.Context, ip string) error
+	CheckCPF(ctx context.Context, cpf string) error
+	RegisterFailure(ctx context.Context, cpf string, ip string) (int, error)
+	Reset(ctx context.Context, cpf string) error
+}
+
+type loginGuard struct {
+	i     *di.Injector
+	ac    storage.AttemptCounter
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewLoginGuard(i *di.Injector) (LoginGuard, error) {
+	ac, err := di.Invoke[storage.AttemptCounter](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke attempt counter: %w", err)
+	}
+
+	return &loginGuard{
+		i:     i,
+		ac:    ac,
+		sleep: sleepContext,
+	}, nil
+}
+
+// CheckIP rejects an address that failed too many logins, whatever CPF it
+// tried, so guessing across many accounts is throttled too.
+func (l *loginGuard) CheckIP(ctx context.Context, ip string) error {
+	if ip == "" {
+		return nil
+	}
+
+	failures, err := l.ac.Count(ctx, ipAttemptsKey(ip))
+	if err != nil {
+		return fmt.Errorf("count login failures of %q: %w", ip, err)
+	}
+
+	if failures >= int64(config.Env.Login.MaxAttemptsPerIP) {
+		return models.ErrTooManyLoginAttempts
+	}
+
+	return nil
+}
+
+// CheckCPF rejects a user that failed too many times in the current window.
+// It guards code checks made with a session, where the account is not locked
+// as it is on login.
+func (l *loginGuard) CheckCPF(ctx context.Context, cpf string) error {
+	failures, err := l.ac.Count(ctx, cpfAttemptsKey(cpf))
+	if err != nil {
+		return fmt.Errorf("count login failures of CPF: %w", err)
+	}
+
+	if failures >= int64(config.Env.Login.MaxAttempts) {
+		return models.ErrTooManyCodeAttempts
+	}
+
+	return nil
+}
+
+// RegisterFailure counts a failed login for the CPF and the address, then
+// waits a delay that doubles with each failure of the CPF. It returns the
+// failures of the CPF in the current window.
+func (l *loginGuard) RegisterFailure(ctx context.Context, cpf string, ip string) (int, error) {
+	window := time.Duration(config.Env.Login.AttemptWindow) * time.Minute
+
+	failures, err := l.ac.Increment(ctx, cpfAttemptsKey(cpf), window)
+	if err != nil {
+		return 0, fmt.Errorf("count login failure of CPF: %w", err)
+	}
+
+	if ip != "" {
+		if _, err := l.ac.Increment(ctx, ipAttemptsKey(ip), window); err != nil {
+			return 0, fmt.Errorf("count login failure of %q: %w", ip, err)
+		}
+	}
+
+	if err := l.sleep(ctx, loginDelay(int(failures))); err != nil {
+		return 0, err
+	}
+
+	return int(failures), nil
+}
+
+func (l *loginGuard) Reset(ctx context.Context, cpf string) error {
+	if err := l.ac.Reset(ctx, cpfAttemptsKey(cpf)); err != nil {
+		return fmt.Errorf("reset login failures of CPF: %w", err)
+	}
+
+	return nil
+}
+
+func loginDelay(failures int) time.Duration {
+	base := time.Duration(config.Env.Login.BaseDelay) * time.Millisecond
+	ceiling := time.Duration(config.Env.Login.MaxDelay) * time.Millisecond
+
+	delay := base
+	for i := 1; i < failures && delay < ceiling; i++ {
+		delay *= 2
+	}
+
+	return min(delay, ceiling)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func cpfAttemptsKey(cpf string) string {
+	return fmt.Sprintf("login-attempts:cpf:%s", cpf)
+}
+
+func ipAttemptsKey(ip string) string {
+	return fmt.Sprintf("login-attempts:ip:%s", ip)
+}
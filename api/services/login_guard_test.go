@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginGuard_CheckIP(t *testing.T) {
+	config.Env.Login = config.Login{MaxAttemptsPerIP: 20}
+
+	t.Run("WhenAddressReachedLimit_ShouldReturnErrTooManyLoginAttempts", func(t *testing.T) {
+		attemptCounterMock := new(mocks.AttemptCounter)
+		guard := &loginGuard{ac: attemptCounterMock}
+
+		attemptCounterMock.On("Count", mock.Anything, "login-attempts:ip:203.0.113.42").
+			Return(int64(20), nil)
+
+		err := guard.CheckIP(context.Background(), "203.0.113.42")
+
+		assert.ErrorIs(t, err, models.ErrTooManyLoginAttempts)
+	})
+
+	t.Run("WhenAddressIsBelowLimit_ShouldReturnNil", func(t *testing.T) {
+		attemptCounterMock := new(mocks.AttemptCounter)
+		guard := &loginGuard{ac: attemptCounterMock}
+
+		attemptCounterMock.On("Count", mock.Anything, "login-attempts:ip:203.0.113.42").
+			Return(int64(19), nil)
+
+		err := guard.CheckIP(context.Background(), "203.0.113.42")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestLoginGuard_CheckCPF(t *testing.T) {
+	config.Env.Login = config.Login{MaxAttempts: 5}
+
+	t.Run("WhenUserReachedLimit_ShouldReturnErrTooManyCodeAttempts", func(t *testing.T) {
+		attemptCounterMock := new(mocks.AttemptCounter)
+		guard := &loginGuard{ac: attemptCounterMock}
+
+		attemptCounterMock.On("Count", mock.Anything, "login-attempts:cpf:12345678900").
+			Return(int64(5), nil)
+
+		err := guard.CheckCPF(context.Background(), "12345678900")
+
+		assert.ErrorIs(t, err, models.ErrTooManyCodeAttempts)
+	})
+
+	t.Run("WhenUserIsBelowLimit_ShouldReturnNil", func(t *testing.T) {
+		attemptCounterMock := new(mocks.AttemptCounter)
+		guard := &loginGuard{ac: attemptCounterMock}
+
+		attemptCounterMock.On("Count", mock.Anything, "login-attempts:cpf:12345678900").
+			Return(int64(4), nil)
+
+		err := guard.CheckCPF(context.Background(), "12345678900")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestLoginGuard_RegisterFailure(t *testing.T) {
+	config.Env.Login = config.Login{AttemptWindow: 15, BaseDelay: 250, MaxDelay: 4000}
+
+	t.Run("WhenFailuresGrow_ShouldDoubleDelayUpToMax", func(t *testing.T) {
+		attemptCounterMock := new(mocks.AttemptCounter)
+
+		var delays []time.Duration
+		guard := &loginGuard{
+			ac: attemptCounterMock,
+			sleep: func(ctx context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			},
+		}
+
+		for _, failures := range []int64{1, 2, 3, 6} {
+			attemptCounterMock.On("Increment", mock.Anything, "login-attempts:cpf:12345678900", 15*time.Minute).
+				Return(failures, nil).Once()
+			attemptCounterMock.On("Increment", mock.Anything, "login-attempts:ip:203.0.113.42", 15*time.Minute).
+				Return(int64(1), nil).Once()
+
+			count, err := guard.RegisterFailure(context.Background(), "12345678900", "203.0.113.42")
+
+			assert.NoError(t, err)
+			assert.Equal(t, int(failures), count)
+		}
+
+		assert.Equal(t, []time.Duration{
+			250 * time.Millisecond,
+			500 * time.Millisecond,
+			time.Second,
+			4 * time.Second,
+		}, delays)
+		attemptCounterMock.AssertExpectations(t)
+	})
+}
@@ -1,48 +1,74 @@
 package services
 
 import (
+	"bytes"
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/G-Villarinho/fast-feet-api/repositories"
 	"github.com/G-Villarinho/fast-feet-api/request"
 	"github.com/G-Villarinho/fast-feet-api/services/email"
+	"github.com/G-Villarinho/fast-feet-api/storage"
+	"github.com/G-Villarinho/fast-feet-api/utils"
 	"github.com/google/uuid"
 )
 
+//go:generate mockery --name=OrderService --filename=order_service.go --output=../mocks --outpkg=mocks
 type OrderService interface {
 	CreateOrder(ctx context.Context, payload models.CreateOrderPayload) (*models.CreateOrderResponse, error)
-	PickUpOrder(ctx context.Context, orderID uuid.UUID) (*models.PickUpOrderResponse, error)
+	PickUpOrder(ctx context.Context, orderID uuid.UUID, payload models.PickUpOrderPayload) (*models.PickUpOrderResponse, error)
 	DeliverOrder(ctx context.Context, orderID uuid.UUID, payload models.DeliverOrderPayload) error
-	GetOrders(ctx context.Context, paginations *models.Pagination) (*models.PaginatedResponse[*models.OrderResponse], error)
+	FailDelivery(ctx context.Context, orderID uuid.UUID, payload models.FailDeliveryPayload) (*models.FailDeliveryResponse, error)
+	CancelOrder(ctx context.Context, orderID uuid.UUID, payload models.CancelOrderPayload) error
+	ReturnOrder(ctx context.Context, orderID uuid.UUID, payload models.ReturnOrderPayload) error
+	GetOrders(ctx context.Context, pagination *models.OrderPagination) (*models.PaginatedResponse[*models.OrderResponse], error)
 	GetOrder(ctx context.Context, orderID uuid.UUID) (*models.OrderDetailsResponse, error)
+	GetOrderEvents(ctx context.Context, orderID uuid.UUID) ([]*models.OrderEventResponse, error)
+	GetOrderProof(ctx context.Context, orderID uuid.UUID) (*models.OrderProof, error)
+	TrackOrder(ctx context.Context, trackingCode uuid.UUID) (*models.TrackingResponse, error)
 }
 
 type orderService struct {
-	i  *di.Injector
-	ef *email.EmailFactory
-	es email.EmailService
-	fs FileService
-	or repositories.OrderRepository
-	rr repositories.RecipientRepository
-	ur repositories.UserRepository
+	i   *di.Injector
+	ef  *email.EmailFactory
+	fs  FileService
+	dar repositories.DeliveryAttemptRepository
+	eor repositories.EmailOutboxRepository
+	oer repositories.OrderEventRepository
+	or  repositories.OrderRepository
+	ost storage.ObjectStorage
+	rr  repositories.RecipientRepository
+	tm  repositories.TransactionManager
+	ur  repositories.UserRepository
 }
 
 func NewOrderService(i *di.Injector) (OrderService, error) {
 	ef := email.NewEmailFactory()
 
-	es, err := di.Invoke[email.EmailService](i)
+	fs, err := di.Invoke[FileService](i)
 	if err != nil {
-		return nil, fmt.Errorf("invoke email service: %w", err)
+		return nil, fmt.Errorf("invoke file service: %w", err)
 	}
 
-	fs, err := di.Invoke[FileService](i)
+	dar, err := di.Invoke[repositories.DeliveryAttemptRepository](i)
 	if err != nil {
-		return nil, fmt.Errorf("invoke file service: %w", err)
+		return nil, fmt.Errorf("invoke delivery attempt repository: %w", err)
+	}
+
+	eor, err := di.Invoke[repositories.EmailOutboxRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email outbox repository: %w", err)
+	}
+
+	oer, err := di.Invoke[repositories.OrderEventRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke order event repository: %w", err)
 	}
 
 	or, err := di.Invoke[repositories.OrderRepository](i)
@@ -50,24 +76,38 @@ func NewOrderService(i *di.Injector) (OrderService, error) {
 		return nil, fmt.Errorf("invoke order repository: %w", err)
 	}
 
+	ost, err := di.Invoke[storage.ObjectStorage](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke object storage: %w", err)
+	}
+
 	rr, err := di.Invoke[repositories.RecipientRepository](i)
 	if err != nil {
 		return nil, fmt.Errorf("invoke recipient repository: %w", err)
 	}
 
+	tm, err := di.Invoke[repositories.TransactionManager](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke transaction manager: %w", err)
+	}
+
 	ur, err := di.Invoke[repositories.UserRepository](i)
 	if err != nil {
 		return nil, fmt.Errorf("invoke user repository: %w", err)
 	}
 
 	return &orderService{
-		i:  i,
-		ef: ef,
-		es: es,
-		fs: fs,
-		or: or,
-		rr: rr,
-		ur: ur,
+		i:   i,
+		ef:  ef,
+		fs:  fs,
+		dar: dar,
+		eor: eor,
+		oer: oer,
+		or:  or,
+		ost: ost,
+		rr:  rr,
+		tm:  tm,
+		ur:  ur,
 	}, nil
 }
 
@@ -96,9 +136,21 @@ func (o *orderService) CreateOrder(ctx context.Context, payload models.CreateOrd
 	}
 
 	order := payload.ToOrder()
+	order.Recipient = *recipient
+	event := models.NewOrderEvent(order.ID, nil, order.Status, &userID, models.OrderEventMetadata{})
+
+	if err := o.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := o.or.CreateOrder(ctx, *order); err != nil {
+			return fmt.Errorf("create order: %w", err)
+		}
 
-	if err := o.or.CreateOrder(ctx, *order); err != nil {
-		return nil, fmt.Errorf("create order: %w", err)
+		if err := o.oer.CreateOrderEvent(ctx, *event); err != nil {
+			return fmt.Errorf("create order %q event: %w", order.ID, err)
+		}
+
+		return o.notifyRecipient(ctx, *order)
+	}); err != nil {
+		return nil, err
 	}
 
 	return &models.CreateOrderResponse{
@@ -106,19 +158,118 @@ func (o *orderService) CreateOrder(ctx context.Context, payload models.CreateOrd
 	}, nil
 }
 
-func (o *orderService) PickUpOrder(ctx context.Context, orderID uuid.UUID) (*models.PickUpOrderResponse, error) {
+func (o *orderService) PickUpOrder(ctx context.Context, orderID uuid.UUID, payload models.PickUpOrderPayload) (*models.PickUpOrderResponse, error) {
 	userID, found := request.UserID(ctx)
 	if !found {
 		return nil, models.ErrUserNotFoundInContext
 	}
 
+	var order *models.Order
+	if err := o.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		// Locking the delivery man keeps them from being deactivated while
+		// the order is assigned to them.
+		user, err := o.ur.GetUserByIDForUpdate(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get user by id %q for update: %w", userID, err)
+		}
+
+		if user == nil {
+			return models.ErrUserNotFound
+		}
+
+		order, err = o.or.GetOrderByID(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("get order by id %q: %w", orderID, err)
+		}
+
+		if order == nil {
+			return models.ErrOrderNotFound
+		}
+
+		event, err := order.TransitionTo(models.PicknUp, user, payload.OrderEventMetadata)
+		if err != nil {
+			return err
+		}
+
+		return o.updateOrderWithEvent(ctx, *order, *event)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &models.PickUpOrderResponse{
+		PicknUpAt: order.PicknUpAt.Time,
+	}, nil
+}
+
+func (o *orderService) DeliverOrder(ctx context.Context, orderID uuid.UUID, payload models.DeliverOrderPayload) error {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return models.ErrUserNotFoundInContext
+	}
+
 	user, err := o.ur.GetUserByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("get user by id %q: %w", userID, err)
+		return fmt.Errorf("get user by id %q: %w", userID, err)
 	}
 
-	if models.Cannot(user.Role, models.UpdateStatus, models.Orders) {
-		return nil, models.ErrInsufficientPermission
+	order, err := o.or.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("get order by id %q: %w", orderID, err)
+	}
+
+	if order == nil {
+		return models.ErrOrderNotFound
+	}
+
+	if err := order.CanTransitionTo(models.Done, user); err != nil {
+		return err
+	}
+
+	image, err := o.fs.ProcessImage(ctx, payload.OrderImage)
+	if err != nil {
+		return err
+	}
+
+	usedBy, err := o.or.GetOrderByProofImageHash(ctx, image.Hash)
+	if err != nil {
+		return fmt.Errorf("get order by proof image hash: %w", err)
+	}
+
+	if usedBy != nil && usedBy.ID != order.ID {
+		return models.ErrImageAlreadyUsed
+	}
+
+	event, err := order.TransitionTo(models.Done, user, payload.OrderEventMetadata)
+	if err != nil {
+		return err
+	}
+
+	proofKey, thumbKey, err := o.storeImage(ctx, fmt.Sprintf("orders/%s/proof", order.ID), image)
+	if err != nil {
+		return err
+	}
+
+	order.ProofImageKey = &proofKey
+	order.ProofThumbKey = &thumbKey
+	order.ProofImageHash = &image.Hash
+
+	if err := o.updateOrderWithEvent(ctx, *order, *event); err != nil {
+		o.deleteObjects(ctx, proofKey, thumbKey)
+		return err
+	}
+
+	return nil
+}
+
+func (o *orderService) FailDelivery(ctx context.Context, orderID uuid.UUID, payload models.FailDeliveryPayload) (*models.FailDeliveryResponse, error) {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return nil, models.ErrUserNotFoundInContext
+	}
+
+	user, err := o.ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", userID, err)
 	}
 
 	order, err := o.or.GetOrderByID(ctx, orderID)
@@ -130,29 +281,80 @@ func (o *orderService) PickUpOrder(ctx context.Context, orderID uuid.UUID) (*mod
 		return nil, models.ErrOrderNotFound
 	}
 
-	if order.Status != models.Waiting {
-		return nil, models.ErrCannotTransitionToDelivered
+	if err := order.CanTransitionTo(models.DeliveryFailed, user); err != nil {
+		return nil, err
 	}
 
-	order.DeliverymanID = &userID
-	order.PicknUpAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
-	order.Status = models.PicknUp
+	var photo *models.ProcessedImage
+	if payload.Photo != nil {
+		photo, err = o.fs.ProcessImage(ctx, payload.Photo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attempt := payload.ToDeliveryAttempt(order.ID, userID)
+	attempts := len(order.DeliveryAttempts) + 1
 
-	if err := o.or.UpdateOrder(ctx, *order); err != nil {
-		return nil, fmt.Errorf("update order %q status: %w", orderID, err)
+	if photo != nil {
+		photoKey, thumbKey, err := o.storeImage(ctx, fmt.Sprintf("orders/%s/attempts", order.ID), photo)
+		if err != nil {
+			return nil, err
+		}
+
+		attempt.PhotoKey = &photoKey
+		attempt.ThumbKey = &thumbKey
 	}
 
-	go func() {
-		sendEmailPayload := o.ef.CreatePickUpSendEmail(order.Recipient.Email, "Pedido em rota de entrega", order.Recipient.FullName, order.TrackingCode.String())
-		o.es.SendEmail(ctx, sendEmailPayload)
-	}()
+	failedEvent, err := order.TransitionTo(models.DeliveryFailed, user, payload.OrderEventMetadata)
+	if err != nil {
+		return nil, err
+	}
 
-	return &models.PickUpOrderResponse{
-		PicknUpAt: order.PicknUpAt.Time,
+	events := []*models.OrderEvent{failedEvent}
+
+	if attempts >= config.Env.Order.MaxDeliveryAttempts {
+		note := fmt.Sprintf("Limite de %d tentativas de entrega atingido.", config.Env.Order.MaxDeliveryAttempts)
+
+		returningEvent, err := order.TransitionTo(models.Returning, user, models.OrderEventMetadata{Note: &note})
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, returningEvent)
+	}
+
+	if err := o.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := o.or.UpdateOrder(ctx, *order); err != nil {
+			return fmt.Errorf("update order %q status: %w", order.ID, err)
+		}
+
+		if err := o.dar.CreateDeliveryAttempt(ctx, *attempt); err != nil {
+			return fmt.Errorf("create order %q delivery attempt: %w", order.ID, err)
+		}
+
+		for _, event := range events {
+			if err := o.oer.CreateOrderEvent(ctx, *event); err != nil {
+				return fmt.Errorf("create order %q event: %w", order.ID, err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		if attempt.PhotoKey != nil {
+			o.deleteObjects(ctx, *attempt.PhotoKey, *attempt.ThumbKey)
+		}
+
+		return nil, err
+	}
+
+	return &models.FailDeliveryResponse{
+		Status:           order.Status,
+		DeliveryAttempts: attempts,
 	}, nil
 }
 
-func (o *orderService) DeliverOrder(ctx context.Context, orderID uuid.UUID, payload models.DeliverOrderPayload) error {
+func (o *orderService) CancelOrder(ctx context.Context, orderID uuid.UUID, payload models.CancelOrderPayload) error {
 	userID, found := request.UserID(ctx)
 	if !found {
 		return models.ErrUserNotFoundInContext
@@ -163,14 +365,38 @@ func (o *orderService) DeliverOrder(ctx context.Context, orderID uuid.UUID, payl
 		return fmt.Errorf("get user by id %q: %w", userID, err)
 	}
 
-	if models.Cannot(user.Role, models.UpdateStatus, models.Orders) {
-		return models.ErrInsufficientPermission
+	order, err := o.or.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("get order by id %q: %w", orderID, err)
 	}
 
-	if err := o.fs.ValidateImage(ctx, payload.OrderImage); err != nil {
+	if order == nil {
+		return models.ErrOrderNotFound
+	}
+
+	event, err := order.TransitionTo(models.Canceled, user, models.OrderEventMetadata{Note: &payload.Reason})
+	if err != nil {
+		return err
+	}
+
+	if err := o.updateOrderWithEvent(ctx, *order, *event); err != nil {
 		return err
 	}
 
+	return nil
+}
+
+func (o *orderService) ReturnOrder(ctx context.Context, orderID uuid.UUID, payload models.ReturnOrderPayload) error {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return models.ErrUserNotFoundInContext
+	}
+
+	user, err := o.ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user by id %q: %w", userID, err)
+	}
+
 	order, err := o.or.GetOrderByID(ctx, orderID)
 	if err != nil {
 		return fmt.Errorf("get order by id %q: %w", orderID, err)
@@ -180,24 +406,22 @@ func (o *orderService) DeliverOrder(ctx context.Context, orderID uuid.UUID, payl
 		return models.ErrOrderNotFound
 	}
 
-	if order.Status != models.PicknUp {
-		return models.ErrCannotTransitionToDelivered
-	}
+	metadata := payload.OrderEventMetadata
+	metadata.Note = &payload.Reason
 
-	order.DeliveryAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
-	order.Status = models.Done
-
-	// TODO: Notification recipient
-	// TODO: Persist imagem in cloudflare or anothe image store client
+	event, err := order.TransitionTo(models.Returned, user, metadata)
+	if err != nil {
+		return err
+	}
 
-	if err := o.or.UpdateOrder(ctx, *order); err != nil {
-		return fmt.Errorf("update order %q status: %w", orderID, err)
+	if err := o.updateOrderWithEvent(ctx, *order, *event); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func (o *orderService) GetOrders(ctx context.Context, pagination *models.Pagination) (*models.PaginatedResponse[*models.OrderResponse], error) {
+func (o *orderService) GetOrders(ctx context.Context, pagination *models.OrderPagination) (*models.PaginatedResponse[*models.OrderResponse], error) {
 	userID, found := request.UserID(ctx)
 	if !found {
 		return nil, models.ErrUserNotFoundInContext
@@ -259,3 +483,199 @@ func (o *orderService) GetOrder(ctx context.Context, orderID uuid.UUID) (*models
 
 	return order.ToOrderDetailsResponse(), nil
 }
+
+func (o *orderService) GetOrderEvents(ctx context.Context, orderID uuid.UUID) ([]*models.OrderEventResponse, error) {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return nil, models.ErrUserNotFoundInContext
+	}
+
+	user, err := o.ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", userID, err)
+	}
+
+	if models.Cannot(user.Role, models.Read, models.Deliveries) {
+		return nil, models.ErrInsufficientPermission
+	}
+
+	order, err := o.or.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("get order by id %q: %w", orderID, err)
+	}
+
+	if order == nil {
+		return nil, models.ErrOrderNotFound
+	}
+
+	if user.Role == models.DeliveryMan && (order.DeliverymanID == nil || *order.DeliverymanID != userID) {
+		return nil, models.ErrNotAssignedToOrder
+	}
+
+	events, err := o.oer.GetOrderEventsByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("get order %q events: %w", orderID, err)
+	}
+
+	response := make([]*models.OrderEventResponse, len(events))
+	for i, event := range events {
+		response[i] = event.ToOrderEventResponse()
+	}
+
+	return response, nil
+}
+
+func (o *orderService) GetOrderProof(ctx context.Context, orderID uuid.UUID) (*models.OrderProof, error) {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return nil, models.ErrUserNotFoundInContext
+	}
+
+	user, err := o.ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", userID, err)
+	}
+
+	if models.Cannot(user.Role, models.Read, models.Deliveries) {
+		return nil, models.ErrInsufficientPermission
+	}
+
+	order, err := o.or.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("get order by id %q: %w", orderID, err)
+	}
+
+	if order == nil {
+		return nil, models.ErrOrderNotFound
+	}
+
+	if user.Role == models.DeliveryMan && (order.DeliverymanID == nil || *order.DeliverymanID != userID) {
+		return nil, models.ErrNotAssignedToOrder
+	}
+
+	if order.ProofImageKey == nil {
+		return nil, models.ErrProofNotFound
+	}
+
+	key := *order.ProofImageKey
+
+	url, err := o.ost.SignedURL(ctx, key, time.Duration(config.Env.Storage.SignedURLExp)*time.Second)
+	if err == nil {
+		return &models.OrderProof{URL: url}, nil
+	}
+
+	if !errors.Is(err, storage.ErrSignedURLNotSupported) {
+		return nil, fmt.Errorf("sign url for object %q: %w", key, err)
+	}
+
+	object, err := o.ost.GetObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, models.ErrProofNotFound
+		}
+
+		return nil, fmt.Errorf("get object %q: %w", key, err)
+	}
+
+	return &models.OrderProof{
+		Content:     object.Body,
+		ContentType: object.ContentType,
+		Size:        object.Size,
+	}, nil
+}
+
+func (o *orderService) TrackOrder(ctx context.Context, trackingCode uuid.UUID) (*models.TrackingResponse, error) {
+	order, err := o.or.GetOrderByTrackingCode(ctx, trackingCode)
+	if err != nil {
+		return nil, fmt.Errorf("get order by tracking code %q: %w", trackingCode, err)
+	}
+
+	if order == nil {
+		return nil, models.ErrOrderNotFound
+	}
+
+	return order.ToTrackingResponse(), nil
+}
+
+// notifyRecipient queues an email telling the recipient about the status the
+// order has just reached. It must run in the same transaction as the order
+// update so the email is sent if and only if the change is committed.
+// Statuses without a recipient-facing message are ignored.
+func (o *orderService) notifyRecipient(ctx context.Context, order models.Order) error {
+	to := order.Recipient.Email
+	name := order.Recipient.FullName
+	trackingCode := order.TrackingCode.String()
+
+	var sendEmailPayload models.SendEmailPayload
+
+	switch order.Status {
+	case models.Waiting:
+		sendEmailPayload = o.ef.CreateCreatedSendEmail(to, "Sua encomenda foi registrada", name, order.Title, trackingCode)
+	case models.PicknUp:
+		sendEmailPayload = o.ef.CreatePickUpSendEmail(to, "Pedido em rota de entrega", name, trackingCode)
+	case models.Done:
+		sendEmailPayload = o.ef.CreateDeliveredSendEmail(to, "Encomenda entregue", name, trackingCode, order.DeliveryAt.Time)
+	case models.Canceled:
+		sendEmailPayload = o.ef.CreateCancelSendEmail(to, "Entrega cancelada", name, trackingCode, utils.DerefString(order.CancelReason))
+	case models.Returned:
+		sendEmailPayload = o.ef.CreateReturnSendEmail(to, "Encomenda devolvida ao remetente", name, trackingCode, utils.DerefString(order.ReturnReason))
+	default:
+		return nil
+	}
+
+	email, err := models.NewEmailOutbox(sendEmailPayload)
+	if err != nil {
+		return err
+	}
+
+	if err := o.eor.CreateEmailOutbox(ctx, *email); err != nil {
+		return fmt.Errorf("queue order %q notification: %w", order.ID, err)
+	}
+
+	return nil
+}
+
+// updateOrderWithEvent saves the order, its status event and the recipient
+// notification in a single transaction.
+func (o *orderService) updateOrderWithEvent(ctx context.Context, order models.Order, event models.OrderEvent) error {
+	return o.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := o.or.UpdateOrder(ctx, order); err != nil {
+			return fmt.Errorf("update order %q status: %w", order.ID, err)
+		}
+
+		if err := o.oer.CreateOrderEvent(ctx, event); err != nil {
+			return fmt.Errorf("create order %q event: %w", order.ID, err)
+		}
+
+		return o.notifyRecipient(ctx, order)
+	})
+}
+
+// storeImage uploads a processed image and its thumbnail under prefix,
+// returning both object keys.
+func (o *orderService) storeImage(ctx context.Context, prefix string, image *models.ProcessedImage) (string, string, error) {
+	name := uuid.NewString()
+	key := fmt.Sprintf("%s/%s%s", prefix, name, image.Extension)
+	thumbKey := fmt.Sprintf("%s/%s_thumb%s", prefix, name, image.Extension)
+
+	if err := o.ost.PutObject(ctx, key, bytes.NewReader(image.Content), int64(len(image.Content)), image.ContentType); err != nil {
+		return "", "", fmt.Errorf("put object %q: %w", key, err)
+	}
+
+	if err := o.ost.PutObject(ctx, thumbKey, bytes.NewReader(image.Thumbnail), int64(len(image.Thumbnail)), image.ContentType); err != nil {
+		o.deleteObjects(ctx, key)
+		return "", "", fmt.Errorf("put object %q: %w", thumbKey, err)
+	}
+
+	return key, thumbKey, nil
+}
+
+// deleteObjects removes objects whose owning record could not be saved.
+// Failures are only logged, since the original error is what matters.
+func (o *orderService) deleteObjects(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := o.ost.DeleteObject(ctx, key); err != nil {
+			slog.Error("delete orphan object", slog.String("key", key), slog.String("error", err.Error()))
+		}
+	}
+}
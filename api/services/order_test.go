@@ -0,0 +1,774 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/services/email"
+	"github.com/G-Villarinho/fast-feet-api/storage"
+	"github.com/G-Villarinho/fast-feet-api/templates"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOrderService_TrackOrder(t *testing.T) {
+	t.Run("WhenOrderNotFound_ShouldReturnErrOrderNotFound", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+
+		service := orderService{
+			or: orderRepoMock,
+		}
+
+		trackingCode := uuid.New()
+
+		orderRepoMock.On("GetOrderByTrackingCode", mock.Anything, trackingCode).
+			Return(nil, nil)
+
+		resp, err := service.TrackOrder(context.Background(), trackingCode)
+
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, models.ErrOrderNotFound)
+		orderRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenRepositoryFails_ShouldReturnError", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+
+		service := orderService{
+			or: orderRepoMock,
+		}
+
+		trackingCode := uuid.New()
+
+		orderRepoMock.On("GetOrderByTrackingCode", mock.Anything, trackingCode).
+			Return(nil, errors.New("database error"))
+
+		resp, err := service.TrackOrder(context.Background(), trackingCode)
+
+		assert.Nil(t, resp)
+		assert.Error(t, err)
+		orderRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenOrderIsPicknUp_ShouldReturnMaskedTimeline", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+
+		service := orderService{
+			or: orderRepoMock,
+		}
+
+		trackingCode := uuid.New()
+		deliverymanID := uuid.New()
+		createdAt := time.Now().UTC().Add(-time.Hour)
+		picknUpAt := time.Now().UTC()
+
+		orderRepoMock.On("GetOrderByTrackingCode", mock.Anything, trackingCode).
+			Return(&models.Order{
+				BaseModel:     models.BaseModel{ID: uuid.New(), CreatedAt: createdAt},
+				TrackingCode:  trackingCode,
+				Status:        models.PicknUp,
+				PicknUpAt:     sql.NullTime{Time: picknUpAt, Valid: true},
+				DeliverymanID: &deliverymanID,
+				Deliveryman:   models.User{FullName: "João Pereira da Silva"},
+				Recipient: models.Recipient{
+					FullName: "Maria Souza",
+					Address:  "Rua Exemplo, 123",
+					City:     "São Paulo",
+				},
+			}, nil)
+
+		resp, err := service.TrackOrder(context.Background(), trackingCode)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.PicknUp, resp.Status)
+		assert.Equal(t, "São Paulo", resp.City)
+		assert.Equal(t, "João S.", resp.DeliverymanName)
+		assert.Nil(t, resp.DeliveryAt)
+		assert.Len(t, resp.Timeline, 2)
+		assert.Equal(t, models.Waiting, resp.Timeline[0].Status)
+		assert.Equal(t, models.PicknUp, resp.Timeline[1].Status)
+		orderRepoMock.AssertExpectations(t)
+	})
+}
+
+func TestOrderService_CreateOrder(t *testing.T) {
+	t.Run("WhenOrderIsCreated_ShouldRecordWaitingEventInTransaction", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		orderEventRepoMock := new(mocks.OrderEventRepository)
+		recipientRepoMock := new(mocks.RecipientRepository)
+		transactionManagerMock := new(mocks.TransactionManager)
+		userRepoMock := new(mocks.UserRepository)
+
+		emailOutboxRepoMock := new(mocks.EmailOutboxRepository)
+
+		service := orderService{
+			ef:  email.NewEmailFactory(),
+			eor: emailOutboxRepoMock,
+			oer: orderEventRepoMock,
+			or:  orderRepoMock,
+			rr:  recipientRepoMock,
+			tm:  transactionManagerMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		recipientID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		recipientRepoMock.On("GetRecipientByID", mock.Anything, recipientID).
+			Return(&models.Recipient{Email: "recipient@example.com"}, nil)
+
+		var queued models.EmailOutbox
+		emailOutboxRepoMock.On("CreateEmailOutbox", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { queued = args.Get(1).(models.EmailOutbox) }).
+			Return(nil)
+
+		transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		orderRepoMock.On("CreateOrder", mock.Anything, mock.Anything).
+			Return(nil)
+
+		orderEventRepoMock.On("CreateOrderEvent", mock.Anything, mock.MatchedBy(func(event models.OrderEvent) bool {
+			return event.FromStatus == nil && event.ToStatus == models.Waiting && *event.ActorID == userID
+		})).Return(nil)
+
+		resp, err := service.CreateOrder(ctx, models.CreateOrderPayload{Title: "Package", RecipientID: recipientID})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, resp)
+		orderRepoMock.AssertExpectations(t)
+		orderEventRepoMock.AssertExpectations(t)
+		transactionManagerMock.AssertExpectations(t)
+
+		assert.Equal(t, "recipient@example.com", queued.To)
+		assert.Equal(t, models.EmailPending, queued.Status)
+		assert.Equal(t, templates.CreatedTemplate, queued.TemplateName)
+
+		params, err := templates.DecodeParams(queued.TemplateName, queued.Params)
+		assert.NoError(t, err)
+		assert.Equal(t, "Package", params.(*templates.CreatedParams).OrderTitle)
+	})
+
+	t.Run("WhenEventCannotBeRecorded_ShouldReturnError", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		orderEventRepoMock := new(mocks.OrderEventRepository)
+		recipientRepoMock := new(mocks.RecipientRepository)
+		transactionManagerMock := new(mocks.TransactionManager)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			oer: orderEventRepoMock,
+			or:  orderRepoMock,
+			rr:  recipientRepoMock,
+			tm:  transactionManagerMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		recipientID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		recipientRepoMock.On("GetRecipientByID", mock.Anything, recipientID).
+			Return(&models.Recipient{}, nil)
+
+		transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		orderRepoMock.On("CreateOrder", mock.Anything, mock.Anything).
+			Return(nil)
+
+		orderEventRepoMock.On("CreateOrderEvent", mock.Anything, mock.Anything).
+			Return(errors.New("database error"))
+
+		resp, err := service.CreateOrder(ctx, models.CreateOrderPayload{Title: "Package", RecipientID: recipientID})
+
+		assert.Nil(t, resp)
+		assert.Error(t, err)
+	})
+}
+
+func TestOrderService_GetOrderEvents(t *testing.T) {
+	t.Run("WhenDeliveryManIsNotAssigned_ShouldReturnErrNotAssignedToOrder", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			or: orderRepoMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		otherDeliverymanID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{DeliverymanID: &otherDeliverymanID}, nil)
+
+		resp, err := service.GetOrderEvents(ctx, orderID)
+
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, models.ErrNotAssignedToOrder)
+	})
+
+	t.Run("WhenDeliveryManReadsUnassignedOrder_ShouldReturnErrNotAssignedToOrder", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		orderEventRepoMock := new(mocks.OrderEventRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			oer: orderEventRepoMock,
+			or:  orderRepoMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.Waiting}, nil)
+
+		resp, err := service.GetOrderEvents(ctx, orderID)
+
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, models.ErrNotAssignedToOrder)
+		orderEventRepoMock.AssertNotCalled(t, "GetOrderEventsByOrderID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenOrderHasEvents_ShouldReturnThemInOrder", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		orderEventRepoMock := new(mocks.OrderEventRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			oer: orderEventRepoMock,
+			or:  orderRepoMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+		waiting := models.Waiting
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}}, nil)
+
+		orderEventRepoMock.On("GetOrderEventsByOrderID", mock.Anything, orderID).
+			Return([]models.OrderEvent{
+				{OrderID: orderID, ToStatus: models.Waiting, ActorID: &userID, Actor: models.User{FullName: "Admin User"}},
+				{OrderID: orderID, FromStatus: &waiting, ToStatus: models.PicknUp},
+			}, nil)
+
+		resp, err := service.GetOrderEvents(ctx, orderID)
+
+		assert.NoError(t, err)
+		assert.Len(t, resp, 2)
+		assert.Equal(t, "Admin User", resp[0].ActorName)
+		assert.Equal(t, models.PicknUp, resp[1].ToStatus)
+		orderEventRepoMock.AssertExpectations(t)
+	})
+}
+
+func TestOrderService_CancelOrder(t *testing.T) {
+	t.Run("WhenUserIsDeliveryMan_ShouldReturnErrInsufficientPermission", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			or: orderRepoMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.Waiting}, nil)
+
+		err := service.CancelOrder(ctx, orderID, models.CancelOrderPayload{Reason: "Pedido duplicado"})
+
+		assert.ErrorIs(t, err, models.ErrInsufficientPermission)
+		orderRepoMock.AssertNotCalled(t, "UpdateOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenOrderIsDelivered_ShouldReturnErrCannotTransitionToCanceled", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			or: orderRepoMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.Admin}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.Done}, nil)
+
+		err := service.CancelOrder(ctx, orderID, models.CancelOrderPayload{Reason: "Pedido duplicado"})
+
+		assert.ErrorIs(t, err, models.ErrCannotTransitionToCanceled)
+		orderRepoMock.AssertNotCalled(t, "UpdateOrder", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderService_ReturnOrder(t *testing.T) {
+	t.Run("WhenDeliveryManIsNotAssigned_ShouldReturnErrNotAssignedToOrder", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			or: orderRepoMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		otherDeliverymanID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.PicknUp, DeliverymanID: &otherDeliverymanID}, nil)
+
+		err := service.ReturnOrder(ctx, orderID, models.ReturnOrderPayload{Reason: "Destinatário ausente"})
+
+		assert.ErrorIs(t, err, models.ErrNotAssignedToOrder)
+		orderRepoMock.AssertNotCalled(t, "UpdateOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenOrderIsWaiting_ShouldReturnErrCannotTransitionToReturned", func(t *testing.T) {
+		orderRepoMock := new(mocks.OrderRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			or: orderRepoMock,
+			ur: userRepoMock,
+		}
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.Waiting}, nil)
+
+		err := service.ReturnOrder(ctx, orderID, models.ReturnOrderPayload{Reason: "Destinatário ausente"})
+
+		assert.ErrorIs(t, err, models.ErrCannotTransitionToReturned)
+	})
+}
+
+func TestOrderService_FailDelivery(t *testing.T) {
+	config.Env.Order.MaxDeliveryAttempts = 3
+
+	newService := func() (orderService, *mocks.OrderRepository, *mocks.OrderEventRepository, *mocks.DeliveryAttemptRepository, *mocks.UserRepository) {
+		orderRepoMock := new(mocks.OrderRepository)
+		orderEventRepoMock := new(mocks.OrderEventRepository)
+		deliveryAttemptRepoMock := new(mocks.DeliveryAttemptRepository)
+		transactionManagerMock := new(mocks.TransactionManager)
+		userRepoMock := new(mocks.UserRepository)
+
+		transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		return orderService{
+			dar: deliveryAttemptRepoMock,
+			oer: orderEventRepoMock,
+			or:  orderRepoMock,
+			tm:  transactionManagerMock,
+			ur:  userRepoMock,
+		}, orderRepoMock, orderEventRepoMock, deliveryAttemptRepoMock, userRepoMock
+	}
+
+	t.Run("WhenBelowAttemptLimit_ShouldKeepOrderAsDeliveryFailed", func(t *testing.T) {
+		service, orderRepoMock, orderEventRepoMock, deliveryAttemptRepoMock, userRepoMock := newService()
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.PicknUp, DeliverymanID: &userID}, nil)
+
+		orderRepoMock.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order models.Order) bool {
+			return order.Status == models.DeliveryFailed
+		})).Return(nil)
+
+		deliveryAttemptRepoMock.On("CreateDeliveryAttempt", mock.Anything, mock.MatchedBy(func(attempt models.DeliveryAttempt) bool {
+			return attempt.Reason == models.RecipientAbsent && attempt.DeliverymanID == userID
+		})).Return(nil)
+
+		orderEventRepoMock.On("CreateOrderEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+		resp, err := service.FailDelivery(ctx, orderID, models.FailDeliveryPayload{Reason: models.RecipientAbsent})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.DeliveryFailed, resp.Status)
+		assert.Equal(t, 1, resp.DeliveryAttempts)
+		orderRepoMock.AssertExpectations(t)
+		orderEventRepoMock.AssertExpectations(t)
+		deliveryAttemptRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenAttemptLimitIsReached_ShouldMoveOrderToReturning", func(t *testing.T) {
+		service, orderRepoMock, orderEventRepoMock, deliveryAttemptRepoMock, userRepoMock := newService()
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{
+				BaseModel:        models.BaseModel{ID: orderID},
+				Status:           models.DeliveryFailed,
+				DeliverymanID:    &userID,
+				DeliveryAttempts: []models.DeliveryAttempt{{}, {}},
+			}, nil)
+
+		orderRepoMock.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order models.Order) bool {
+			return order.Status == models.Returning
+		})).Return(nil)
+
+		deliveryAttemptRepoMock.On("CreateDeliveryAttempt", mock.Anything, mock.Anything).Return(nil)
+
+		orderEventRepoMock.On("CreateOrderEvent", mock.Anything, mock.MatchedBy(func(event models.OrderEvent) bool {
+			return event.ToStatus == models.DeliveryFailed
+		})).Return(nil).Once()
+
+		orderEventRepoMock.On("CreateOrderEvent", mock.Anything, mock.MatchedBy(func(event models.OrderEvent) bool {
+			return *event.FromStatus == models.DeliveryFailed && event.ToStatus == models.Returning
+		})).Return(nil).Once()
+
+		resp, err := service.FailDelivery(ctx, orderID, models.FailDeliveryPayload{Reason: models.WrongAddress})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.Returning, resp.Status)
+		assert.Equal(t, 3, resp.DeliveryAttempts)
+		orderRepoMock.AssertExpectations(t)
+		orderEventRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenOrderIsWaiting_ShouldReturnErrCannotTransitionToDeliveryFailed", func(t *testing.T) {
+		service, orderRepoMock, _, deliveryAttemptRepoMock, userRepoMock := newService()
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.Waiting}, nil)
+
+		resp, err := service.FailDelivery(ctx, orderID, models.FailDeliveryPayload{Reason: models.RecipientAbsent})
+
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, models.ErrCannotTransitionToDeliveryFailed)
+		deliveryAttemptRepoMock.AssertNotCalled(t, "CreateDeliveryAttempt", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrderService_DeliverOrder(t *testing.T) {
+	newProcessedImage := func() *models.ProcessedImage {
+		return &models.ProcessedImage{
+			Content:     []byte("png-content"),
+			Thumbnail:   []byte("png-thumb"),
+			ContentType: "image/png",
+			Extension:   ".png",
+			Hash:        "5d41402abc4b2a76b9719d911017c592",
+		}
+	}
+
+	t.Run("WhenOrderIsDelivered_ShouldStoreProofImage", func(t *testing.T) {
+		fileServiceMock := new(mocks.FileService)
+		objectStorageMock := new(mocks.ObjectStorage)
+		orderEventRepoMock := new(mocks.OrderEventRepository)
+		orderRepoMock := new(mocks.OrderRepository)
+		transactionManagerMock := new(mocks.TransactionManager)
+		userRepoMock := new(mocks.UserRepository)
+
+		emailOutboxRepoMock := new(mocks.EmailOutboxRepository)
+
+		service := orderService{
+			ef:  email.NewEmailFactory(),
+			eor: emailOutboxRepoMock,
+			fs:  fileServiceMock,
+			oer: orderEventRepoMock,
+			or:  orderRepoMock,
+			ost: objectStorageMock,
+			tm:  transactionManagerMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+		image := &multipart.FileHeader{Filename: "proof.png"}
+		processed := newProcessedImage()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.PicknUp, DeliverymanID: &userID}, nil)
+
+		fileServiceMock.On("ProcessImage", mock.Anything, image).Return(processed, nil)
+		orderRepoMock.On("GetOrderByProofImageHash", mock.Anything, processed.Hash).Return(nil, nil)
+
+		var storedKey string
+		objectStorageMock.On("PutObject", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "orders/"+orderID.String()+"/proof/") && !strings.HasSuffix(key, "_thumb.png")
+		}), mock.Anything, int64(len(processed.Content)), "image/png").
+			Run(func(args mock.Arguments) { storedKey = args.String(1) }).
+			Return(nil)
+
+		objectStorageMock.On("PutObject", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasSuffix(key, "_thumb.png")
+		}), mock.Anything, int64(len(processed.Thumbnail)), "image/png").Return(nil)
+
+		transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			})
+
+		orderRepoMock.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order models.Order) bool {
+			return order.Status == models.Done &&
+				order.ProofImageKey != nil && *order.ProofImageKey == storedKey &&
+				order.ProofImageHash != nil && *order.ProofImageHash == processed.Hash
+		})).Return(nil)
+
+		orderEventRepoMock.On("CreateOrderEvent", mock.Anything, mock.Anything).Return(nil)
+
+		var queued models.EmailOutbox
+		emailOutboxRepoMock.On("CreateEmailOutbox", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { queued = args.Get(1).(models.EmailOutbox) }).
+			Return(nil)
+
+		err := service.DeliverOrder(ctx, orderID, models.DeliverOrderPayload{OrderImage: image})
+
+		assert.NoError(t, err)
+		objectStorageMock.AssertExpectations(t)
+		orderRepoMock.AssertExpectations(t)
+
+		assert.Equal(t, templates.DeliveredTemplate, queued.TemplateName)
+
+		params, err := templates.DecodeParams(queued.TemplateName, queued.Params)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, params.(*templates.DeliveredParams).DeliveredAt)
+	})
+
+	t.Run("WhenImageWasUsedByAnotherOrder_ShouldReturnErrImageAlreadyUsed", func(t *testing.T) {
+		fileServiceMock := new(mocks.FileService)
+		objectStorageMock := new(mocks.ObjectStorage)
+		orderRepoMock := new(mocks.OrderRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			fs:  fileServiceMock,
+			or:  orderRepoMock,
+			ost: objectStorageMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+		image := &multipart.FileHeader{Filename: "proof.png"}
+		processed := newProcessedImage()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.PicknUp, DeliverymanID: &userID}, nil)
+
+		fileServiceMock.On("ProcessImage", mock.Anything, image).Return(processed, nil)
+		orderRepoMock.On("GetOrderByProofImageHash", mock.Anything, processed.Hash).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: uuid.New()}}, nil)
+
+		err := service.DeliverOrder(ctx, orderID, models.DeliverOrderPayload{OrderImage: image})
+
+		assert.ErrorIs(t, err, models.ErrImageAlreadyUsed)
+		objectStorageMock.AssertNotCalled(t, "PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenUpdateFails_ShouldDeleteStoredProofImage", func(t *testing.T) {
+		fileServiceMock := new(mocks.FileService)
+		objectStorageMock := new(mocks.ObjectStorage)
+		orderRepoMock := new(mocks.OrderRepository)
+		transactionManagerMock := new(mocks.TransactionManager)
+		userRepoMock := new(mocks.UserRepository)
+
+		service := orderService{
+			fs:  fileServiceMock,
+			or:  orderRepoMock,
+			ost: objectStorageMock,
+			tm:  transactionManagerMock,
+			ur:  userRepoMock,
+		}
+
+		userID := uuid.New()
+		orderID := uuid.New()
+		ctx := request.WithUserID(context.Background(), userID)
+		image := &multipart.FileHeader{Filename: "proof.png"}
+		processed := newProcessedImage()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, orderID).
+			Return(&models.Order{BaseModel: models.BaseModel{ID: orderID}, Status: models.PicknUp, DeliverymanID: &userID}, nil)
+
+		fileServiceMock.On("ProcessImage", mock.Anything, image).Return(processed, nil)
+		orderRepoMock.On("GetOrderByProofImageHash", mock.Anything, processed.Hash).Return(nil, nil)
+		objectStorageMock.On("PutObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "image/png").Return(nil)
+		objectStorageMock.On("DeleteObject", mock.Anything, mock.Anything).Return(nil).Twice()
+
+		transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+			Return(errors.New("database down"))
+
+		err := service.DeliverOrder(ctx, orderID, models.DeliverOrderPayload{OrderImage: image})
+
+		assert.Error(t, err)
+		objectStorageMock.AssertExpectations(t)
+	})
+}
+
+func TestOrderService_GetOrderProof(t *testing.T) {
+	config.Env.Storage.SignedURLExp = 300
+
+	newService := func(userID uuid.UUID, role models.Role, order *models.Order) (orderService, *mocks.ObjectStorage) {
+		objectStorageMock := new(mocks.ObjectStorage)
+		orderRepoMock := new(mocks.OrderRepository)
+		userRepoMock := new(mocks.UserRepository)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: role}, nil)
+
+		orderRepoMock.On("GetOrderByID", mock.Anything, order.ID).Return(order, nil)
+
+		return orderService{
+			or:  orderRepoMock,
+			ost: objectStorageMock,
+			ur:  userRepoMock,
+		}, objectStorageMock
+	}
+
+	t.Run("WhenStorageSignsURLs_ShouldReturnSignedURL", func(t *testing.T) {
+		userID := uuid.New()
+		key := "orders/proof.png"
+		order := &models.Order{BaseModel: models.BaseModel{ID: uuid.New()}, Status: models.Done, ProofImageKey: &key}
+		service, objectStorageMock := newService(userID, models.Admin, order)
+
+		objectStorageMock.On("SignedURL", mock.Anything, key, 5*time.Minute).
+			Return("https://storage.local/orders/proof.png?X-Amz-Signature=abc", nil)
+
+		proof, err := service.GetOrderProof(request.WithUserID(context.Background(), userID), order.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://storage.local/orders/proof.png?X-Amz-Signature=abc", proof.URL)
+		objectStorageMock.AssertNotCalled(t, "GetObject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenStorageCannotSignURLs_ShouldReturnObjectContent", func(t *testing.T) {
+		userID := uuid.New()
+		key := "orders/proof.png"
+		order := &models.Order{BaseModel: models.BaseModel{ID: uuid.New()}, Status: models.Done, DeliverymanID: &userID, ProofImageKey: &key}
+		service, objectStorageMock := newService(userID, models.DeliveryMan, order)
+
+		objectStorageMock.On("SignedURL", mock.Anything, key, mock.Anything).
+			Return("", storage.ErrSignedURLNotSupported)
+
+		objectStorageMock.On("GetObject", mock.Anything, key).
+			Return(&storage.Object{Body: io.NopCloser(strings.NewReader("png")), ContentType: "image/png", Size: 3}, nil)
+
+		proof, err := service.GetOrderProof(request.WithUserID(context.Background(), userID), order.ID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, proof.URL)
+		assert.Equal(t, "image/png", proof.ContentType)
+		assert.Equal(t, int64(3), proof.Size)
+	})
+
+	t.Run("WhenOrderHasNoProof_ShouldReturnErrProofNotFound", func(t *testing.T) {
+		userID := uuid.New()
+		order := &models.Order{BaseModel: models.BaseModel{ID: uuid.New()}, Status: models.PicknUp}
+		service, objectStorageMock := newService(userID, models.Admin, order)
+
+		proof, err := service.GetOrderProof(request.WithUserID(context.Background(), userID), order.ID)
+
+		assert.Nil(t, proof)
+		assert.ErrorIs(t, err, models.ErrProofNotFound)
+		objectStorageMock.AssertNotCalled(t, "SignedURL", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenDeliveryManIsNotAssigned_ShouldReturnErrNotAssignedToOrder", func(t *testing.T) {
+		userID := uuid.New()
+		otherID := uuid.New()
+		key := "orders/proof.png"
+		order := &models.Order{BaseModel: models.BaseModel{ID: uuid.New()}, Status: models.Done, DeliverymanID: &otherID, ProofImageKey: &key}
+		service, _ := newService(userID, models.DeliveryMan, order)
+
+		proof, err := service.GetOrderProof(request.WithUserID(context.Background(), userID), order.ID)
+
+		assert.Nil(t, proof)
+		assert.ErrorIs(t, err, models.ErrNotAssignedToOrder)
+	})
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"encoding/base64"
 
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"golang.org/x/crypto/bcrypt"
@@ -11,6 +12,7 @@ import (
 //go:generate mockery --name=SecureService --filename=secure_service.go --output=../mocks --outpkg=mocks
 type SecureService interface {
 	CreatePassword(ctx context.Context) (string, error)
+	CreateToken(ctx context.Context) (string, error)
 	HashPassword(ctx context.Context, password string) (string, error)
 	CheckPassword(ctx context.Context, hashedPassword, password string) error
 }
@@ -34,6 +36,16 @@ func (s *secureService) CreatePassword(ctx context.Context) (string, error) {
 	return password, nil
 }
 
+// CreateToken returns a random URL-safe token with 256 bits of entropy.
+func (s *secureService) CreateToken(ctx context.Context) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 func (s *secureService) CheckPassword(ctx context.Context, hashedPassword string, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
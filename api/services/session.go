@@ -0,0 +1,397 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/repositories"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/storage"
+	"github.com/google/uuid"
+)
+
+//go:generate mockery --name=SessionService --filename=session_service.go --output=../mocks --outpkg=mocks
+type SessionService interface {
+	CreateSession(ctx context.Context, userID uuid.UUID) (*models.LoginResponse, error)
+	RefreshSession(ctx context.Context, refreshToken string) (*models.LoginResponse, error)
+	ValidateSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	GetSessions(ctx context.Context) ([]models.SessionResponse, error)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context) error
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	EndSession(ctx context.Context, accessToken string, refreshToken string) error
+}
+
+type sessionService struct {
+	i   *di.Injector
+	ts  TokenService
+	ss  SecureService
+	ur  repositories.UserRepository
+	c   storage.Cache
+	now func() time.Time
+}
+
+func NewSessionService(i *di.Injector) (SessionService, error) {
+	ts, err := di.Invoke[TokenService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke token service: %w", err)
+	}
+
+	ss, err := di.Invoke[SecureService](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke secure service: %w", err)
+	}
+
+	ur, err := di.Invoke[repositories.UserRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke user repository: %w", err)
+	}
+
+	c, err := di.Invoke[storage.Cache](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke cache: %w", err)
+	}
+
+	return &sessionService{
+		i:   i,
+		ts:  ts,
+		ss:  ss,
+		ur:  ur,
+		c:   c,
+		now: time.Now,
+	}, nil
+}
+
+// CreateSession starts a session for the user and returns its first token
+// pair. The access token carries the session ID in its jti claim.
+func (s *sessionService) CreateSession(ctx context.Context, userID uuid.UUID) (*models.LoginResponse, error) {
+	now := s.now().UTC()
+	ip, userAgent := request.ClientInfo(ctx)
+
+	session := models.NewSession(userID, ip, userAgent, now, refreshTokenTTL())
+
+	return s.issueTokens(ctx, session, now)
+}
+
+// RefreshSession exchanges a refresh token for a new token pair. Each refresh
+// token works once; presenting a rotated one means it leaked, so the whole
+// session is revoked.
+func (s *sessionService) RefreshSession(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
+	tokenHash := models.HashToken(refreshToken)
+
+	var stored models.RefreshToken
+	if err := s.c.GetDel(ctx, refreshTokenKey(tokenHash), &stored); err != nil {
+		if errors.Is(err, storage.ErrCacheMiss) {
+			return nil, models.ErrRefreshTokenInvalid
+		}
+
+		return nil, fmt.Errorf("get refresh token: %w", err)
+	}
+
+	if stored.Used {
+		if err := s.deleteSessions(ctx, stored.UserID, stored.SessionID); err != nil {
+			return nil, err
+		}
+
+		return nil, models.ErrRefreshTokenReused
+	}
+
+	session, err := s.getSession(ctx, stored.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil || session.RefreshTokenHash != tokenHash {
+		return nil, models.ErrRefreshTokenInvalid
+	}
+
+	user, err := s.ur.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", session.UserID, err)
+	}
+
+	if user == nil {
+		if err := s.deleteSessions(ctx, session.UserID, session.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, models.ErrRefreshTokenInvalid
+	}
+
+	if user.Status == models.BlockedStatus {
+		return nil, models.ErrUserBlocked
+	}
+
+	stored.Used = true
+	if err := s.c.Set(ctx, refreshTokenKey(tokenHash), stored, refreshTokenTTL()); err != nil {
+		return nil, fmt.Errorf("mark refresh token as used: %w", err)
+	}
+
+	return s.issueTokens(ctx, session, s.now().UTC())
+}
+
+// ValidateSession reports ErrSessionRevoked when the session is no longer in
+// the store, which covers logout, revocation and expiration alike.
+func (s *sessionService) ValidateSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	session, err := s.getSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.UserID != userID {
+		return models.ErrSessionRevoked
+	}
+
+	return nil
+}
+
+func (s *sessionService) GetSessions(ctx context.Context) ([]models.SessionResponse, error) {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return nil, models.ErrUserNotFoundInContext
+	}
+
+	currentSessionID, _ := request.SessionID(ctx)
+
+	sessions, err := s.getUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(sessions, func(a, b int) bool {
+		return sessions[a].CreatedAt.After(sessions[b].CreatedAt)
+	})
+
+	response := make([]models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, *session.ToSessionResponse(currentSessionID))
+	}
+
+	return response, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return models.ErrUserNotFoundInContext
+	}
+
+	session, err := s.getSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session == nil || session.UserID != userID {
+		return models.ErrSessionNotFound
+	}
+
+	return s.deleteSessions(ctx, userID, sessionID)
+}
+
+// RevokeOtherSessions signs the user out everywhere except the current session.
+func (s *sessionService) RevokeOtherSessions(ctx context.Context) error {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return models.ErrUserNotFoundInContext
+	}
+
+	currentSessionID, _ := request.SessionID(ctx)
+
+	members, err := s.c.SetMembers(ctx, userSessionsKey(userID))
+	if err != nil {
+		return fmt.Errorf("list sessions of user %q: %w", userID, err)
+	}
+
+	var sessionIDs []uuid.UUID
+	for _, member := range members {
+		sessionID, err := uuid.Parse(member)
+		if err != nil || sessionID == currentSessionID {
+			continue
+		}
+
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+
+	return s.deleteSessions(ctx, userID, sessionIDs...)
+}
+
+func (s *sessionService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	members, err := s.c.SetMembers(ctx, userSessionsKey(userID))
+	if err != nil {
+		return fmt.Errorf("list sessions of user %q: %w", userID, err)
+	}
+
+	sessionIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if sessionID, err := uuid.Parse(member); err == nil {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+
+	return s.deleteSessions(ctx, userID, sessionIDs...)
+}
+
+// EndSession revokes the session that an access or a refresh token belongs
+// to. The refresh token lets clients log out after their access token has
+// expired. ErrSessionNotFound is returned when neither token resolves to a
+// session.
+func (s *sessionService) EndSession(ctx context.Context, accessToken string, refreshToken string) error {
+	if accessToken != "" {
+		if claims, err := s.ts.ParseToken(ctx, accessToken); err == nil {
+			if sessionID, err := claims.SessionID(); err == nil {
+				return s.deleteSessions(ctx, claims.UserID, sessionID)
+			}
+		}
+	}
+
+	if refreshToken == "" {
+		return models.ErrSessionNotFound
+	}
+
+	var stored models.RefreshToken
+	if err := s.c.Get(ctx, refreshTokenKey(models.HashToken(refreshToken)), &stored); err != nil {
+		if errors.Is(err, storage.ErrCacheMiss) {
+			return models.ErrSessionNotFound
+		}
+
+		return fmt.Errorf("get refresh token: %w", err)
+	}
+
+	return s.deleteSessions(ctx, stored.UserID, stored.SessionID)
+}
+
+// issueTokens rotates the refresh token of the session, saves it and signs a
+// new access token.
+func (s *sessionService) issueTokens(ctx context.Context, session *models.Session, now time.Time) (*models.LoginResponse, error) {
+	refreshToken, err := s.ss.CreateToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create refresh token: %w", err)
+	}
+
+	ttl := refreshTokenTTL()
+	session.Rotate(models.HashToken(refreshToken), now, ttl)
+
+	if err := s.c.Set(ctx, sessionKey(session.ID), session, ttl); err != nil {
+		return nil, fmt.Errorf("store session: %w", err)
+	}
+
+	if err := s.c.AddToSet(ctx, userSessionsKey(session.UserID), session.ID.String(), ttl); err != nil {
+		return nil, fmt.Errorf("index session: %w", err)
+	}
+
+	stored := models.RefreshToken{
+		SessionID: session.ID,
+		UserID:    session.UserID,
+	}
+
+	if err := s.c.Set(ctx, refreshTokenKey(session.RefreshTokenHash), stored, ttl); err != nil {
+		return nil, fmt.Errorf("store refresh token: %w", err)
+	}
+
+	accessTTL := time.Duration(config.Env.Session.AccessTokenExp) * time.Minute
+
+	token, err := s.ts.CreateToken(ctx, models.TokenPayload{
+		UserID:    session.UserID,
+		SessionID: session.ID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(accessTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTTL.Seconds()),
+	}, nil
+}
+
+func (s *sessionService) getSession(ctx context.Context, sessionID uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	if err := s.c.Get(ctx, sessionKey(sessionID), &session); err != nil {
+		if errors.Is(err, storage.ErrCacheMiss) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get session %q: %w", sessionID, err)
+	}
+
+	return &session, nil
+}
+
+// getUserSessions returns the live sessions of a user and drops index entries
+// whose session already expired.
+func (s *sessionService) getUserSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	members, err := s.c.SetMembers(ctx, userSessionsKey(userID))
+	if err != nil {
+		return nil, fmt.Errorf("list sessions of user %q: %w", userID, err)
+	}
+
+	var sessions []models.Session
+	var stale []string
+	for _, member := range members {
+		sessionID, err := uuid.Parse(member)
+		if err != nil {
+			stale = append(stale, member)
+			continue
+		}
+
+		session, err := s.getSession(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if session == nil {
+			stale = append(stale, member)
+			continue
+		}
+
+		sessions = append(sessions, *session)
+	}
+
+	if err := s.c.RemoveFromSet(ctx, userSessionsKey(userID), stale...); err != nil {
+		return nil, fmt.Errorf("prune sessions of user %q: %w", userID, err)
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionService) deleteSessions(ctx context.Context, userID uuid.UUID, sessionIDs ...uuid.UUID) error {
+	members := make([]string, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		if err := s.c.Delete(ctx, sessionKey(sessionID)); err != nil {
+			return fmt.Errorf("delete session %q: %w", sessionID, err)
+		}
+
+		members = append(members, sessionID.String())
+	}
+
+	if err := s.c.RemoveFromSet(ctx, userSessionsKey(userID), members...); err != nil {
+		return fmt.Errorf("unindex sessions of user %q: %w", userID, err)
+	}
+
+	return nil
+}
+
+func sessionKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("session:%s", sessionID)
+}
+
+func userSessionsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("session:user:%s", userID)
+}
+
+func refreshTokenKey(tokenHash string) string {
+	return fmt.Sprintf("refresh-token:%s", tokenHash)
+}
+
+func refreshTokenTTL() time.Duration {
+	return time.Duration(config.Env.Session.RefreshTokenExp) * time.Hour
+}
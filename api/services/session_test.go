@@ -0,0 +1,418 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSessionService_CreateSession(t *testing.T) {
+	config.Env.Session.AccessTokenExp = 15
+	config.Env.Session.RefreshTokenExp = 720
+
+	t.Run("WhenSessionIsStored_ShouldReturnTokenPairBoundToSession", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		tokenServiceMock := new(mocks.TokenService)
+		secureServiceMock := new(mocks.SecureService)
+
+		now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+		service := &sessionService{
+			ts:  tokenServiceMock,
+			ss:  secureServiceMock,
+			c:   cacheMock,
+			now: func() time.Time { return now },
+		}
+
+		userID := uuid.New()
+		ctx := request.WithClientInfo(context.Background(), "10.0.0.1", "Mozilla/5.0")
+
+		secureServiceMock.On("CreateToken", mock.Anything).
+			Return("refresh-token", nil)
+
+		var stored *models.Session
+		cacheMock.On("Set", mock.Anything, mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "session:") }), mock.Anything, 720*time.Hour).
+			Run(func(args mock.Arguments) {
+				stored = args.Get(2).(*models.Session)
+			}).
+			Return(nil)
+
+		cacheMock.On("AddToSet", mock.Anything, "session:user:"+userID.String(), mock.AnythingOfType("string"), 720*time.Hour).
+			Return(nil)
+
+		cacheMock.On("Set", mock.Anything, "refresh-token:"+models.HashToken("refresh-token"), mock.AnythingOfType("models.RefreshToken"), 720*time.Hour).
+			Return(nil)
+
+		tokenServiceMock.On("CreateToken", mock.Anything, mock.MatchedBy(func(p models.TokenPayload) bool {
+			return p.UserID == userID && p.SessionID == stored.ID && p.ExpiresAt.Equal(now.Add(15*time.Minute))
+		})).Return("jwt-token", nil)
+
+		response, err := service.CreateSession(ctx, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "jwt-token", response.Token)
+		assert.Equal(t, "refresh-token", response.RefreshToken)
+		assert.Equal(t, int64(900), response.ExpiresIn)
+		assert.Equal(t, "10.0.0.1", stored.IP)
+		assert.Equal(t, "Mozilla/5.0", stored.UserAgent)
+		assert.Equal(t, models.HashToken("refresh-token"), stored.RefreshTokenHash)
+		cacheMock.AssertExpectations(t)
+	})
+}
+
+func TestSessionService_RefreshSession(t *testing.T) {
+	config.Env.Session.AccessTokenExp = 15
+	config.Env.Session.RefreshTokenExp = 720
+
+	userID := uuid.New()
+	sessionID := uuid.New()
+	tokenHash := models.HashToken("old-refresh-token")
+
+	newService := func() (*sessionService, *mocks.Cache, *mocks.UserRepository, *mocks.TokenService, *mocks.SecureService) {
+		cacheMock := new(mocks.Cache)
+		userRepoMock := new(mocks.UserRepository)
+		tokenServiceMock := new(mocks.TokenService)
+		secureServiceMock := new(mocks.SecureService)
+
+		return &sessionService{
+			ts:  tokenServiceMock,
+			ss:  secureServiceMock,
+			ur:  userRepoMock,
+			c:   cacheMock,
+			now: time.Now,
+		}, cacheMock, userRepoMock, tokenServiceMock, secureServiceMock
+	}
+
+	t.Run("WhenTokenIsUnknown_ShouldReturnErrRefreshTokenInvalid", func(t *testing.T) {
+		service, cacheMock, _, _, _ := newService()
+
+		cacheMock.On("GetDel", mock.Anything, "refresh-token:"+tokenHash, mock.Anything).
+			Return(storage.ErrCacheMiss)
+
+		response, err := service.RefreshSession(context.Background(), "old-refresh-token")
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrRefreshTokenInvalid)
+	})
+
+	t.Run("WhenTokenWasAlreadyUsed_ShouldRevokeWholeSession", func(t *testing.T) {
+		service, cacheMock, _, _, _ := newService()
+
+		cacheMock.On("GetDel", mock.Anything, "refresh-token:"+tokenHash, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.RefreshToken) = models.RefreshToken{SessionID: sessionID, UserID: userID, Used: true}
+			}).
+			Return(nil)
+
+		cacheMock.On("Delete", mock.Anything, "session:"+sessionID.String()).
+			Return(nil)
+
+		cacheMock.On("RemoveFromSet", mock.Anything, "session:user:"+userID.String(), sessionID.String()).
+			Return(nil)
+
+		response, err := service.RefreshSession(context.Background(), "old-refresh-token")
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrRefreshTokenReused)
+		cacheMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenTokenIsCurrent_ShouldMarkItUsedAndRotate", func(t *testing.T) {
+		service, cacheMock, userRepoMock, tokenServiceMock, secureServiceMock := newService()
+
+		cacheMock.On("GetDel", mock.Anything, "refresh-token:"+tokenHash, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.RefreshToken) = models.RefreshToken{SessionID: sessionID, UserID: userID}
+			}).
+			Return(nil)
+
+		cacheMock.On("Get", mock.Anything, "session:"+sessionID.String(), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.Session) = models.Session{ID: sessionID, UserID: userID, RefreshTokenHash: tokenHash}
+			}).
+			Return(nil)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Status: models.ActiveStatus}, nil)
+
+		cacheMock.On("Set", mock.Anything, "refresh-token:"+tokenHash, models.RefreshToken{SessionID: sessionID, UserID: userID, Used: true}, 720*time.Hour).
+			Return(nil)
+
+		secureServiceMock.On("CreateToken", mock.Anything).
+			Return("new-refresh-token", nil)
+
+		cacheMock.On("Set", mock.Anything, "session:"+sessionID.String(), mock.MatchedBy(func(s *models.Session) bool {
+			return s.RefreshTokenHash == models.HashToken("new-refresh-token")
+		}), 720*time.Hour).Return(nil)
+
+		cacheMock.On("AddToSet", mock.Anything, "session:user:"+userID.String(), sessionID.String(), 720*time.Hour).
+			Return(nil)
+
+		cacheMock.On("Set", mock.Anything, "refresh-token:"+models.HashToken("new-refresh-token"), models.RefreshToken{SessionID: sessionID, UserID: userID}, 720*time.Hour).
+			Return(nil)
+
+		tokenServiceMock.On("CreateToken", mock.Anything, mock.MatchedBy(func(p models.TokenPayload) bool {
+			return p.SessionID == sessionID && p.UserID == userID
+		})).Return("new-access-token", nil)
+
+		response, err := service.RefreshSession(context.Background(), "old-refresh-token")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new-access-token", response.Token)
+		assert.Equal(t, "new-refresh-token", response.RefreshToken)
+		cacheMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenUserIsBlocked_ShouldReturnErrUserBlocked", func(t *testing.T) {
+		service, cacheMock, userRepoMock, _, _ := newService()
+
+		cacheMock.On("GetDel", mock.Anything, "refresh-token:"+tokenHash, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.RefreshToken) = models.RefreshToken{SessionID: sessionID, UserID: userID}
+			}).
+			Return(nil)
+
+		cacheMock.On("Get", mock.Anything, "session:"+sessionID.String(), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.Session) = models.Session{ID: sessionID, UserID: userID, RefreshTokenHash: tokenHash}
+			}).
+			Return(nil)
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Status: models.BlockedStatus}, nil)
+
+		response, err := service.RefreshSession(context.Background(), "old-refresh-token")
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrUserBlocked)
+	})
+}
+
+func TestSessionService_ValidateSession(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	t.Run("WhenSessionIsMissing_ShouldReturnErrSessionRevoked", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &sessionService{c: cacheMock}
+
+		cacheMock.On("Get", mock.Anything, "session:"+sessionID.String(), mock.Anything).
+			Return(storage.ErrCacheMiss)
+
+		err := service.ValidateSession(context.Background(), userID, sessionID)
+
+		assert.ErrorIs(t, err, models.ErrSessionRevoked)
+	})
+
+	t.Run("WhenSessionBelongsToAnotherUser_ShouldReturnErrSessionRevoked", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &sessionService{c: cacheMock}
+
+		cacheMock.On("Get", mock.Anything, "session:"+sessionID.String(), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.Session) = models.Session{ID: sessionID, UserID: uuid.New()}
+			}).
+			Return(nil)
+
+		err := service.ValidateSession(context.Background(), userID, sessionID)
+
+		assert.ErrorIs(t, err, models.ErrSessionRevoked)
+	})
+
+	t.Run("WhenSessionIsActive_ShouldReturnNil", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &sessionService{c: cacheMock}
+
+		cacheMock.On("Get", mock.Anything, "session:"+sessionID.String(), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.Session) = models.Session{ID: sessionID, UserID: userID}
+			}).
+			Return(nil)
+
+		err := service.ValidateSession(context.Background(), userID, sessionID)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestSessionService_GetSessions(t *testing.T) {
+	t.Run("WhenIndexHasExpiredSessions_ShouldPruneThemAndMarkCurrent", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &sessionService{c: cacheMock}
+
+		userID := uuid.New()
+		current := models.Session{ID: uuid.New(), UserID: userID, CreatedAt: time.Now().Add(-time.Hour)}
+		other := models.Session{ID: uuid.New(), UserID: userID, CreatedAt: time.Now()}
+		expiredID := uuid.New()
+
+		ctx := request.WithUserID(context.Background(), userID)
+		ctx = request.WithSessionID(ctx, current.ID)
+
+		cacheMock.On("SetMembers", mock.Anything, "session:user:"+userID.String()).
+			Return([]string{current.ID.String(), expiredID.String(), other.ID.String()}, nil)
+
+		for _, session := range []models.Session{current, other} {
+			session := session
+			cacheMock.On("Get", mock.Anything, "session:"+session.ID.String(), mock.Anything).
+				Run(func(args mock.Arguments) {
+					*args.Get(2).(*models.Session) = session
+				}).
+				Return(nil)
+		}
+
+		cacheMock.On("Get", mock.Anything, "session:"+expiredID.String(), mock.Anything).
+			Return(storage.ErrCacheMiss)
+
+		cacheMock.On("RemoveFromSet", mock.Anything, "session:user:"+userID.String(), expiredID.String()).
+			Return(nil)
+
+		response, err := service.GetSessions(ctx)
+
+		assert.NoError(t, err)
+		assert.Len(t, response, 2)
+		assert.Equal(t, other.ID, response[0].ID)
+		assert.False(t, response[0].Current)
+		assert.True(t, response[1].Current)
+		cacheMock.AssertExpectations(t)
+	})
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+	ctx := request.WithUserID(context.Background(), userID)
+
+	t.Run("WhenSessionBelongsToAnotherUser_ShouldReturnErrSessionNotFound", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &sessionService{c: cacheMock}
+
+		cacheMock.On("Get", mock.Anything, "session:"+sessionID.String(), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.Session) = models.Session{ID: sessionID, UserID: uuid.New()}
+			}).
+			Return(nil)
+
+		err := service.RevokeSession(ctx, sessionID)
+
+		assert.ErrorIs(t, err, models.ErrSessionNotFound)
+		cacheMock.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenSessionBelongsToUser_ShouldDeleteAndUnindexIt", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &sessionService{c: cacheMock}
+
+		cacheMock.On("Get", mock.Anything, "session:"+sessionID.String(), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.Session) = models.Session{ID: sessionID, UserID: userID}
+			}).
+			Return(nil)
+
+		cacheMock.On("Delete", mock.Anything, "session:"+sessionID.String()).
+			Return(nil)
+
+		cacheMock.On("RemoveFromSet", mock.Anything, "session:user:"+userID.String(), sessionID.String()).
+			Return(nil)
+
+		err := service.RevokeSession(ctx, sessionID)
+
+		assert.NoError(t, err)
+		cacheMock.AssertExpectations(t)
+	})
+}
+
+func TestSessionService_EndSession(t *testing.T) {
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	t.Run("WhenAccessTokenIsValid_ShouldDeleteItsSession", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		tokenServiceMock := new(mocks.TokenService)
+		service := &sessionService{c: cacheMock, ts: tokenServiceMock}
+
+		claims := &models.TokenClaims{UserID: userID}
+		claims.ID = sessionID.String()
+		tokenServiceMock.On("ParseToken", mock.Anything, "access-token").Return(claims, nil)
+
+		cacheMock.On("Delete", mock.Anything, "session:"+sessionID.String()).Return(nil)
+		cacheMock.On("RemoveFromSet", mock.Anything, "session:user:"+userID.String(), sessionID.String()).Return(nil)
+
+		err := service.EndSession(context.Background(), "access-token", "")
+
+		assert.NoError(t, err)
+		cacheMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenAccessTokenExpired_ShouldResolveSessionFromRefreshToken", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		tokenServiceMock := new(mocks.TokenService)
+		service := &sessionService{c: cacheMock, ts: tokenServiceMock}
+
+		tokenServiceMock.On("ParseToken", mock.Anything, "expired-token").Return(nil, errors.New("token is expired"))
+
+		cacheMock.On("Get", mock.Anything, "refresh-token:"+models.HashToken("refresh-token"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*models.RefreshToken) = models.RefreshToken{SessionID: sessionID, UserID: userID}
+			}).
+			Return(nil)
+		cacheMock.On("Delete", mock.Anything, "session:"+sessionID.String()).Return(nil)
+		cacheMock.On("RemoveFromSet", mock.Anything, "session:user:"+userID.String(), sessionID.String()).Return(nil)
+
+		err := service.EndSession(context.Background(), "expired-token", "refresh-token")
+
+		assert.NoError(t, err)
+		cacheMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenRefreshTokenIsUnknown_ShouldReturnErrSessionNotFound", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &sessionService{c: cacheMock}
+
+		cacheMock.On("Get", mock.Anything, "refresh-token:"+models.HashToken("refresh-token"), mock.Anything).
+			Return(storage.ErrCacheMiss)
+
+		err := service.EndSession(context.Background(), "", "refresh-token")
+
+		assert.ErrorIs(t, err, models.ErrSessionNotFound)
+		cacheMock.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSessionService_RevokeOtherSessions(t *testing.T) {
+	t.Run("WhenUserHasOtherSessions_ShouldKeepOnlyCurrent", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &sessionService{c: cacheMock}
+
+		userID := uuid.New()
+		currentID := uuid.New()
+		otherID := uuid.New()
+
+		ctx := request.WithUserID(context.Background(), userID)
+		ctx = request.WithSessionID(ctx, currentID)
+
+		cacheMock.On("SetMembers", mock.Anything, "session:user:"+userID.String()).
+			Return([]string{currentID.String(), otherID.String()}, nil)
+
+		cacheMock.On("Delete", mock.Anything, "session:"+otherID.String()).
+			Return(nil)
+
+		cacheMock.On("RemoveFromSet", mock.Anything, "session:user:"+userID.String(), otherID.String()).
+			Return(nil)
+
+		err := service.RevokeOtherSessions(ctx)
+
+		assert.NoError(t, err)
+		cacheMock.AssertExpectations(t)
+		cacheMock.AssertNotCalled(t, "Delete", mock.Anything, "session:"+currentID.String())
+	})
+}
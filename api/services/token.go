@@ -2,9 +2,9 @@ package services
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
 
-	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/golang-jwt/jwt/v5"
@@ -13,16 +13,24 @@ import (
 //go:generate mockery --name=TokenService --filename=token_service.go --output=../mocks --outpkg=mocks
 type TokenService interface {
 	CreateToken(ctx context.Context, payload models.TokenPayload) (string, error)
+	ParseToken(ctx context.Context, token string) (*models.TokenClaims, error)
+	GetJWKS(ctx context.Context) *models.JWKS
 }
 
 type tokenService struct {
-	i *di.Injector
+	i    *di.Injector
+	keys *KeySet
 }
 
 func NewTokenService(i *di.Injector) (TokenService, error) {
+	keys, err := di.Invoke[*KeySet](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke key set: %w", err)
+	}
 
 	return &tokenService{
-		i: i,
+		i:    i,
+		keys: keys,
 	}, nil
 }
 
@@ -30,11 +38,30 @@ func (t *tokenService) CreateToken(ctx context.Context, payload models.TokenPayl
 	claims := models.TokenClaims{
 		UserID: payload.UserID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(config.Env.Session.TokenExp))),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        payload.SessionID.String(),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.Env.Session.JWTSecret))
+	return t.keys.Sign(claims)
+}
+
+// ParseToken verifies the signature and expiration of an access token.
+func (t *tokenService) ParseToken(ctx context.Context, tokenString string) (*models.TokenClaims, error) {
+	token, err := t.keys.Parse(tokenString, &models.TokenClaims{})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*models.TokenClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+// GetJWKS returns the public keys other services use to verify our tokens.
+func (t *tokenService) GetJWKS(ctx context.Context) *models.JWKS {
+	return t.keys.JWKS()
 }
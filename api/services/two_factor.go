@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/di"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/repositories"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/storage"
+	"github.com/G-Villarinho/fast-feet-api/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew is the number of 30 second steps accepted on either side of
+	// the current one.
+	totpSkew = 1
+)
+
+//go:generate mockery --name=TwoFactorService --filename=two_factor_service.go --output=../mocks --outpkg=mocks
+type TwoFactorService interface {
+	StartEnrollment(ctx context.Context) (*models.TwoFactorEnrollmentResponse, error)
+	ConfirmEnrollment(ctx context.Context, payload models.TwoFactorCodePayload) (*models.RecoveryCodesResponse, error)
+	DisableTwoFactor(ctx context.Context, payload models.TwoFactorCodePayload) error
+	RegenerateRecoveryCodes(ctx context.Context, payload models.TwoFactorCodePayload) (*models.RecoveryCodesResponse, error)
+	BeginEnrollment(ctx context.Context, user models.User) (*models.TwoFactorEnrollmentResponse, error)
+	CompleteEnrollment(ctx context.Context, user *models.User, code string) ([]string, error)
+	VerifyCode(ctx context.Context, user *models.User, code string) error
+}
+
+type twoFactorService struct {
+	i   *di.Injector
+	ur  repositories.UserRepository
+	rcr repositories.RecoveryCodeRepository
+	alr repositories.AuditLogRepository
+	tm  repositories.TransactionManager
+	lg  LoginGuard
+	c   storage.Cache
+	now func() time.Time
+}
+
+func NewTwoFactorService(i *di.Injector) (TwoFactorService, error) {
+	ur, err := di.Invoke[repositories.UserRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke user repository: %w", err)
+	}
+
+	rcr, err := di.Invoke[repositories.RecoveryCodeRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke recovery code repository: %w", err)
+	}
+
+	alr, err := di.Invoke[repositories.AuditLogRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke audit log repository: %w", err)
+	}
+
+	tm, err := di.Invoke[repositories.TransactionManager](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke transaction manager: %w", err)
+	}
+
+	lg, err := di.Invoke[LoginGuard](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke login guard: %w", err)
+	}
+
+	c, err := di.Invoke[storage.Cache](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke cache: %w", err)
+	}
+
+	return &twoFactorService{
+		i:   i,
+		ur:  ur,
+		rcr: rcr,
+		alr: alr,
+		tm:  tm,
+		lg:  lg,
+		c:   c,
+		now: time.Now,
+	}, nil
+}
+
+// StartEnrollment creates a secret for the authenticated user. It only takes
+// effect once ConfirmEnrollment receives a code generated from it.
+func (t *twoFactorService) StartEnrollment(ctx context.Context) (*models.TwoFactorEnrollmentResponse, error) {
+	user, err := t.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.CanUseTwoFactor() {
+		return nil, models.ErrTwoFactorNotAvailable
+	}
+
+	if user.TwoFactorEnabled() {
+		return nil, models.ErrTwoFactorAlreadyEnabled
+	}
+
+	return t.BeginEnrollment(ctx, *user)
+}
+
+func (t *twoFactorService) ConfirmEnrollment(ctx context.Context, payload models.TwoFactorCodePayload) (*models.RecoveryCodesResponse, error) {
+	user, err := t.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled() {
+		return nil, models.ErrTwoFactorAlreadyEnabled
+	}
+
+	var codes []string
+	if err := t.checkCode(ctx, user, func() error {
+		codes, err = t.CompleteEnrollment(ctx, user, payload.Code)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off after checking a code,
+// unless the role of the user requires it.
+func (t *twoFactorService) DisableTwoFactor(ctx context.Context, payload models.TwoFactorCodePayload) error {
+	user, err := t.getAuthenticatedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !user.TwoFactorEnabled() {
+		return models.ErrTwoFactorNotEnabled
+	}
+
+	if twoFactorRequired(user.Role) {
+		return models.ErrTwoFactorRequired
+	}
+
+	if err := t.checkCode(ctx, user, func() error {
+		return t.VerifyCode(ctx, user, payload.Code)
+	}); err != nil {
+		return err
+	}
+
+	user.DisableTwoFactor()
+
+	return t.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := t.ur.UpdateUser(ctx, *user); err != nil {
+			return fmt.Errorf("update user %q: %w", user.ID, err)
+		}
+
+		if err := t.rcr.DeleteRecoveryCodesByUserID(ctx, user.ID); err != nil {
+			return fmt.Errorf("delete recovery codes of user %q: %w", user.ID, err)
+		}
+
+		return t.audit(ctx, models.TwoFactorDisabledAction, user.ID)
+	})
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the authenticated
+// user, used or not.
+func (t *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, payload models.TwoFactorCodePayload) (*models.RecoveryCodesResponse, error) {
+	user, err := t.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TwoFactorEnabled() {
+		return nil, models.ErrTwoFactorNotEnabled
+	}
+
+	if err := t.checkCode(ctx, user, func() error {
+		return t.VerifyCode(ctx, user, payload.Code)
+	}); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = t.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		codes, err = t.replaceRecoveryCodes(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// BeginEnrollment keeps a new secret pending for the user and returns it with
+// the URI to show as a QR code. Starting again replaces the pending secret.
+func (t *twoFactorService) BeginEnrollment(ctx context.Context, user models.User) (*models.TwoFactorEnrollmentResponse, error) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("generate TOTP secret: %w", err)
+	}
+
+	ttl := time.Duration(config.Env.TwoFactor.EnrollmentExp) * time.Minute
+	if err := t.c.Set(ctx, twoFactorEnrollmentKey(user.ID), secret, ttl); err != nil {
+		return nil, fmt.Errorf("store pending TOTP secret: %w", err)
+	}
+
+	return &models.TwoFactorEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(config.Env.TwoFactor.Issuer, user.Email, secret),
+	}, nil
+}
+
+// CompleteEnrollment enables two-factor authentication once the code proves
+// the authenticator holds the pending secret, and returns the recovery codes.
+// They are shown this one time; only their hashes are kept.
+func (t *twoFactorService) CompleteEnrollment(ctx context.Context, user *models.User, code string) ([]string, error) {
+	var secret string
+	if err := t.c.Get(ctx, twoFactorEnrollmentKey(user.ID), &secret); err != nil {
+		if errors.Is(err, storage.ErrCacheMiss) {
+			return nil, models.ErrTwoFactorEnrollmentNotFound
+		}
+
+		return nil, fmt.Errorf("get pending TOTP secret: %w", err)
+	}
+
+	now := t.now().UTC()
+
+	step, ok := utils.ValidateTOTP(secret, code, now, totpSkew)
+	if !ok {
+		return nil, models.ErrInvalidTwoFactorCode
+	}
+
+	user.EnableTwoFactor(secret, step, now)
+
+	var codes []string
+	err := t.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := t.ur.UpdateUser(ctx, *user); err != nil {
+			return fmt.Errorf("update user %q: %w", user.ID, err)
+		}
+
+		var err error
+		codes, err = t.replaceRecoveryCodes(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		return t.audit(ctx, models.TwoFactorEnabledAction, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.c.Delete(ctx, twoFactorEnrollmentKey(user.ID)); err != nil {
+		return nil, fmt.Errorf("delete pending TOTP secret: %w", err)
+	}
+
+	return codes, nil
+}
+
+// VerifyCode accepts a TOTP code or an unused recovery code. Each TOTP step
+// and each recovery code work once.
+func (t *twoFactorService) VerifyCode(ctx context.Context, user *models.User, code string) error {
+	if !user.TwoFactorEnabled() || user.TwoFactorSecret == nil {
+		return models.ErrTwoFactorNotEnabled
+	}
+
+	now := t.now().UTC()
+
+	if step, ok := utils.ValidateTOTP(*user.TwoFactorSecret, code, now, totpSkew); ok {
+		if !user.UseTwoFactorStep(step) {
+			return models.ErrInvalidTwoFactorCode
+		}
+
+		// The check above only saw this copy of the user; the database decides
+		// which of concurrent requests gets the step.
+		used, err := t.ur.UseTwoFactorStep(ctx, user.ID, step)
+		if err != nil {
+			return fmt.Errorf("use two-factor step of user %q: %w", user.ID, err)
+		}
+
+		if !used {
+			return models.ErrInvalidTwoFactorCode
+		}
+
+		return nil
+	}
+
+	return t.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		recoveryCode, err := t.rcr.GetUnusedRecoveryCode(ctx, user.ID, models.HashRecoveryCode(code))
+		if err != nil {
+			return fmt.Errorf("get recovery code: %w", err)
+		}
+
+		if recoveryCode == nil {
+			return models.ErrInvalidTwoFactorCode
+		}
+
+		recoveryCode.Use(now)
+
+		if err := t.rcr.UpdateRecoveryCode(ctx, *recoveryCode); err != nil {
+			return fmt.Errorf("update recovery code %q: %w", recoveryCode.ID, err)
+		}
+
+		return t.audit(ctx, models.RecoveryCodeUsedAction, user.ID)
+	})
+}
+
+func (t *twoFactorService) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	now := t.now().UTC()
+	codes := make([]string, recoveryCodeCount)
+	recoveryCodes := make([]models.RecoveryCode, recoveryCodeCount)
+
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+
+		codes[i] = code
+		recoveryCodes[i] = *models.NewRecoveryCode(userID, code, now)
+	}
+
+	if err := t.rcr.DeleteRecoveryCodesByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("delete recovery codes of user %q: %w", userID, err)
+	}
+
+	if err := t.rcr.CreateRecoveryCodes(ctx, recoveryCodes); err != nil {
+		return nil, fmt.Errorf("create recovery codes of user %q: %w", userID, err)
+	}
+
+	return codes, nil
+}
+
+func (t *twoFactorService) audit(ctx context.Context, action models.AuditAction, userID uuid.UUID) error {
+	ip, userAgent := request.ClientInfo(ctx)
+
+	if err := t.alr.CreateAuditLog(ctx, *models.NewAuditLog(action, &userID, ip, userAgent, nil)); err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (t *twoFactorService) getAuthenticatedUser(ctx context.Context) (*models.User, error) {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return nil, models.ErrUserNotFoundInContext
+	}
+
+	user, err := t.ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", userID, err)
+	}
+
+	if user == nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// twoFactorRequired reports whether the configuration makes two-factor
+// authentication mandatory for the role.
+func twoFactorRequired(role models.Role) bool {
+	return slices.Contains(config.Env.TwoFactor.RequiredRoles, string(role))
+}
+
+// checkCode runs a code check made by an authenticated user. Wrong codes
+// count as failed logins of the user, so codes cannot be guessed with a
+// stolen session any faster than on login.
+func (t *twoFactorService) checkCode(ctx context.Context, user *models.User, check func() error) error {
+	if err := t.lg.CheckCPF(ctx, user.CPF); err != nil {
+		return err
+	}
+
+	if err := check(); err != nil {
+		if errors.Is(err, models.ErrInvalidTwoFactorCode) {
+			ip, _ := request.ClientInfo(ctx)
+			if _, err := t.lg.RegisterFailure(ctx, user.CPF, ip); err != nil {
+				return err
+			}
+		}
+
+		return err
+	}
+
+	return t.lg.Reset(ctx, user.CPF)
+}
+
+// generateRecoveryCode returns a code such as "k7m2p-x9qrt". The alphabet
+// leaves out characters that are easy to confuse.
+func generateRecoveryCode() (string, error) {
+	const letters = "abcdefghjkmnpqrstuvwxyz23456789"
+	size := big.NewInt(int64(len(letters)))
+
+	b := make([]byte, 10)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", err
+		}
+
+		b[i] = letters[n.Int64()]
+	}
+
+	return string(b[:5]) + "-" + string(b[5:]), nil
+}
+
+func twoFactorEnrollmentKey(userID uuid.UUID) string {
+	return fmt.Sprintf("two-factor:enrollment:%s", userID)
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+	"github.com/G-Villarinho/fast-feet-api/mocks"
+	"github.com/G-Villarinho/fast-feet-api/models"
+	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/storage"
+	"github.com/G-Villarinho/fast-feet-api/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTransactionManager() *mocks.TransactionManager {
+	transactionManagerMock := new(mocks.TransactionManager)
+	transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+	return transactionManagerMock
+}
+
+func TestTwoFactorService_CompleteEnrollment(t *testing.T) {
+	now := time.Date(2025, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	t.Run("WhenCodeMatchesPendingSecret_ShouldEnableAndStoreHashedRecoveryCodes", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		recoveryCodeRepoMock := new(mocks.RecoveryCodeRepository)
+		auditLogRepoMock := new(mocks.AuditLogRepository)
+		cacheMock := new(mocks.Cache)
+
+		service := &twoFactorService{
+			ur:  userRepoMock,
+			rcr: recoveryCodeRepoMock,
+			alr: auditLogRepoMock,
+			tm:  newTransactionManager(),
+			c:   cacheMock,
+			now: func() time.Time { return now },
+		}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.Admin}
+		enrollmentKey := "two-factor:enrollment:" + user.ID.String()
+		code, _ := utils.TOTPCode(testTOTPSecret, utils.TOTPStep(now))
+
+		cacheMock.On("Get", mock.Anything, enrollmentKey, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*string) = testTOTPSecret
+			}).
+			Return(nil)
+
+		userRepoMock.On("UpdateUser", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+			return u.TwoFactorEnabled() && *u.TwoFactorSecret == testTOTPSecret && u.TwoFactorLastStep == utils.TOTPStep(now)
+		})).Return(nil)
+
+		var stored []models.RecoveryCode
+		recoveryCodeRepoMock.On("DeleteRecoveryCodesByUserID", mock.Anything, user.ID).Return(nil)
+		recoveryCodeRepoMock.On("CreateRecoveryCodes", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				stored = args.Get(1).([]models.RecoveryCode)
+			}).
+			Return(nil)
+
+		auditLogRepoMock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l models.AuditLog) bool {
+			return l.Action == models.TwoFactorEnabledAction && *l.UserID == user.ID
+		})).Return(nil)
+
+		cacheMock.On("Delete", mock.Anything, enrollmentKey).Return(nil)
+
+		codes, err := service.CompleteEnrollment(context.Background(), user, code)
+
+		assert.NoError(t, err)
+		assert.Len(t, codes, recoveryCodeCount)
+		assert.Len(t, stored, recoveryCodeCount)
+		assert.Equal(t, models.HashRecoveryCode(codes[0]), stored[0].CodeHash)
+		assert.NotContains(t, stored[0].CodeHash, codes[0])
+		userRepoMock.AssertExpectations(t)
+		recoveryCodeRepoMock.AssertExpectations(t)
+		auditLogRepoMock.AssertExpectations(t)
+		cacheMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenEnrollmentExpired_ShouldReturnErrTwoFactorEnrollmentNotFound", func(t *testing.T) {
+		cacheMock := new(mocks.Cache)
+		service := &twoFactorService{c: cacheMock, now: func() time.Time { return now }}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}
+
+		cacheMock.On("Get", mock.Anything, "two-factor:enrollment:"+user.ID.String(), mock.Anything).
+			Return(storage.ErrCacheMiss)
+
+		codes, err := service.CompleteEnrollment(context.Background(), user, "123456")
+
+		assert.Nil(t, codes)
+		assert.ErrorIs(t, err, models.ErrTwoFactorEnrollmentNotFound)
+		assert.False(t, user.TwoFactorEnabled())
+	})
+}
+
+func TestTwoFactorService_ConfirmEnrollment(t *testing.T) {
+	now := time.Date(2025, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	t.Run("WhenCodeIsWrong_ShouldCountFailureOfUser", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		loginGuardMock := new(mocks.LoginGuard)
+		cacheMock := new(mocks.Cache)
+		service := &twoFactorService{ur: userRepoMock, lg: loginGuardMock, c: cacheMock, now: func() time.Time { return now }}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, CPF: "12345678900", Role: models.Admin}
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+		cacheMock.On("Get", mock.Anything, "two-factor:enrollment:"+user.ID.String(), mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*string) = testTOTPSecret
+			}).
+			Return(nil)
+
+		loginGuardMock.On("CheckCPF", mock.Anything, user.CPF).Return(nil)
+		loginGuardMock.On("RegisterFailure", mock.Anything, user.CPF, "203.0.113.42").Return(1, nil)
+
+		ctx := request.WithClientInfo(request.WithUserID(context.Background(), user.ID), "203.0.113.42", "Mozilla/5.0")
+		response, err := service.ConfirmEnrollment(ctx, models.TwoFactorCodePayload{Code: "000000"})
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrInvalidTwoFactorCode)
+		assert.False(t, user.TwoFactorEnabled())
+		loginGuardMock.AssertExpectations(t)
+		loginGuardMock.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenUserFailedTooManyTimes_ShouldNotCheckTheCode", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		loginGuardMock := new(mocks.LoginGuard)
+		cacheMock := new(mocks.Cache)
+		service := &twoFactorService{ur: userRepoMock, lg: loginGuardMock, c: cacheMock, now: func() time.Time { return now }}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, CPF: "12345678900", Role: models.Admin}
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+		loginGuardMock.On("CheckCPF", mock.Anything, user.CPF).Return(models.ErrTooManyCodeAttempts)
+
+		code, _ := utils.TOTPCode(testTOTPSecret, utils.TOTPStep(now))
+		response, err := service.ConfirmEnrollment(request.WithUserID(context.Background(), user.ID), models.TwoFactorCodePayload{Code: code})
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrTooManyCodeAttempts)
+		cacheMock.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+		loginGuardMock.AssertNotCalled(t, "RegisterFailure", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	t.Run("ShouldUseOnlyTheUnambiguousAlphabet", func(t *testing.T) {
+		for range 100 {
+			code, err := generateRecoveryCode()
+
+			assert.NoError(t, err)
+			assert.Regexp(t, `^[a-hjkmnp-z2-9]{5}-[a-hjkmnp-z2-9]{5}$`, code)
+		}
+	})
+}
+
+func TestTwoFactorService_VerifyCode(t *testing.T) {
+	now := time.Date(2025, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	newUser := func() *models.User {
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.Admin}
+		user.EnableTwoFactor(testTOTPSecret, utils.TOTPStep(now)-10, now)
+		return user
+	}
+
+	t.Run("WhenTOTPCodeIsValid_ShouldRecordItsStep", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := &twoFactorService{ur: userRepoMock, now: func() time.Time { return now }}
+
+		user := newUser()
+		code, _ := utils.TOTPCode(testTOTPSecret, utils.TOTPStep(now))
+
+		userRepoMock.On("UseTwoFactorStep", mock.Anything, user.ID, utils.TOTPStep(now)).Return(true, nil)
+
+		err := service.VerifyCode(context.Background(), user, code)
+
+		assert.NoError(t, err)
+		userRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenTOTPStepIsUsedConcurrently_ShouldReturnErrInvalidTwoFactorCode", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := &twoFactorService{ur: userRepoMock, now: func() time.Time { return now }}
+
+		user := newUser()
+		code, _ := utils.TOTPCode(testTOTPSecret, utils.TOTPStep(now))
+
+		userRepoMock.On("UseTwoFactorStep", mock.Anything, user.ID, utils.TOTPStep(now)).Return(false, nil)
+
+		err := service.VerifyCode(context.Background(), user, code)
+
+		assert.ErrorIs(t, err, models.ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("WhenTOTPCodeWasAlreadyUsed_ShouldReturnErrInvalidTwoFactorCode", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := &twoFactorService{ur: userRepoMock, now: func() time.Time { return now }}
+
+		user := newUser()
+		user.TwoFactorLastStep = utils.TOTPStep(now)
+		code, _ := utils.TOTPCode(testTOTPSecret, utils.TOTPStep(now))
+
+		err := service.VerifyCode(context.Background(), user, code)
+
+		assert.ErrorIs(t, err, models.ErrInvalidTwoFactorCode)
+		userRepoMock.AssertNotCalled(t, "UseTwoFactorStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenRecoveryCodeIsUnused_ShouldConsumeItAndAudit", func(t *testing.T) {
+		recoveryCodeRepoMock := new(mocks.RecoveryCodeRepository)
+		auditLogRepoMock := new(mocks.AuditLogRepository)
+
+		service := &twoFactorService{
+			rcr: recoveryCodeRepoMock,
+			alr: auditLogRepoMock,
+			tm:  newTransactionManager(),
+			now: func() time.Time { return now },
+		}
+
+		user := newUser()
+		recoveryCode := models.NewRecoveryCode(user.ID, "abcde-fghjk", now)
+
+		recoveryCodeRepoMock.On("GetUnusedRecoveryCode", mock.Anything, user.ID, models.HashRecoveryCode("ABCDEFGHJK")).
+			Return(recoveryCode, nil)
+		recoveryCodeRepoMock.On("UpdateRecoveryCode", mock.Anything, mock.MatchedBy(func(c models.RecoveryCode) bool {
+			return c.ID == recoveryCode.ID && c.UsedAt.Valid
+		})).Return(nil)
+
+		ctx := request.WithClientInfo(context.Background(), "203.0.113.42", "curl/8.0")
+		auditLogRepoMock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l models.AuditLog) bool {
+			return l.Action == models.RecoveryCodeUsedAction && l.IP == "203.0.113.42"
+		})).Return(nil)
+
+		err := service.VerifyCode(ctx, user, "ABCDEFGHJK")
+
+		assert.NoError(t, err)
+		recoveryCodeRepoMock.AssertExpectations(t)
+		auditLogRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WhenCodeMatchesNothing_ShouldReturnErrInvalidTwoFactorCode", func(t *testing.T) {
+		recoveryCodeRepoMock := new(mocks.RecoveryCodeRepository)
+
+		service := &twoFactorService{
+			rcr: recoveryCodeRepoMock,
+			tm:  newTransactionManager(),
+			now: func() time.Time { return now },
+		}
+
+		user := newUser()
+
+		recoveryCodeRepoMock.On("GetUnusedRecoveryCode", mock.Anything, user.ID, mock.Anything).
+			Return(nil, nil)
+
+		err := service.VerifyCode(context.Background(), user, "000000")
+
+		assert.ErrorIs(t, err, models.ErrInvalidTwoFactorCode)
+	})
+}
+
+func TestTwoFactorService_DisableTwoFactor(t *testing.T) {
+	t.Run("WhenRoleRequiresTwoFactor_ShouldReturnErrTwoFactorRequired", func(t *testing.T) {
+		config.Env.TwoFactor = config.TwoFactor{RequiredRoles: []string{string(models.Owner)}}
+		defer func() { config.Env.TwoFactor = config.TwoFactor{} }()
+
+		userRepoMock := new(mocks.UserRepository)
+		service := &twoFactorService{ur: userRepoMock, now: time.Now}
+
+		user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Role: models.Owner}
+		user.EnableTwoFactor(testTOTPSecret, 0, time.Now())
+
+		userRepoMock.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+		ctx := request.WithUserID(context.Background(), user.ID)
+		err := service.DisableTwoFactor(ctx, models.TwoFactorCodePayload{Code: "123456"})
+
+		assert.ErrorIs(t, err, models.ErrTwoFactorRequired)
+		assert.True(t, user.TwoFactorEnabled())
+		userRepoMock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+}
@@ -3,11 +3,18 @@ package services
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
 
+	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/di"
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/G-Villarinho/fast-feet-api/repositories"
 	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/services/email"
+	"github.com/G-Villarinho/fast-feet-api/utils"
+	"github.com/google/uuid"
 )
 
 //go:generate mockery --name=UserService --filename=user_service.go --output=../mocks --outpkg=mocks
@@ -15,12 +22,25 @@ type UserService interface {
 	CreateAdmin(ctx context.Context, payload models.CreateUserPayload) error
 	CreateDeliveryMan(ctx context.Context, payload models.CreateUserPayload) error
 	GetUser(ctx context.Context) (*models.UserResponse, error)
+	GetDeliveryMen(ctx context.Context, pagination *models.DeliveryManPagination) (*models.PaginatedResponse[*models.DeliveryManResponse], error)
+	GetDeliveryMan(ctx context.Context, deliveryManID uuid.UUID) (*models.DeliveryManResponse, error)
+	UpdateDeliveryMan(ctx context.Context, deliveryManID uuid.UUID, payload models.UpdateUserPayload) (*models.DeliveryManResponse, error)
+	DeactivateDeliveryMan(ctx context.Context, deliveryManID uuid.UUID) error
+	BlockUser(ctx context.Context, userID uuid.UUID, payload models.BlockUserPayload) error
+	UnblockUser(ctx context.Context, userID uuid.UUID) error
+	GetDeliveryManOrders(ctx context.Context, deliveryManID uuid.UUID, pagination *models.Pagination) (*models.PaginatedResponse[*models.OrderResponse], error)
 }
 
 type userService struct {
-	i  *di.Injector
-	ss SecureService
-	ur repositories.UserRepository
+	i   *di.Injector
+	ss  SecureService
+	ur  repositories.UserRepository
+	or  repositories.OrderRepository
+	atr repositories.ActivationTokenRepository
+	eor repositories.EmailOutboxRepository
+	alr repositories.AuditLogRepository
+	tm  repositories.TransactionManager
+	ef  *email.EmailFactory
 }
 
 func NewUserService(i *di.Injector) (UserService, error) {
@@ -34,10 +54,41 @@ func NewUserService(i *di.Injector) (UserService, error) {
 		return nil, fmt.Errorf("invoke user repository: %w", err)
 	}
 
+	or, err := di.Invoke[repositories.OrderRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke order repository: %w", err)
+	}
+
+	atr, err := di.Invoke[repositories.ActivationTokenRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke activation token repository: %w", err)
+	}
+
+	eor, err := di.Invoke[repositories.EmailOutboxRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke email outbox repository: %w", err)
+	}
+
+	alr, err := di.Invoke[repositories.AuditLogRepository](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke audit log repository: %w", err)
+	}
+
+	tm, err := di.Invoke[repositories.TransactionManager](i)
+	if err != nil {
+		return nil, fmt.Errorf("invoke transaction manager: %w", err)
+	}
+
 	return &userService{
-		i:  i,
-		ur: ur,
-		ss: ss,
+		i:   i,
+		ur:  ur,
+		or:  or,
+		ss:  ss,
+		atr: atr,
+		eor: eor,
+		alr: alr,
+		tm:  tm,
+		ef:  email.NewEmailFactory(),
 	}, nil
 }
 
@@ -75,6 +126,231 @@ func (u *userService) GetUser(ctx context.Context) (*models.UserResponse, error)
 	return user.ToUserResponse(), nil
 }
 
+func (u *userService) GetDeliveryMen(ctx context.Context, pagination *models.DeliveryManPagination) (*models.PaginatedResponse[*models.DeliveryManResponse], error) {
+	if _, err := authorizeUser(ctx, u.ur, models.Read, models.Users); err != nil {
+		return nil, err
+	}
+
+	paginatedDeliveryMen, err := u.ur.GetDeliveryMenPagedList(ctx, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("get paginated delivery men: %w", err)
+	}
+
+	return models.MapPaginatedResult(paginatedDeliveryMen, func(user models.User) *models.DeliveryManResponse {
+		return user.ToDeliveryManResponse()
+	}), nil
+}
+
+func (u *userService) GetDeliveryMan(ctx context.Context, deliveryManID uuid.UUID) (*models.DeliveryManResponse, error) {
+	if _, err := authorizeUser(ctx, u.ur, models.Read, models.Users); err != nil {
+		return nil, err
+	}
+
+	deliveryMan, err := u.getDeliveryMan(ctx, deliveryManID)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveryMan.ToDeliveryManResponse(), nil
+}
+
+func (u *userService) UpdateDeliveryMan(ctx context.Context, deliveryManID uuid.UUID, payload models.UpdateUserPayload) (*models.DeliveryManResponse, error) {
+	if _, err := authorizeUser(ctx, u.ur, models.Update, models.Users); err != nil {
+		return nil, err
+	}
+
+	deliveryMan, err := u.getDeliveryMan(ctx, deliveryManID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Email != deliveryMan.Email {
+		userWithSameEmail, err := u.ur.GetUserByEmail(ctx, payload.Email)
+		if err != nil {
+			return nil, fmt.Errorf("get user by email: %w", err)
+		}
+
+		if userWithSameEmail != nil {
+			return nil, models.ErrEmailAlreadyExists
+		}
+	}
+
+	if cpf := utils.RemoveCPFFormat(payload.CPF); cpf != deliveryMan.CPF {
+		userWithSameCPF, err := u.ur.GetUserByCPF(ctx, cpf)
+		if err != nil {
+			return nil, fmt.Errorf("get user by CPF: %w", err)
+		}
+
+		if userWithSameCPF != nil {
+			return nil, models.ErrCPFAlreadyExists
+		}
+	}
+
+	deliveryMan.ApplyUpdates(&payload)
+
+	if err := u.ur.UpdateUser(ctx, *deliveryMan); err != nil {
+		return nil, fmt.Errorf("update delivery man %q: %w", deliveryManID, err)
+	}
+
+	return deliveryMan.ToDeliveryManResponse(), nil
+}
+
+// DeactivateDeliveryMan soft deletes the delivery man, which prevents them
+// from logging in. Orders still in their hands must be finished first.
+func (u *userService) DeactivateDeliveryMan(ctx context.Context, deliveryManID uuid.UUID) error {
+	if _, err := authorizeUser(ctx, u.ur, models.Delete, models.Users); err != nil {
+		return err
+	}
+
+	return u.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		// The lock keeps the delivery man from picking up an order between
+		// the count and the delete.
+		deliveryMan, err := u.ur.GetUserByIDForUpdate(ctx, deliveryManID)
+		if err != nil {
+			return fmt.Errorf("get user by id %q for update: %w", deliveryManID, err)
+		}
+
+		if deliveryMan == nil || deliveryMan.Role != models.DeliveryMan {
+			return models.ErrDeliveryManNotFound
+		}
+
+		activeOrders, err := u.or.CountDeliveryManOrders(ctx, deliveryManID, models.InProgressStatuses)
+		if err != nil {
+			return fmt.Errorf("count delivery man %q orders: %w", deliveryManID, err)
+		}
+
+		if activeOrders > 0 {
+			return models.ErrDeliveryManHasActiveOrders
+		}
+
+		if err := u.ur.DeleteUser(ctx, deliveryManID); err != nil {
+			return fmt.Errorf("delete delivery man %q: %w", deliveryManID, err)
+		}
+
+		return nil
+	})
+}
+
+func (u *userService) GetDeliveryManOrders(ctx context.Context, deliveryManID uuid.UUID, pagination *models.Pagination) (*models.PaginatedResponse[*models.OrderResponse], error) {
+	if _, err := authorizeUser(ctx, u.ur, models.Read, models.Users); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.getDeliveryMan(ctx, deliveryManID); err != nil {
+		return nil, err
+	}
+
+	paginatedOrders, err := u.or.GetDeliveryManOrdersPagedList(ctx, deliveryManID, models.InProgressStatuses, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("get delivery man %q orders: %w", deliveryManID, err)
+	}
+
+	return models.MapPaginatedResult(paginatedOrders, func(order models.Order) *models.OrderResponse {
+		return order.ToOrderResponse()
+	}), nil
+}
+
+func (u *userService) BlockUser(ctx context.Context, userID uuid.UUID, payload models.BlockUserPayload) error {
+	authUser, user, err := u.getManageableUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := user.Block(authUser.ID, payload.Reason, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("blocked by %s: %s", authUser.ID, payload.Reason)
+	if err := u.updateAndAudit(ctx, *user, models.UserBlockedAction, details); err != nil {
+		return err
+	}
+
+	slog.Info("user blocked",
+		slog.String("userId", userID.String()),
+		slog.String("blockedBy", authUser.ID.String()),
+	)
+
+	return nil
+}
+
+func (u *userService) UnblockUser(ctx context.Context, userID uuid.UUID) error {
+	authUser, user, err := u.getManageableUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := user.Unblock(); err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("unblocked by %s", authUser.ID)
+	if err := u.updateAndAudit(ctx, *user, models.UserUnblockedAction, details); err != nil {
+		return err
+	}
+
+	slog.Info("user unblocked",
+		slog.String("userId", userID.String()),
+		slog.String("unblockedBy", authUser.ID.String()),
+	)
+
+	return nil
+}
+
+// updateAndAudit saves the user and records who changed them, so the change
+// is never persisted without its audit entry.
+func (u *userService) updateAndAudit(ctx context.Context, user models.User, action models.AuditAction, details string) error {
+	ip, userAgent := request.ClientInfo(ctx)
+
+	return u.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.ur.UpdateUser(ctx, user); err != nil {
+			return fmt.Errorf("update user %q: %w", user.ID, err)
+		}
+
+		if err := u.alr.CreateAuditLog(ctx, *models.NewAuditLog(action, &user.ID, ip, userAgent, &details)); err != nil {
+			return fmt.Errorf("create audit log: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// getManageableUser returns the authenticated user and the user they want to
+// change. Only an owner can change another owner.
+func (u *userService) getManageableUser(ctx context.Context, userID uuid.UUID) (*models.User, *models.User, error) {
+	authUser, err := authorizeUser(ctx, u.ur, models.Update, models.Users)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := u.ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get user by id %q: %w", userID, err)
+	}
+
+	if user == nil {
+		return nil, nil, models.ErrUserNotFound
+	}
+
+	if user.Role == models.Owner && authUser.Role != models.Owner {
+		return nil, nil, models.ErrInsufficientPermission
+	}
+
+	return authUser, user, nil
+}
+
+func (u *userService) getDeliveryMan(ctx context.Context, deliveryManID uuid.UUID) (*models.User, error) {
+	deliveryMan, err := u.ur.GetUserByID(ctx, deliveryManID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", deliveryManID, err)
+	}
+
+	if deliveryMan == nil || deliveryMan.Role != models.DeliveryMan {
+		return nil, models.ErrDeliveryManNotFound
+	}
+
+	return deliveryMan, nil
+}
+
 func (u *userService) createUser(ctx context.Context, payload models.CreateUserPayload, role models.Role) error {
 	userID, found := request.UserID(ctx)
 	if !found {
@@ -108,6 +384,8 @@ func (u *userService) createUser(ctx context.Context, payload models.CreateUserP
 		return models.ErrCPFAlreadyExists
 	}
 
+	// The generated password is never disclosed: the user defines their own
+	// through the activation link sent in the welcome email.
 	password, err := u.ss.CreatePassword(ctx)
 	if err != nil {
 		return fmt.Errorf("create password: %w", err)
@@ -118,11 +396,64 @@ func (u *userService) createUser(ctx context.Context, payload models.CreateUserP
 		return fmt.Errorf("hash password: %w", err)
 	}
 
+	token, err := u.ss.CreateToken(ctx)
+	if err != nil {
+		return fmt.Errorf("create activation token: %w", err)
+	}
+
 	user := payload.ToUser(passwordHash, role)
+	activationToken := models.NewActivationToken(user.ID, token, time.Now().UTC(), time.Duration(config.Env.Activation.TokenExp)*time.Hour)
 
-	if err := u.ur.CreateUser(ctx, *user); err != nil {
-		return fmt.Errorf("create user: %w", err)
+	return u.tm.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := u.ur.CreateUser(ctx, *user); err != nil {
+			return fmt.Errorf("create user: %w", err)
+		}
+
+		if err := u.atr.CreateActivationToken(ctx, *activationToken); err != nil {
+			return fmt.Errorf("create activation token: %w", err)
+		}
+
+		return u.sendWelcomeEmail(ctx, *user, token, activationToken.ExpiresAt)
+	})
+}
+
+func (u *userService) sendWelcomeEmail(ctx context.Context, user models.User, token string, expiresAt time.Time) error {
+	activationURL := fmt.Sprintf("%s?token=%s", config.Env.Activation.URL, url.QueryEscape(token))
+
+	sendEmailPayload := u.ef.CreateWelcomeSendEmail(user.Email, "Bem-vindo ao Fast Feet", user.FullName, user.RoleLabel(), activationURL, expiresAt)
+
+	email, err := models.NewEmailOutbox(sendEmailPayload)
+	if err != nil {
+		return err
+	}
+
+	if err := u.eor.CreateEmailOutbox(ctx, *email); err != nil {
+		return fmt.Errorf("queue welcome email for user %q: %w", user.ID, err)
 	}
 
 	return nil
 }
+
+// authorizeUser loads the authenticated user and checks that their role may
+// perform action on resource.
+func authorizeUser(ctx context.Context, ur repositories.UserRepository, action models.Action, resource models.Resource) (*models.User, error) {
+	userID, found := request.UserID(ctx)
+	if !found {
+		return nil, models.ErrUserNotFoundInContext
+	}
+
+	user, err := ur.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id %q: %w", userID, err)
+	}
+
+	if user == nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	if models.Cannot(user.Role, action, resource) {
+		return nil, models.ErrInsufficientPermission
+	}
+
+	return user, nil
+}
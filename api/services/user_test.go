@@ -3,16 +3,43 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/G-Villarinho/fast-feet-api/config"
 	"github.com/G-Villarinho/fast-feet-api/mocks"
 	"github.com/G-Villarinho/fast-feet-api/models"
 	"github.com/G-Villarinho/fast-feet-api/request"
+	"github.com/G-Villarinho/fast-feet-api/services/email"
+	"github.com/G-Villarinho/fast-feet-api/templates"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+func newCreateUserService() (*userService, *mocks.UserRepository, *mocks.SecureService) {
+	config.Env.Activation = config.Activation{URL: "http://localhost:5173/activate", TokenExp: 48}
+
+	userRepoMock := new(mocks.UserRepository)
+	secureServiceMock := new(mocks.SecureService)
+	transactionManagerMock := new(mocks.TransactionManager)
+
+	transactionManagerMock.On("WithTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	return &userService{
+		ur:  userRepoMock,
+		ss:  secureServiceMock,
+		atr: new(mocks.ActivationTokenRepository),
+		eor: new(mocks.EmailOutboxRepository),
+		tm:  transactionManagerMock,
+		ef:  email.NewEmailFactory(),
+	}, userRepoMock, secureServiceMock
+}
+
 func TestUserService_CreateAdmin(t *testing.T) {
 	t.Run("WhenEmailAlreadyExists_ShouldReturnErrEmailAlreadyExists", func(t *testing.T) {
 		mockRepo := new(mocks.UserRepository)
@@ -76,17 +103,14 @@ func TestUserService_CreateAdmin(t *testing.T) {
 	})
 
 	t.Run("WhenUserIsValid_ShouldCreateSuccessfullyAndReturnNil", func(t *testing.T) {
-		userRepoMock := new(mocks.UserRepository)
-		secureServiceMock := new(mocks.SecureService)
-
-		service := userService{
-			ur: userRepoMock,
-			ss: secureServiceMock,
-		}
+		service, userRepoMock, secureServiceMock := newCreateUserService()
+		activationTokenRepoMock := service.atr.(*mocks.ActivationTokenRepository)
+		emailOutboxRepoMock := service.eor.(*mocks.EmailOutboxRepository)
 
 		payload := models.CreateUserPayload{
-			Email: "new@example.com",
-			CPF:   "12345678900",
+			FullName: "New Admin",
+			Email:    "new@example.com",
+			CPF:      "12345678900",
 		}
 
 		userID := uuid.New()
@@ -107,23 +131,36 @@ func TestUserService_CreateAdmin(t *testing.T) {
 		secureServiceMock.On("HashPassword", mock.Anything, mock.Anything).
 			Return("$2y$10$hash", nil)
 
-		userRepoMock.On("CreateUser", mock.Anything, mock.Anything).
-			Return(nil)
+		secureServiceMock.On("CreateToken", mock.Anything).
+			Return("activation-token", nil)
+
+		var createdUser models.User
+		userRepoMock.On("CreateUser", mock.Anything, mock.MatchedBy(func(user models.User) bool {
+			createdUser = user
+			return user.MustChangePassword && user.Role == models.Admin
+		})).Return(nil)
+
+		activationTokenRepoMock.On("CreateActivationToken", mock.Anything, mock.MatchedBy(func(token models.ActivationToken) bool {
+			return token.UserID == createdUser.ID && token.TokenHash == models.HashToken("activation-token")
+		})).Return(nil)
+
+		emailOutboxRepoMock.On("CreateEmailOutbox", mock.Anything, mock.MatchedBy(func(email models.EmailOutbox) bool {
+			return email.To == payload.Email &&
+				email.TemplateName == templates.WelcomeTemplate &&
+				strings.Contains(string(email.Params), "http://localhost:5173/activate?token=activation-token")
+		})).Return(nil)
 
 		err := service.CreateAdmin(ctx, payload)
 
 		assert.NoError(t, err)
 		userRepoMock.AssertExpectations(t)
+		activationTokenRepoMock.AssertExpectations(t)
+		emailOutboxRepoMock.AssertExpectations(t)
 	})
 
 	t.Run("WhenCreateUserFails_ShouldReturnError", func(t *testing.T) {
-		userRepoMock := new(mocks.UserRepository)
-		secureServiceMock := new(mocks.SecureService)
-
-		service := userService{
-			ur: userRepoMock,
-			ss: secureServiceMock,
-		}
+		service, userRepoMock, secureServiceMock := newCreateUserService()
+		emailOutboxRepoMock := service.eor.(*mocks.EmailOutboxRepository)
 
 		payload := models.CreateUserPayload{
 			Email: "new@example.com",
@@ -148,6 +185,9 @@ func TestUserService_CreateAdmin(t *testing.T) {
 		secureServiceMock.On("HashPassword", mock.Anything, mock.Anything).
 			Return("$2y$10$hash", nil)
 
+		secureServiceMock.On("CreateToken", mock.Anything).
+			Return("activation-token", nil)
+
 		userRepoMock.On("CreateUser", mock.Anything, mock.Anything).
 			Return(errors.New("failed to create user"))
 
@@ -156,6 +196,7 @@ func TestUserService_CreateAdmin(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create user")
 		userRepoMock.AssertExpectations(t)
+		emailOutboxRepoMock.AssertNotCalled(t, "CreateEmailOutbox", mock.Anything, mock.Anything)
 	})
 
 	t.Run("WhenUserHasNoPermission_ShouldReturnErrInsufficientPermission", func(t *testing.T) {
@@ -184,3 +225,260 @@ func TestUserService_CreateAdmin(t *testing.T) {
 		userRepoMock.AssertExpectations(t)
 	})
 }
+
+func TestUserService_GetDeliveryMan(t *testing.T) {
+	t.Run("WhenUserIsNotDeliveryMan_ShouldReturnErrDeliveryManNotFound", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := userService{ur: userRepoMock}
+
+		userID := uuid.New()
+		adminID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		userRepoMock.On("GetUserByID", mock.Anything, adminID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: adminID}, Role: models.Admin}, nil)
+
+		response, err := service.GetDeliveryMan(request.WithUserID(context.Background(), userID), adminID)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrDeliveryManNotFound)
+	})
+
+	t.Run("WhenUserIsDeliveryMan_ShouldReturnErrInsufficientPermission", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := userService{ur: userRepoMock}
+
+		userID := uuid.New()
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.DeliveryMan}, nil)
+
+		response, err := service.GetDeliveryMan(request.WithUserID(context.Background(), userID), uuid.New())
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrInsufficientPermission)
+	})
+}
+
+func TestUserService_UpdateDeliveryMan(t *testing.T) {
+	payload := models.UpdateUserPayload{
+		FullName: "John Doe",
+		Email:    "taken@example.com",
+		CPF:      "123.456.789-00",
+	}
+
+	t.Run("WhenEmailBelongsToAnotherUser_ShouldReturnErrEmailAlreadyExists", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := userService{ur: userRepoMock}
+
+		userID := uuid.New()
+		deliveryManID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		userRepoMock.On("GetUserByID", mock.Anything, deliveryManID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: deliveryManID}, Email: "john@example.com", CPF: "12345678900", Role: models.DeliveryMan}, nil)
+
+		userRepoMock.On("GetUserByEmail", mock.Anything, payload.Email).
+			Return(&models.User{}, nil)
+
+		response, err := service.UpdateDeliveryMan(request.WithUserID(context.Background(), userID), deliveryManID, payload)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, models.ErrEmailAlreadyExists)
+		userRepoMock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenPayloadIsValid_ShouldUpdateDeliveryMan", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := userService{ur: userRepoMock}
+
+		userID := uuid.New()
+		deliveryManID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		userRepoMock.On("GetUserByID", mock.Anything, deliveryManID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: deliveryManID}, Email: "john@example.com", CPF: "12345678900", Role: models.DeliveryMan}, nil)
+
+		userRepoMock.On("GetUserByEmail", mock.Anything, payload.Email).
+			Return(nil, nil)
+
+		userRepoMock.On("UpdateUser", mock.Anything, mock.MatchedBy(func(user models.User) bool {
+			return user.ID == deliveryManID && user.Email == payload.Email && user.CPF == "12345678900"
+		})).Return(nil)
+
+		response, err := service.UpdateDeliveryMan(request.WithUserID(context.Background(), userID), deliveryManID, payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "John Doe", response.FullName)
+		userRepoMock.AssertExpectations(t)
+	})
+}
+
+func TestUserService_DeactivateDeliveryMan(t *testing.T) {
+	t.Run("WhenDeliveryManWasAlreadyDeactivated_ShouldReturnErrDeliveryManNotFound", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		orderRepoMock := new(mocks.OrderRepository)
+		service := userService{ur: userRepoMock, or: orderRepoMock, tm: newTransactionManager()}
+
+		userID := uuid.New()
+		deliveryManID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		userRepoMock.On("GetUserByIDForUpdate", mock.Anything, deliveryManID).
+			Return(nil, nil)
+
+		err := service.DeactivateDeliveryMan(request.WithUserID(context.Background(), userID), deliveryManID)
+
+		assert.ErrorIs(t, err, models.ErrDeliveryManNotFound)
+		orderRepoMock.AssertNotCalled(t, "CountDeliveryManOrders", mock.Anything, mock.Anything, mock.Anything)
+		userRepoMock.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenDeliveryManHasOrdersInProgress_ShouldReturnErrDeliveryManHasActiveOrders", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		orderRepoMock := new(mocks.OrderRepository)
+		service := userService{ur: userRepoMock, or: orderRepoMock, tm: newTransactionManager()}
+
+		userID := uuid.New()
+		deliveryManID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		userRepoMock.On("GetUserByIDForUpdate", mock.Anything, deliveryManID).
+			Return(&models.User{Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("CountDeliveryManOrders", mock.Anything, deliveryManID, models.InProgressStatuses).
+			Return(int64(2), nil)
+
+		err := service.DeactivateDeliveryMan(request.WithUserID(context.Background(), userID), deliveryManID)
+
+		assert.ErrorIs(t, err, models.ErrDeliveryManHasActiveOrders)
+		userRepoMock.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenDeliveryManHasNoOrdersInProgress_ShouldDeleteUser", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		orderRepoMock := new(mocks.OrderRepository)
+		service := userService{ur: userRepoMock, or: orderRepoMock, tm: newTransactionManager()}
+
+		userID := uuid.New()
+		deliveryManID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{Role: models.Admin}, nil)
+
+		userRepoMock.On("GetUserByIDForUpdate", mock.Anything, deliveryManID).
+			Return(&models.User{Role: models.DeliveryMan}, nil)
+
+		orderRepoMock.On("CountDeliveryManOrders", mock.Anything, deliveryManID, models.InProgressStatuses).
+			Return(int64(0), nil)
+
+		userRepoMock.On("DeleteUser", mock.Anything, deliveryManID).
+			Return(nil)
+
+		err := service.DeactivateDeliveryMan(request.WithUserID(context.Background(), userID), deliveryManID)
+
+		assert.NoError(t, err)
+		userRepoMock.AssertExpectations(t)
+	})
+}
+
+func TestUserService_BlockUser(t *testing.T) {
+	payload := models.BlockUserPayload{Reason: "Comportamento inadequado"}
+
+	t.Run("WhenTargetIsOwnerAndUserIsAdmin_ShouldReturnErrInsufficientPermission", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		service := userService{ur: userRepoMock}
+
+		userID := uuid.New()
+		ownerID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.Admin}, nil)
+
+		userRepoMock.On("GetUserByID", mock.Anything, ownerID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: ownerID}, Role: models.Owner, Status: models.ActiveStatus}, nil)
+
+		err := service.BlockUser(request.WithUserID(context.Background(), userID), ownerID, payload)
+
+		assert.ErrorIs(t, err, models.ErrInsufficientPermission)
+		userRepoMock.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WhenUserIsAdmin_ShouldBlockDeliveryManAndAudit", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		auditLogRepoMock := new(mocks.AuditLogRepository)
+		service := userService{ur: userRepoMock, alr: auditLogRepoMock, tm: newTransactionManager()}
+
+		userID := uuid.New()
+		deliveryManID := uuid.New()
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.Admin}, nil)
+
+		userRepoMock.On("GetUserByID", mock.Anything, deliveryManID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: deliveryManID}, Role: models.DeliveryMan, Status: models.ActiveStatus}, nil)
+
+		userRepoMock.On("UpdateUser", mock.Anything, mock.MatchedBy(func(user models.User) bool {
+			return user.ID == deliveryManID &&
+				user.Status == models.BlockedStatus &&
+				*user.BlockReason == payload.Reason &&
+				*user.BlockedByID == userID
+		})).Return(nil)
+
+		auditLogRepoMock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l models.AuditLog) bool {
+			return l.Action == models.UserBlockedAction &&
+				*l.UserID == deliveryManID &&
+				l.IP == "203.0.113.7" &&
+				strings.Contains(*l.Details, userID.String()) &&
+				strings.Contains(*l.Details, payload.Reason)
+		})).Return(nil)
+
+		ctx := request.WithClientInfo(request.WithUserID(context.Background(), userID), "203.0.113.7", "test-agent")
+		err := service.BlockUser(ctx, deliveryManID, payload)
+
+		assert.NoError(t, err)
+		userRepoMock.AssertExpectations(t)
+		auditLogRepoMock.AssertExpectations(t)
+	})
+}
+
+func TestUserService_UnblockUser(t *testing.T) {
+	t.Run("WhenUserIsBlocked_ShouldUnblockAndAudit", func(t *testing.T) {
+		userRepoMock := new(mocks.UserRepository)
+		auditLogRepoMock := new(mocks.AuditLogRepository)
+		service := userService{ur: userRepoMock, alr: auditLogRepoMock, tm: newTransactionManager()}
+
+		userID := uuid.New()
+		deliveryManID := uuid.New()
+
+		deliveryMan := &models.User{BaseModel: models.BaseModel{ID: deliveryManID}, Role: models.DeliveryMan, Status: models.ActiveStatus}
+		assert.NoError(t, deliveryMan.Block(userID, "Comportamento inadequado", time.Now().UTC()))
+
+		userRepoMock.On("GetUserByID", mock.Anything, userID).
+			Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Role: models.Admin}, nil)
+		userRepoMock.On("GetUserByID", mock.Anything, deliveryManID).Return(deliveryMan, nil)
+		userRepoMock.On("UpdateUser", mock.Anything, mock.MatchedBy(func(user models.User) bool {
+			return user.ID == deliveryManID && user.Status == models.ActiveStatus
+		})).Return(nil)
+		auditLogRepoMock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(l models.AuditLog) bool {
+			return l.Action == models.UserUnblockedAction &&
+				*l.UserID == deliveryManID &&
+				strings.Contains(*l.Details, userID.String())
+		})).Return(nil)
+
+		err := service.UnblockUser(request.WithUserID(context.Background(), userID), deliveryManID)
+
+		assert.NoError(t, err)
+		userRepoMock.AssertExpectations(t)
+		auditLogRepoMock.AssertExpectations(t)
+	})
+}
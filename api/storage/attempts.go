@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AttemptCounter counts events per key inside a fixed window that starts with
+// the first event.
+//
+//go:generate mockery --name=AttemptCounter --filename=attempt_counter.go --output=../mocks --outpkg=mocks
+type AttemptCounter interface {
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+	Count(ctx context.Context, key string) (int64, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type redisAttemptCounter struct {
+	client *redis.Client
+}
+
+func NewRedisAttemptCounter(client *redis.Client) AttemptCounter {
+	return &redisAttemptCounter{client: client}
+}
+
+func (r *redisAttemptCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("incr %q: %w", key, err)
+	}
+
+	return incr.Val(), nil
+}
+
+func (r *redisAttemptCounter) Count(ctx context.Context, key string) (int64, error) {
+	count, err := r.client.Get(ctx, key).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("get %q: %w", key, err)
+	}
+
+	return count, nil
+}
+
+func (r *redisAttemptCounter) Reset(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("delete %q: %w", key, err)
+	}
+
+	return nil
+}
+
+type attempts struct {
+	count     int64
+	expiresAt time.Time
+}
+
+type memoryAttemptCounter struct {
+	mu      sync.Mutex
+	entries map[string]attempts
+	now     func() time.Time
+}
+
+// NewMemoryAttemptCounter keeps counters in the process. It does not share
+// state between instances, so it only serves as a fallback.
+func NewMemoryAttemptCounter() AttemptCounter {
+	return &memoryAttemptCounter{
+		entries: make(map[string]attempts),
+		now:     time.Now,
+	}
+}
+
+func (m *memoryAttemptCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	entry, found := m.entries[key]
+	if !found {
+		entry = attempts{expiresAt: now.Add(window)}
+	}
+
+	entry.count++
+	m.entries[key] = entry
+
+	return entry.count, nil
+}
+
+func (m *memoryAttemptCounter) Count(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, found := m.entries[key]
+	if !found || !m.now().Before(entry.expiresAt) {
+		return 0, nil
+	}
+
+	return entry.count, nil
+}
+
+func (m *memoryAttemptCounter) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+
+	return nil
+}
+
+func (m *memoryAttemptCounter) sweep(now time.Time) {
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+type fallbackAttemptCounter struct {
+	primary  AttemptCounter
+	fallback AttemptCounter
+}
+
+// NewFallbackAttemptCounter uses primary and switches to fallback for every
+// call primary fails, so an unavailable Redis does not turn off brute-force
+// protection.
+func NewFallbackAttemptCounter(primary, fallback AttemptCounter) AttemptCounter {
+	return &fallbackAttemptCounter{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (f *fallbackAttemptCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	count, err := f.primary.Increment(ctx, key, window)
+	if err != nil {
+		slog.Warn("attempt counter unavailable, using fallback", slog.String("error", err.Error()))
+		return f.fallback.Increment(ctx, key, window)
+	}
+
+	return count, nil
+}
+
+func (f *fallbackAttemptCounter) Count(ctx context.Context, key string) (int64, error) {
+	count, err := f.primary.Count(ctx, key)
+	if err != nil {
+		slog.Warn("attempt counter unavailable, using fallback", slog.String("error", err.Error()))
+		return f.fallback.Count(ctx, key)
+	}
+
+	return count, nil
+}
+
+func (f *fallbackAttemptCounter) Reset(ctx context.Context, key string) error {
+	if err := f.primary.Reset(ctx, key); err != nil {
+		slog.Warn("attempt counter unavailable, using fallback", slog.String("error", err.Error()))
+	}
+
+	return f.fallback.Reset(ctx, key)
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryAttemptCounter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("WhenWindowExpires_ShouldStartCountingAgain", func(t *testing.T) {
+		now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+		counter := &memoryAttemptCounter{
+			entries: make(map[string]attempts),
+			now:     func() time.Time { return now },
+		}
+
+		for i := 1; i <= 3; i++ {
+			count, err := counter.Increment(ctx, "key", time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(i), count)
+		}
+
+		now = now.Add(time.Minute)
+
+		count, err := counter.Count(ctx, "key")
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+
+		count, err = counter.Increment(ctx, "key", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("WhenReset_ShouldForgetKey", func(t *testing.T) {
+		counter := NewMemoryAttemptCounter()
+
+		_, err := counter.Increment(ctx, "key", time.Minute)
+		assert.NoError(t, err)
+		assert.NoError(t, counter.Reset(ctx, "key"))
+
+		count, err := counter.Count(ctx, "key")
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
+
+type failingAttemptCounter struct{}
+
+func (failingAttemptCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (failingAttemptCounter) Count(ctx context.Context, key string) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (failingAttemptCounter) Reset(ctx context.Context, key string) error {
+	return errors.New("connection refused")
+}
+
+func TestFallbackAttemptCounter(t *testing.T) {
+	t.Run("WhenPrimaryFails_ShouldKeepCountingInFallback", func(t *testing.T) {
+		ctx := context.Background()
+		counter := NewFallbackAttemptCounter(failingAttemptCounter{}, NewMemoryAttemptCounter())
+
+		_, err := counter.Increment(ctx, "key", time.Minute)
+		assert.NoError(t, err)
+
+		count, err := counter.Increment(ctx, "key", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		count, err = counter.Count(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
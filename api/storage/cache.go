@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrCacheMiss = errors.New("key not found in cache")
+
+//go:generate mockery --name=Cache --filename=cache.go --output=../mocks --outpkg=mocks
+type Cache interface {
+	Get(ctx context.Context, key string, target any) error
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	// GetDel reads and removes the key atomically, so only one caller can
+	// consume a value.
+	GetDel(ctx context.Context, key string, target any) error
+	// AddToSet adds member to the set at key and resets the expiration of the
+	// whole set to ttl.
+	AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error
+	SetMembers(ctx context.Context, key string) ([]string, error)
+	RemoveFromSet(ctx context.Context, key string, members ...string) error
+}
+
+type redisCache struct {
+	client *redis.Client
+}
+
+func NewRedisCache(client *redis.Client) Cache {
+	return &redisCache{client: client}
+}
+
+func (r *redisCache) Get(ctx context.Context, key string, target any) error {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return ErrCacheMiss
+		}
+
+		return fmt.Errorf("get %q: %w", key, err)
+	}
+
+	return jsoniter.Unmarshal(data, target)
+}
+
+func (r *redisCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	data, err := jsoniter.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode %q: %w", key, err)
+	}
+
+	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("set %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (r *redisCache) Delete(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("delete %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (r *redisCache) GetDel(ctx context.Context, key string, target any) error {
+	data, err := r.client.GetDel(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return ErrCacheMiss
+		}
+
+		return fmt.Errorf("getdel %q: %w", key, err)
+	}
+
+	return jsoniter.Unmarshal(data, target)
+}
+
+func (r *redisCache) AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, member)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("sadd %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (r *redisCache) SetMembers(ctx context.Context, key string) ([]string, error) {
+	members, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("smembers %q: %w", key, err)
+	}
+
+	return members, nil
+}
+
+func (r *redisCache) RemoveFromSet(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	values := make([]any, len(members))
+	for i, member := range members {
+		values[i] = member
+	}
+
+	if err := r.client.SRem(ctx, key, values...).Err(); err != nil {
+		return fmt.Errorf("srem %q: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalObjectStorage(t *testing.T) {
+	objectStorage, err := NewLocalObjectStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	key := "orders/123/proof/photo.jpg"
+
+	t.Run("WhenObjectIsStored_ShouldReadItBack", func(t *testing.T) {
+		err := objectStorage.PutObject(ctx, key, strings.NewReader("jpeg-content"), int64(len("jpeg-content")), "image/jpeg")
+		assert.NoError(t, err)
+
+		object, err := objectStorage.GetObject(ctx, key)
+		assert.NoError(t, err)
+		defer object.Body.Close()
+
+		content, err := io.ReadAll(object.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg-content", string(content))
+		assert.Equal(t, "image/jpeg", object.ContentType)
+		assert.Equal(t, int64(len("jpeg-content")), object.Size)
+	})
+
+	t.Run("WhenObjectIsDeleted_ShouldReturnErrObjectNotFound", func(t *testing.T) {
+		assert.NoError(t, objectStorage.DeleteObject(ctx, key))
+
+		object, err := objectStorage.GetObject(ctx, key)
+
+		assert.Nil(t, object)
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+	})
+
+	t.Run("WhenKeyEscapesRoot_ShouldReturnErrInvalidObjectKey", func(t *testing.T) {
+		err := objectStorage.PutObject(ctx, "../outside.jpg", strings.NewReader("x"), 1, "image/jpeg")
+
+		assert.ErrorIs(t, err, ErrInvalidObjectKey)
+	})
+
+	t.Run("WhenSigningURL_ShouldReturnErrSignedURLNotSupported", func(t *testing.T) {
+		_, err := objectStorage.SignedURL(ctx, key, time.Minute)
+
+		assert.ErrorIs(t, err, ErrSignedURLNotSupported)
+	})
+}
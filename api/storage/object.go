@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+)
+
+var (
+	ErrObjectNotFound        = errors.New("object not found in storage")
+	ErrInvalidObjectKey      = errors.New("invalid object key")
+	ErrSignedURLNotSupported = errors.New("storage driver does not support signed urls")
+)
+
+const (
+	LocalDriver = "local"
+	S3Driver    = "s3"
+)
+
+//go:generate mockery --name=ObjectStorage --filename=object_storage.go --output=../mocks --outpkg=mocks
+type ObjectStorage interface {
+	PutObject(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	GetObject(ctx context.Context, key string) (*Object, error)
+	DeleteObject(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+func NewObjectStorage() (ObjectStorage, error) {
+	switch config.Env.Storage.Driver {
+	case LocalDriver, "":
+		return NewLocalObjectStorage(config.Env.Storage.LocalPath)
+	case S3Driver:
+		return NewS3ObjectStorage(S3Options{
+			Endpoint:     config.Env.S3.Endpoint,
+			Region:       config.Env.S3.Region,
+			Bucket:       config.Env.S3.Bucket,
+			AccessKey:    config.Env.S3.AccessKey,
+			SecretKey:    config.Env.S3.SecretKey,
+			UsePathStyle: config.Env.S3.UsePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", config.Env.Storage.Driver)
+	}
+}
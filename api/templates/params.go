@@ -0,0 +1,223 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrInvalidParam = errors.New("invalid email template param")
+
+// Params is implemented by the typed parameters of each email template.
+type Params interface {
+	TemplateName() TemplateName
+}
+
+// SecretParams is implemented by the parameters of templates that carry a
+// credential, such as a link with a one-time token. Redacted returns a copy
+// without it, to be stored once the email no longer needs to be sent.
+type SecretParams interface {
+	Params
+	Redacted() Params
+}
+
+type CreatedParams struct {
+	RecipientName string `json:"recipientName"`
+	OrderTitle    string `json:"orderTitle"`
+	TrackingCode  string `json:"trackingCode"`
+}
+
+type PickUpParams struct {
+	RecipientName string `json:"recipientName"`
+	TrackingCode  string `json:"trackingCode"`
+}
+
+type DeliveredParams struct {
+	RecipientName string `json:"recipientName"`
+	TrackingCode  string `json:"trackingCode"`
+	DeliveredAt   string `json:"deliveredAt"`
+}
+
+type CancelParams struct {
+	RecipientName string `json:"recipientName"`
+	TrackingCode  string `json:"trackingCode"`
+	Reason        string `json:"reason"`
+}
+
+type ReturnParams struct {
+	RecipientName string `json:"recipientName"`
+	TrackingCode  string `json:"trackingCode"`
+	Reason        string `json:"reason"`
+}
+
+type WelcomeParams struct {
+	FullName      string `json:"fullName"`
+	Role          string `json:"role"`
+	ActivationURL string `json:"activationUrl"`
+	ExpiresAt     string `json:"expiresAt"`
+}
+
+type PasswordResetParams struct {
+	FullName  string `json:"fullName"`
+	ResetURL  string `json:"resetUrl"`
+	ExpiresIn string `json:"expiresIn"`
+}
+
+type AccountLockedParams struct {
+	FullName    string `json:"fullName"`
+	Attempts    string `json:"attempts"`
+	IP          string `json:"ip"`
+	LockedUntil string `json:"lockedUntil"`
+}
+
+func (CreatedParams) TemplateName() TemplateName       { return CreatedTemplate }
+func (PickUpParams) TemplateName() TemplateName        { return PickUpTemplate }
+func (DeliveredParams) TemplateName() TemplateName     { return DeliveredTemplate }
+func (CancelParams) TemplateName() TemplateName        { return CancelTemplate }
+func (ReturnParams) TemplateName() TemplateName        { return ReturnTemplate }
+func (WelcomeParams) TemplateName() TemplateName       { return WelcomeTemplate }
+func (PasswordResetParams) TemplateName() TemplateName { return PasswordResetTemplate }
+func (AccountLockedParams) TemplateName() TemplateName { return AccountLockedTemplate }
+
+func (p WelcomeParams) Redacted() Params {
+	p.ActivationURL = ""
+	return p
+}
+
+func (p PasswordResetParams) Redacted() Params {
+	p.ResetURL = ""
+	return p
+}
+
+// registry declares every template and how to build its parameters. Each
+// entry must have a matching .html and .txt file.
+var registry = map[TemplateName]func() Params{
+	CreatedTemplate:       func() Params { return &CreatedParams{} },
+	PickUpTemplate:        func() Params { return &PickUpParams{} },
+	DeliveredTemplate:     func() Params { return &DeliveredParams{} },
+	CancelTemplate:        func() Params { return &CancelParams{} },
+	ReturnTemplate:        func() Params { return &ReturnParams{} },
+	WelcomeTemplate:       func() Params { return &WelcomeParams{} },
+	PasswordResetTemplate: func() Params { return &PasswordResetParams{} },
+	AccountLockedTemplate: func() Params { return &AccountLockedParams{} },
+}
+
+// samples holds example parameters used to preview each template.
+var samples = map[TemplateName]Params{
+	CreatedTemplate: CreatedParams{
+		RecipientName: "Maria Silva",
+		OrderTitle:    "Notebook Dell Inspiron",
+		TrackingCode:  "FF-7Q2K9X",
+	},
+	PickUpTemplate: PickUpParams{
+		RecipientName: "Maria Silva",
+		TrackingCode:  "FF-7Q2K9X",
+	},
+	DeliveredTemplate: DeliveredParams{
+		RecipientName: "Maria Silva",
+		TrackingCode:  "FF-7Q2K9X",
+		DeliveredAt:   "15/03/2025 às 14:30",
+	},
+	CancelTemplate: CancelParams{
+		RecipientName: "Maria Silva",
+		TrackingCode:  "FF-7Q2K9X",
+		Reason:        "Pedido cancelado a pedido do remetente.",
+	},
+	ReturnTemplate: ReturnParams{
+		RecipientName: "Maria Silva",
+		TrackingCode:  "FF-7Q2K9X",
+		Reason:        "Destinatário ausente após 3 tentativas de entrega.",
+	},
+	WelcomeTemplate: WelcomeParams{
+		FullName:      "João Pereira",
+		Role:          "entregador",
+		ActivationURL: "http://localhost:5173/activate?token=exemplo",
+		ExpiresAt:     "17/03/2025 às 14:30",
+	},
+	PasswordResetTemplate: PasswordResetParams{
+		FullName:  "João Pereira",
+		ResetURL:  "http://localhost:5173/reset-password?token=exemplo",
+		ExpiresIn: "30 minutos",
+	},
+	AccountLockedTemplate: AccountLockedParams{
+		FullName:    "João Pereira",
+		Attempts:    "5",
+		IP:          "203.0.113.42",
+		LockedUntil: "17/03/2025 às 14:30",
+	},
+}
+
+// Names returns the declared templates in alphabetical order.
+func Names() []TemplateName {
+	names := make([]TemplateName, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	return names
+}
+
+// HasSecrets reports whether the parameters of a template carry a credential.
+func HasSecrets(name TemplateName) bool {
+	newParams, ok := registry[name]
+	if !ok {
+		return false
+	}
+
+	_, ok = newParams().(SecretParams)
+	return ok
+}
+
+// DecodeParams rebuilds the typed parameters of a template from JSON.
+func DecodeParams(name TemplateName, data []byte) (Params, error) {
+	newParams, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+	}
+
+	params := newParams()
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, params); err != nil {
+			return nil, fmt.Errorf("decode %q params: %w", name, err)
+		}
+	}
+
+	return params, nil
+}
+
+// SampleParams returns the example parameters of a template with the given
+// fields replaced. Keys are the JSON names of the fields.
+func SampleParams(name TemplateName, overrides map[string]string) (Params, error) {
+	sample, ok := samples[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+	}
+
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return nil, fmt.Errorf("encode %q sample: %w", name, err)
+	}
+
+	fields := make(map[string]any)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("decode %q sample: %w", name, err)
+	}
+
+	for key, value := range overrides {
+		if _, ok := fields[key]; !ok {
+			return nil, fmt.Errorf("%w: %q is not a param of %q", ErrInvalidParam, key, name)
+		}
+
+		fields[key] = value
+	}
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("encode %q params: %w", name, err)
+	}
+
+	return DecodeParams(name, data)
+}
@@ -1,54 +1,107 @@
 package templates
 
 import (
+	"bytes"
+	"embed"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
 
 	"github.com/G-Villarinho/fast-feet-api/di"
 )
 
+var ErrTemplateNotFound = errors.New("email template not found")
+
 type TemplateName string
 
 const (
-	PickUpTemplate TemplateName = "pick-up-template"
+	CreatedTemplate       TemplateName = "created-template"
+	PickUpTemplate        TemplateName = "pick-up-template"
+	DeliveredTemplate     TemplateName = "delivered-template"
+	CancelTemplate        TemplateName = "cancel-template"
+	ReturnTemplate        TemplateName = "return-template"
+	WelcomeTemplate       TemplateName = "welcome-template"
+	PasswordResetTemplate TemplateName = "password-reset-template"
+	AccountLockedTemplate TemplateName = "account-locked-template"
 )
 
-//go:generate mockery --name=TemplateService --output=../mocks --outpkg=mocks
+//go:embed *.html *.txt
+var files embed.FS
+
+var funcs = map[string]any{
+	"currentYear": func() int { return time.Now().Year() },
+}
+
+type RenderedTemplate struct {
+	HTML string
+	Text string
+}
+
+//go:generate mockery --name=Template --filename=template.go --output=../mocks --outpkg=mocks
 type Template interface {
-	RenderTemplate(templateName TemplateName, params map[string]string) (string, error)
+	RenderTemplate(params Params) (*RenderedTemplate, error)
 }
 
 type templateService struct {
-	i    *di.Injector
-	path string
+	i     *di.Injector
+	html  map[TemplateName]*htmltemplate.Template
+	texts map[TemplateName]*texttemplate.Template
 }
 
+// NewTemplate compiles every declared template once. It fails when a template
+// file is missing, does not parse or references a field its parameters lack,
+// so a broken template stops the API at startup instead of at send time.
 func NewTemplate(i *di.Injector) (Template, error) {
-	dir, err := os.Getwd()
-	if err != nil {
-		return nil, err
+	t := &templateService{
+		i:     i,
+		html:  make(map[TemplateName]*htmltemplate.Template, len(registry)),
+		texts: make(map[TemplateName]*texttemplate.Template, len(registry)),
 	}
 
-	return &templateService{
-		i:    i,
-		path: filepath.Join(dir, "templates"),
-	}, nil
+	for _, name := range Names() {
+		html, err := htmltemplate.New(string(name)).Funcs(funcs).ParseFS(files, string(name)+".html")
+		if err != nil {
+			return nil, fmt.Errorf("parse %s.html: %w", name, err)
+		}
+
+		text, err := texttemplate.New(string(name)).Funcs(funcs).ParseFS(files, string(name)+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("parse %s.txt: %w", name, err)
+		}
+
+		t.html[name] = html.Lookup(string(name) + ".html")
+		t.texts[name] = text.Lookup(string(name) + ".txt")
+
+		if _, err := t.RenderTemplate(registry[name]()); err != nil {
+			return nil, fmt.Errorf("check %s: %w", name, err)
+		}
+	}
+
+	return t, nil
 }
 
-func (t *templateService) RenderTemplate(templateName TemplateName, params map[string]string) (string, error) {
-	content, err := os.ReadFile(filepath.Join(t.path, string(templateName)+".html"))
-	if err != nil {
-		return "", errors.New("read email template: " + err.Error())
+func (t *templateService) RenderTemplate(params Params) (*RenderedTemplate, error) {
+	name := params.TemplateName()
+
+	html, ok := t.html[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+	}
+
+	var htmlContent bytes.Buffer
+	if err := html.Execute(&htmlContent, params); err != nil {
+		return nil, fmt.Errorf("render %s.html: %w", name, err)
 	}
 
-	template := string(content)
-	for key, value := range params {
-		placeholder := fmt.Sprintf("#%s#", key)
-		template = strings.ReplaceAll(template, placeholder, value)
+	var textContent bytes.Buffer
+	if err := t.texts[name].Execute(&textContent, params); err != nil {
+		return nil, fmt.Errorf("render %s.txt: %w", name, err)
 	}
 
-	return template, nil
+	return &RenderedTemplate{
+		HTML: htmlContent.String(),
+		Text: textContent.String(),
+	}, nil
 }
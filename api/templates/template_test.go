@@ -0,0 +1,107 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTemplate(t *testing.T) {
+	t.Run("ShouldCompileEveryDeclaredTemplate", func(t *testing.T) {
+		tmpl, err := NewTemplate(nil)
+
+		assert.NoError(t, err)
+
+		for _, name := range Names() {
+			params, err := DecodeParams(name, nil)
+			assert.NoError(t, err)
+
+			rendered, err := tmpl.RenderTemplate(params)
+			assert.NoError(t, err, name)
+			assert.NotEmpty(t, rendered.HTML, name)
+			assert.NotEmpty(t, rendered.Text, name)
+		}
+	})
+}
+
+func TestTemplateService_RenderTemplate(t *testing.T) {
+	tmpl, err := NewTemplate(nil)
+	assert.NoError(t, err)
+
+	t.Run("ShouldEscapeParamsInHTML", func(t *testing.T) {
+		rendered, err := tmpl.RenderTemplate(PickUpParams{
+			RecipientName: `<script>alert("x")</script>`,
+			TrackingCode:  "abc-123",
+		})
+
+		assert.NoError(t, err)
+		assert.NotContains(t, rendered.HTML, "<script>")
+		assert.Contains(t, rendered.HTML, "&lt;script&gt;")
+		assert.Contains(t, rendered.HTML, "abc-123")
+	})
+
+	t.Run("ShouldRenderPlaintextAlternative", func(t *testing.T) {
+		rendered, err := tmpl.RenderTemplate(CancelParams{
+			RecipientName: "Maria",
+			TrackingCode:  "abc-123",
+			Reason:        "Endereço incorreto",
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, rendered.Text, "Olá Maria,")
+		assert.Contains(t, rendered.Text, "Motivo do cancelamento: Endereço incorreto")
+		assert.NotContains(t, rendered.Text, "<")
+	})
+}
+
+func TestDecodeParams(t *testing.T) {
+	t.Run("ShouldDecodeTypedParams", func(t *testing.T) {
+		params, err := DecodeParams(ReturnTemplate, []byte(`{"recipientName":"Maria","reason":"Ausente"}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ReturnParams{RecipientName: "Maria", Reason: "Ausente"}, params)
+	})
+
+	t.Run("WhenTemplateIsUnknown_ShouldReturnErrTemplateNotFound", func(t *testing.T) {
+		params, err := DecodeParams("unknown-template", nil)
+
+		assert.Nil(t, params)
+		assert.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+}
+
+func TestSampleParams(t *testing.T) {
+	t.Run("ShouldDeclareSampleForEveryTemplate", func(t *testing.T) {
+		for _, name := range Names() {
+			params, err := SampleParams(name, nil)
+
+			assert.NoError(t, err, name)
+			assert.Equal(t, name, params.TemplateName())
+		}
+	})
+
+	t.Run("ShouldOverrideSuppliedFields", func(t *testing.T) {
+		params, err := SampleParams(CancelTemplate, map[string]string{"reason": "Endereço inexistente"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &CancelParams{
+			RecipientName: "Maria Silva",
+			TrackingCode:  "FF-7Q2K9X",
+			Reason:        "Endereço inexistente",
+		}, params)
+	})
+
+	t.Run("WhenFieldIsUnknown_ShouldReturnErrInvalidParam", func(t *testing.T) {
+		params, err := SampleParams(PickUpTemplate, map[string]string{"reason": "x"})
+
+		assert.Nil(t, params)
+		assert.ErrorIs(t, err, ErrInvalidParam)
+	})
+
+	t.Run("WhenTemplateIsUnknown_ShouldReturnErrTemplateNotFound", func(t *testing.T) {
+		params, err := SampleParams("unknown-template", nil)
+
+		assert.Nil(t, params)
+		assert.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+}
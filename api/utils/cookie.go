@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/G-Villarinho/fast-feet-api/config"
+)
+
+// NewCookie builds a cookie with the Domain, Secure and SameSite attributes
+// configured for the session. A negative maxAge expires the cookie.
+func NewCookie(name, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   config.Env.Session.CookieDomain,
+		MaxAge:   maxAge,
+		Secure:   config.Env.Session.CookieSecure,
+		HttpOnly: httpOnly,
+		SameSite: sameSiteMode(config.Env.Session.CookieSameSite),
+	}
+
+	if maxAge < 0 {
+		cookie.Expires = time.Unix(0, 0)
+	}
+
+	return cookie
+}
+
+func ExpiredCookie(name, path string, httpOnly bool) *http.Cookie {
+	return NewCookie(name, "", path, -1, httpOnly)
+}
+
+func sameSiteMode(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrient(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	// A 2x1 image: red on the left, blue on the right.
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, red)
+	src.Set(1, 0, blue)
+
+	t.Run("WhenOrientationIs6_ShouldRotateClockwise", func(t *testing.T) {
+		dst := Orient(src, 6)
+
+		assert.Equal(t, image.Rect(0, 0, 1, 2), dst.Bounds())
+		assert.Equal(t, red, dst.NRGBAAt(0, 0))
+		assert.Equal(t, blue, dst.NRGBAAt(0, 1))
+	})
+
+	t.Run("WhenOrientationIs8_ShouldRotateCounterClockwise", func(t *testing.T) {
+		dst := Orient(src, 8)
+
+		assert.Equal(t, image.Rect(0, 0, 1, 2), dst.Bounds())
+		assert.Equal(t, blue, dst.NRGBAAt(0, 0))
+		assert.Equal(t, red, dst.NRGBAAt(0, 1))
+	})
+
+	t.Run("WhenOrientationIs3_ShouldRotate180", func(t *testing.T) {
+		dst := Orient(src, 3)
+
+		assert.Equal(t, blue, dst.NRGBAAt(0, 0))
+		assert.Equal(t, red, dst.NRGBAAt(1, 0))
+	})
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	t.Run("WhenDataIsNotJPEG_ShouldReturnDefault", func(t *testing.T) {
+		assert.Equal(t, 1, JPEGOrientation([]byte("\x89PNG\r\n\x1a\n")))
+	})
+
+	t.Run("WhenSegmentIsTruncated_ShouldReturnDefault", func(t *testing.T) {
+		assert.Equal(t, 1, JPEGOrientation([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x40, 'E', 'x'}))
+	})
+}
@@ -56,7 +56,28 @@ func GetQueryStringPointer(value string) *string {
 	return &value
 }
 
+func DerefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
 func RemoveCPFFormat(cpf string) string {
 	re := regexp.MustCompile(`\D`)
 	return re.ReplaceAllString(cpf, "")
 }
+
+func MaskName(fullName string) string {
+	names := strings.Fields(fullName)
+	if len(names) == 0 {
+		return ""
+	}
+
+	if len(names) == 1 {
+		return names[0]
+	}
+
+	lastName := []rune(names[len(names)-1])
+	return names[0] + " " + strings.ToUpper(string(lastName[0])) + "."
+}
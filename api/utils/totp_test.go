@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors ("12345678901234567890").
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// The RFC vectors have eight digits; the six-digit code is their suffix.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(unix, 0)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	t.Run("WhenCodeIsFromPreviousStep_ShouldAcceptWithinSkew", func(t *testing.T) {
+		code, _ := TOTPCode(rfcSecret, TOTPStep(now)-1)
+
+		step, ok := ValidateTOTP(rfcSecret, code, now, 1)
+
+		assert.True(t, ok)
+		assert.Equal(t, TOTPStep(now)-1, step)
+	})
+
+	t.Run("WhenCodeIsOutsideSkew_ShouldReject", func(t *testing.T) {
+		code, _ := TOTPCode(rfcSecret, TOTPStep(now)-2)
+
+		_, ok := ValidateTOTP(rfcSecret, code, now, 1)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("WhenCodeHasWrongLength_ShouldReject", func(t *testing.T) {
+		_, ok := ValidateTOTP(rfcSecret, "12345", now, 1)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("Fast Feet", "john@example.com", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Fast Feet:john@example.com", parsed.Path)
+	assert.Equal(t, rfcSecret, parsed.Query().Get("secret"))
+	assert.Equal(t, "Fast Feet", parsed.Query().Get("issuer"))
+}
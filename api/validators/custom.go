@@ -1,11 +1,14 @@
 package validators
 
 import (
+	"unicode"
+
 	"github.com/go-playground/validator/v10"
 )
 
 const (
-	CPFTag = "cpf"
+	CPFTag      = "cpf"
+	PasswordTag = "password"
 )
 
 func SetupCustomValidations(validator *validator.Validate) error {
@@ -13,6 +16,10 @@ func SetupCustomValidations(validator *validator.Validate) error {
 		return err
 	}
 
+	if err := validator.RegisterValidation(PasswordTag, passwordValidator); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -71,3 +78,27 @@ func cpfValidator(fl validator.FieldLevel) bool {
 
 	return true
 }
+
+// passwordValidator enforces the password policy: 8 to 72 characters (bcrypt
+// ignores anything past 72 bytes) with upper and lower case letters and digits.
+func passwordValidator(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+
+	if len(password) < 8 || len(password) > 72 {
+		return false
+	}
+
+	var hasUpper, hasLower, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	return hasUpper && hasLower && hasDigit
+}
@@ -36,7 +36,7 @@ func ValidateStruct(s any) ValidationErrors {
 }
 
 func getErrorMessage(err validator.FieldError) string {
-	if err.Tag() == "min" || err.Tag() == "max" {
+	if err.Tag() == "min" || err.Tag() == "max" || err.Tag() == "oneof" {
 		param := err.Param()
 		return strings.Replace(ValidationMessages[err.Tag()], "{0}", param, 1)
 	}